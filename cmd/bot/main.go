@@ -1,12 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
 
 	"github.com/rymax1e/open-cashback-advisor/internal/bot"
+	"github.com/rymax1e/open-cashback-advisor/internal/config"
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
 )
 
 func main() {
@@ -22,26 +25,67 @@ func main() {
 	apiClient := bot.NewAPIClient(cfg.APIBaseURL)
 	log.Printf("✅ API клиент создан: %s", cfg.APIBaseURL)
 
+	// Создание хранилища состояний диалогов
+	states, closeStates := newStateStore(cfg)
+	defer closeStates()
+
 	// Создание бота
-	telegramBot, err := bot.NewBot(cfg.TelegramToken, apiClient, cfg.Debug)
+	telegramBot, err := bot.NewBot(cfg, apiClient, states, newOCREngine(cfg))
 	if err != nil {
 		log.Fatalf("❌ Не удалось создать бота: %v", err)
 	}
 
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	// Graceful shutdown: по сигналу бот перестаёт принимать обновления
+	// и дожидается завершения уже запущенных обработчиков
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	go func() {
-		<-quit
-		log.Println("\n⚠️  Получен сигнал остановки бота...")
-		os.Exit(0)
+		<-ctx.Done()
+		log.Println("\n⚠️  Получен сигнал остановки бота, завершаю обработку...")
 	}()
 
 	logStartupInfo()
 
-	// Запуск бота
-	telegramBot.Start()
+	// Запуск бота (блокируется до остановки)
+	if cfg.Mode == bot.ModeWebhook {
+		if err := telegramBot.StartWebhook(ctx, cfg.Webhook); err != nil {
+			log.Fatalf("❌ Ошибка webhook: %v", err)
+		}
+		return
+	}
+	telegramBot.Start(ctx)
+}
+
+// newStateStore создаёт хранилище состояний согласно конфигурации.
+// Возвращает функцию освобождения ресурсов хранилища.
+func newStateStore(cfg *bot.Config) (bot.StateStore, func()) {
+	if cfg.StateStore != bot.StateStorePostgres {
+		log.Printf("✅ Состояния диалогов хранятся в памяти (TTL %s)", cfg.StateTTL)
+		return bot.NewMemoryStateStore(cfg.StateTTL), func() {}
+	}
+
+	dbCfg := config.Load()
+	db, err := database.New(context.Background(), dbCfg.Database.ConnectionString())
+	if err != nil {
+		log.Fatalf("❌ Не удалось подключиться к базе данных состояний: %v", err)
+	}
+
+	log.Printf("✅ Состояния диалогов хранятся в PostgreSQL (TTL %s)", cfg.StateTTL)
+	return bot.NewPostgresStateStore(db.Pool, cfg.StateTTL), db.Close
+}
+
+// newOCREngine создаёт движок распознавания скриншотов.
+// Если tesseract не установлен, распознавание отключается.
+func newOCREngine(cfg *bot.Config) bot.OCREngine {
+	engine, err := bot.NewTesseractEngine(cfg.TesseractPath, cfg.OCRLanguages)
+	if err != nil {
+		log.Printf("⚠️ Распознавание скриншотов отключено: %v", err)
+		return nil
+	}
+
+	log.Printf("✅ Распознавание скриншотов включено (языки: %s)", cfg.OCRLanguages)
+	return engine
 }
 
 // logStartupInfo выводит информацию о запуске.
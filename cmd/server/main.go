@@ -8,6 +8,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // часовые пояса для DIGEST_TZ в образе без tzdata
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -47,6 +48,11 @@ func main() {
 	// Создание зависимостей
 	repo := database.NewRepository(db)
 	svc := service.NewService(repo)
+	digestLocation, err := cfg.Digest.Location()
+	if err != nil {
+		log.Fatalf("❌ Ошибка конфигурации: %v", err)
+	}
+	svc.SetDigestLocation(digestLocation)
 	handler := handlers.NewHandler(svc)
 
 	// Настройка и запуск сервера
@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// activationPattern находит в тексте правила требование активации:
+// "активация", "активировать до 05.12" или "активация до 05.12.2025".
+var activationPattern = regexp.MustCompile(`(?i)актив\p{L}*(?:\s+до\s+(\d{1,2})\.(\d{1,2})(?:\.(\d{4}))?)?`)
+
+// extractActivation вынимает из текста требование активации и возвращает
+// его вместе с остатком текста. Срок без года относится к ближайшей
+// такой дате, начиная с сегодняшней.
+func extractActivation(text string, now time.Time) (*models.ActivationRequest, string) {
+	match := activationPattern.FindStringSubmatch(text)
+	if match == nil {
+		return nil, text
+	}
+
+	activation := &models.ActivationRequest{}
+	if match[1] != "" {
+		day, _ := strconv.Atoi(match[1])
+		month, _ := strconv.Atoi(match[2])
+		year, _ := strconv.Atoi(match[3])
+		if year == 0 {
+			year = now.Year()
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			if time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).Before(today) {
+				year++
+			}
+		}
+		activation.Deadline = fmt.Sprintf("%02d.%02d.%04d", day, month, year)
+	}
+
+	return activation, strings.Replace(text, match[0], " ", 1)
+}
+
+// formatActivationLine предупреждает, что категорию правила ещё не включили
+// в приложении банка и кэшбэк по ней не начислят.
+func formatActivationLine(rule *models.CashbackRule, indent string) string {
+	if !rule.Activation.Pending() {
+		return ""
+	}
+
+	text := fmt.Sprintf("\n%s⚠️ Не активирован в приложении банка", indent)
+	if rule.Activation.Deadline != nil {
+		text += " — до " + rule.Activation.Deadline.Format("02.01")
+	}
+	return text
+}
+
+// activationButtons возвращает кнопку отметки об активации правила.
+func activationButtons(rule *models.CashbackRule) [][]inlineButton {
+	return [][]inlineButton{{
+		{Text: BtnActivated, Action: CallbackActivateRule, Payload: strconv.FormatInt(rule.ID, 10)},
+	}}
+}
+
+// sendSavedCashback отправляет сохранённое правило. Если категорию нужно
+// активировать в приложении банка, под сообщением появляется кнопка
+// отметки об активации.
+func (b *Bot) sendSavedCashback(chatID, userID int64, rule *models.CashbackRule) {
+	if !rule.Activation.Pending() {
+		b.sendText(chatID, formatSavedCashback(rule))
+		return
+	}
+	b.sendWithInlineButtons(chatID, userID, formatSavedCashback(rule), activationButtons(rule))
+}
+
+// applyActivation отмечает правило активированным по нажатию кнопки.
+func (b *Bot) applyActivation(callback *tgbotapi.CallbackQuery, ruleID int64) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	_, err := b.client.ActivateCashback(ruleID, strconv.FormatInt(callback.From.ID, 10))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRuleNotFound):
+			b.answerCallback(callback.ID, "Этого кэшбэка уже нет")
+			b.editMessage(chatID, messageID, callback.Message.Text)
+		case errors.Is(err, ErrNotRuleOwner):
+			b.answerCallback(callback.ID, "Отметить активацию может только владелец карты")
+		default:
+			log.Printf("❌ Ошибка отметки об активации правила %d: %v", ruleID, err)
+			b.answerCallback(callback.ID, "❌ Не удалось сохранить отметку")
+		}
+		return
+	}
+
+	b.answerCallback(callback.ID, "")
+	b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+BtnActivated)
+}
+
+// runActivationReminders периодически напоминает владельцам правил
+// активировать категории, срок активации которых скоро наступит.
+func (b *Bot) runActivationReminders(ctx context.Context) {
+	ticker := time.NewTicker(ActivationCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		b.sendActivationReminders()
+	}
+}
+
+// sendActivationReminders отправляет владельцам напоминания об активации.
+// Каждое напоминание уходит один раз: правило сначала отмечается в API.
+func (b *Bot) sendActivationReminders() {
+	rules, err := b.client.ListDueActivations()
+	if err != nil {
+		log.Printf("❌ Ошибка получения правил для напоминания об активации: %v", err)
+		return
+	}
+
+	for _, due := range rules {
+		rule, err := b.client.ClaimActivationReminder(due.ID)
+		if err != nil {
+			if !errors.Is(err, ErrReminderAlreadySent) {
+				log.Printf("❌ Ошибка отметки о напоминании по правилу %d: %v", due.ID, err)
+			}
+			continue
+		}
+
+		ownerID, err := strconv.ParseInt(rule.UserID, 10, 64)
+		if err != nil {
+			continue
+		}
+		if err := b.sendWithButtonsTo(ownerID, formatActivationReminder(rule), activationButtons(rule)); err != nil {
+			log.Printf("⚠️ Не удалось напомнить об активации правила %d: %v", rule.ID, err)
+			continue
+		}
+		log.Printf("⏰ Отправлено напоминание об активации правила %d", rule.ID)
+	}
+}
+
+// formatActivationReminder форматирует напоминание об активации категории.
+func formatActivationReminder(rule *models.CashbackRule) string {
+	text := fmt.Sprintf("⏰ Не забудьте активировать категорию \"%s\" в приложении банка %s — иначе кэшбэк %s не начислят.",
+		rule.Category, rule.BankName, formatRewardPercent(rule))
+	if rule.Activation.Deadline != nil {
+		text += fmt.Sprintf("\n\n📅 Активировать нужно до %s.", rule.Activation.Deadline.Format("02.01.2006"))
+	}
+	return text + "\n\nКогда активируете, нажмите кнопку ниже."
+}
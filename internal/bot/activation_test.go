@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestExtractActivation(t *testing.T) {
+	now := time.Date(2025, time.November, 20, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input        string
+		wantFound    bool
+		wantDeadline string
+		wantRest     string
+	}{
+		{"активация", true, "", ""},
+		{"Активировать до 05.12", true, "05.12.2025", ""},
+		{"активация до 20.11", true, "20.11.2025", ""},
+		{"активация до 10.01", true, "10.01.2026", ""},
+		{"31.12.2025 активация до 05.12.2025", true, "05.12.2025", "31.12.2025"},
+		{"выходные", false, "", "выходные"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			activation, rest := extractActivation(tt.input, now)
+			if (activation != nil) != tt.wantFound {
+				t.Fatalf("extractActivation() = %+v, ожидалось найдено = %v", activation, tt.wantFound)
+			}
+			if activation != nil && activation.Deadline != tt.wantDeadline {
+				t.Errorf("extractActivation() срок = %q, ожидалось %q", activation.Deadline, tt.wantDeadline)
+			}
+			if strings.TrimSpace(rest) != tt.wantRest {
+				t.Errorf("extractActivation() остаток = %q, ожидалось %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestParseMessageActivation(t *testing.T) {
+	data, err := ParseMessage("Альфа, Такси, 5, 3000, активация")
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if data.Activation == nil || data.MonthYear == "" {
+		t.Errorf("ParseMessage() = активация %+v, дата %q", data.Activation, data.MonthYear)
+	}
+
+	data, err = ParseMessage("Альфа, Такси, 5, 3000, 31.12.2099, активировать до 05.12.2099, выходные")
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if data.Activation == nil || data.Activation.Deadline != "05.12.2099" {
+		t.Errorf("ParseMessage() активация = %+v", data.Activation)
+	}
+	if data.MonthYear != "31.12.2099" || len(data.Conditions.Weekdays) == 0 {
+		t.Errorf("ParseMessage() дата %q, условия %+v", data.MonthYear, data.Conditions)
+	}
+}
+
+func TestFormatActivationLine(t *testing.T) {
+	deadline := time.Date(2025, time.December, 5, 0, 0, 0, 0, time.UTC)
+	rule := &models.CashbackRule{Activation: models.RuleActivation{Required: true, Deadline: &deadline}}
+
+	if got := formatActivationLine(rule, ""); got != "\n⚠️ Не активирован в приложении банка — до 05.12" {
+		t.Errorf("formatActivationLine() = %q", got)
+	}
+
+	rule.Activation.ActivatedAt = &deadline
+	if got := formatActivationLine(rule, ""); got != "" {
+		t.Errorf("formatActivationLine() для активированного правила = %q", got)
+	}
+}
+
+func TestActivationButtonFitsCallbackLimit(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+	rule := &models.CashbackRule{ID: math.MaxInt64}
+
+	button := activationButtons(rule)[0][0]
+	if _, err := codec.Encode(button.Action, button.Payload, math.MaxInt64); err != nil {
+		t.Errorf("Encode() error = %v", err)
+	}
+	if button.Payload != strconv.FormatInt(math.MaxInt64, 10) {
+		t.Errorf("activationButtons() payload = %q", button.Payload)
+	}
+}
@@ -0,0 +1,134 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// parseSpendingArgs разбирает траты по категориям: "Аптеки 5000, Такси 3000".
+func parseSpendingArgs(args string) (map[string]float64, error) {
+	spending := make(map[string]float64)
+
+	for _, part := range strings.Split(args, ",") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return nil, NewParseError("траты", fmt.Sprintf("укажите сумму для \"%s\"", strings.TrimSpace(part)))
+		}
+
+		amountStr := strings.TrimSuffix(strings.TrimSuffix(fields[len(fields)-1], "₽"), "р")
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(amountStr, ",", "."), 64)
+		if err != nil || amount <= 0 {
+			return nil, NewParseError("траты", fmt.Sprintf("сумма \"%s\" должна быть положительным числом", fields[len(fields)-1]))
+		}
+
+		spending[strings.Join(fields[:len(fields)-1], " ")] += amount
+	}
+
+	return spending, nil
+}
+
+// handleAdvice обрабатывает команду /advice [Категория сумма, ...].
+func (b *Bot) handleAdvice(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName, err := b.client.GetUserGroup(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	now := time.Now()
+	req := &models.CategoryAdviceRequest{MonthYear: fmt.Sprintf("%d-%02d", now.Year(), now.Month())}
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		spending, err := parseSpendingArgs(args)
+		if err != nil {
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ %s\n\nНапример: /advice Аптеки 5000, Такси 3000", err))
+			return
+		}
+		req.Spending = spending
+	}
+
+	advice, err := b.client.AdviseCategories(groupName, req)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось подобрать категории: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatCategoryAdvice(advice, userIDStr))
+}
+
+// formatCategoryAdvice форматирует совет группе по выбору категорий.
+// Совет пользователю userID показывается первым.
+func formatCategoryAdvice(resp *models.CategoryAdviceResponse, userID string) string {
+	month := resp.MonthYear.Format("01.2006")
+	if len(resp.Advice) == 0 {
+		return fmt.Sprintf("🧭 На %s нет меню категорий банков ваших карт.\n\n"+
+			"Меню добавляются через API: PUT /api/v1/offer-menus/{банк}", month)
+	}
+
+	advice := make([]models.MemberAdvice, 0, len(resp.Advice))
+	for _, a := range resp.Advice {
+		if a.UserID == userID {
+			advice = append(advice, a)
+		}
+	}
+	for _, a := range resp.Advice {
+		if a.UserID != userID {
+			advice = append(advice, a)
+		}
+	}
+
+	text := fmt.Sprintf("🧭 Какие категории выбрать на %s\n", month)
+	if resp.WeightSource == models.WeightSourceSpending {
+		text += "📊 С учётом ваших трат\n"
+	} else {
+		text += "📊 С учётом категорий, которые группа выбирала раньше\n"
+	}
+
+	for _, a := range advice {
+		who := a.UserDisplayName
+		if who == "" {
+			who = a.UserID
+		}
+		if a.UserID == userID {
+			who = "Вы"
+		}
+
+		text += fmt.Sprintf("\n👤 %s — %s (выбрать %d):\n", who, a.BankName, a.PickCount)
+		if len(a.AlreadyChosen) > 0 {
+			text += fmt.Sprintf("   ✅ Уже выбрано: %s\n", strings.Join(a.AlreadyChosen, ", "))
+		}
+		if len(a.Picks) == 0 && len(a.AlreadyChosen) > 0 {
+			text += "   Все категории уже выбраны\n"
+		}
+		for i, pick := range a.Picks {
+			text += fmt.Sprintf("   %d. %s — %.1f%%%s\n", i+1, pick.Category, pick.CashbackPercent, formatPickReason(pick))
+		}
+	}
+
+	if resp.WeightSource == models.WeightSourceSpending && resp.TotalGain > 0 {
+		text += fmt.Sprintf("\n💰 Группа получит дополнительно около %.0f₽ в месяц", resp.TotalGain)
+	}
+
+	return strings.TrimRight(text, "\n")
+}
+
+// formatPickReason объясняет, чем полезен выбор категории группе.
+func formatPickReason(pick models.CategoryPick) string {
+	switch {
+	case pick.NewCoverage:
+		return " 🆕 в группе ни у кого нет"
+	case pick.CashbackPercent > pick.CurrentPercent:
+		return fmt.Sprintf(" ⬆️ сейчас в группе %.1f%%", pick.CurrentPercent)
+	default:
+		return fmt.Sprintf(" (в группе уже есть %.1f%%)", pick.CurrentPercent)
+	}
+}
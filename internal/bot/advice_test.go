@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestParseSpendingArgs(t *testing.T) {
+	spending, err := parseSpendingArgs("Аптеки 5000, Доставка еды 3000₽")
+	if err != nil {
+		t.Fatalf("parseSpendingArgs() error = %v", err)
+	}
+	if spending["Аптеки"] != 5000 || spending["Доставка еды"] != 3000 {
+		t.Errorf("parseSpendingArgs() = %v", spending)
+	}
+
+	for _, args := range []string{"Аптеки", "Аптеки много", "Такси -5"} {
+		if _, err := parseSpendingArgs(args); err == nil {
+			t.Errorf("parseSpendingArgs(%q) ожидалась ошибка", args)
+		}
+	}
+}
+
+func TestFormatCategoryAdviceShowsOwnAdviceFirst(t *testing.T) {
+	resp := &models.CategoryAdviceResponse{
+		MonthYear:    time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		WeightSource: models.WeightSourceSpending,
+		TotalGain:    450,
+		Advice: []models.MemberAdvice{
+			{UserID: "1", UserDisplayName: "Иван", BankName: "Тинькофф", PickCount: 1,
+				Picks: []models.CategoryPick{{Category: "Такси", CashbackPercent: 5, CurrentPercent: 3}}},
+			{UserID: "2", UserDisplayName: "Мария", BankName: "Альфа", PickCount: 2,
+				AlreadyChosen: []string{"Кафе"},
+				Picks:         []models.CategoryPick{{Category: "Аптеки", CashbackPercent: 5, NewCoverage: true}}},
+		},
+	}
+
+	text := formatCategoryAdvice(resp, "2")
+	if strings.Index(text, "Вы — Альфа") > strings.Index(text, "Иван — Тинькофф") {
+		t.Errorf("Совет пользователю должен быть первым: %q", text)
+	}
+	for _, want := range []string{"Уже выбрано: Кафе", "Аптеки — 5.0% 🆕", "сейчас в группе 3.0%", "450₽"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatCategoryAdvice() не содержит %q: %q", want, text)
+		}
+	}
+}
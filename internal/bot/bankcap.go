@@ -0,0 +1,203 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// bankCapPeriods сопоставляет слова периода в командах с периодами лимита.
+var bankCapPeriods = map[string]string{
+	"месяц":   models.BankCapPeriodMonth,
+	"мес":     models.BankCapPeriodMonth,
+	"квартал": models.BankCapPeriodQuarter,
+	"кв":      models.BankCapPeriodQuarter,
+}
+
+// handleBankCap обрабатывает команду /bankcap: без аргументов показывает
+// лимиты, "Банк 5000 [месяц|квартал]" задаёт лимит, "Банк выкл" удаляет.
+func (b *Bot) handleBankCap(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		caps, err := b.client.ListBankCaps(userIDStr)
+		if err != nil {
+			log.Printf("❌ Ошибка получения лимитов банков %s: %v", userIDStr, err)
+			b.sendText(message.Chat.ID, "❌ Не удалось получить лимиты банков")
+			return
+		}
+		b.sendText(message.Chat.ID, formatBankCaps(caps))
+		return
+	}
+
+	bankName, req, err := parseBankCapArgs(args)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ %v\n\nПример: /bankcap Тинькофф 5000, /bankcap Альфа 9000 квартал или /bankcap Тинькофф выкл", err))
+		return
+	}
+
+	if req == nil {
+		if err := b.client.DeleteBankCap(userIDStr, bankName); err != nil {
+			if errors.Is(err, ErrBankCapNotFound) {
+				b.sendText(message.Chat.ID, fmt.Sprintf("❌ Лимита для банка %s нет", bankName))
+				return
+			}
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось удалить лимит: %v", err))
+			return
+		}
+		b.sendText(message.Chat.ID, fmt.Sprintf("✅ Лимит банка %s удалён", bankName))
+		return
+	}
+
+	bankCap, err := b.client.SetBankCap(userIDStr, bankName, req)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось сохранить лимит: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, fmt.Sprintf("✅ Лимит банка %s: %s %s на все категории.\n\n"+
+		"Отмечайте покупки командой /spent, чтобы бот считал заработанный кэшбэк.",
+		bankCap.BankName, formatRubles(bankCap.Amount), formatCapPeriod(bankCap.Period)))
+}
+
+// handleSpent обрабатывает команду /spent Сумма Категория: записывает покупку
+// по своей карте с лучшим кэшбэком на категорию.
+func (b *Bot) handleSpent(message *tgbotapi.Message) {
+	groupName := b.getUserGroup(message.From.ID)
+	if groupName == "" {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	amount, category, err := parseSpendArgs(message.CommandArguments())
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ %v\n\nПример: /spent 1500 Такси", err))
+		return
+	}
+
+	resp, err := b.client.RecordSpend(&models.CreateSpendRequest{
+		GroupName: groupName,
+		UserID:    strconv.FormatInt(message.From.ID, 10),
+		Category:  category,
+		Amount:    amount,
+	})
+	if err != nil {
+		log.Printf("❌ Ошибка записи траты: %v", err)
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось записать покупку: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatSpend(resp))
+}
+
+// parseBankCapArgs разбирает аргументы /bankcap: "Банк 5000 [месяц|квартал]"
+// или "Банк выкл". Для удаления лимита возвращает nil вместо запроса.
+func parseBankCapArgs(args string) (string, *models.BankCapRequest, error) {
+	fields := strings.Fields(args)
+	last := strings.ToLower(fields[len(fields)-1])
+
+	if last == "выкл" || last == "off" {
+		bankName := strings.Join(fields[:len(fields)-1], " ")
+		if bankName == "" {
+			return "", nil, fmt.Errorf("укажите банк")
+		}
+		return bankName, nil, nil
+	}
+
+	req := &models.BankCapRequest{Period: models.BankCapPeriodMonth}
+	if period, ok := bankCapPeriods[last]; ok {
+		req.Period = period
+		fields = fields[:len(fields)-1]
+	}
+
+	if len(fields) < 2 {
+		return "", nil, fmt.Errorf("укажите банк и лимит в рублях")
+	}
+
+	amount, err := parsePaymentAmount(fields[len(fields)-1])
+	if err != nil {
+		return "", nil, err
+	}
+	req.Amount = amount
+
+	return strings.Join(fields[:len(fields)-1], " "), req, nil
+}
+
+// parseSpendArgs разбирает аргументы /spent: "1500 Такси".
+func parseSpendArgs(args string) (float64, string, error) {
+	fields := strings.Fields(args)
+	if len(fields) < 2 {
+		return 0, "", fmt.Errorf("укажите сумму и категорию покупки")
+	}
+
+	amount, err := parsePaymentAmount(fields[0])
+	if err != nil {
+		return 0, "", err
+	}
+	return amount, strings.Join(fields[1:], " "), nil
+}
+
+// formatBankCaps форматирует лимиты банков пользователя с остатком за период.
+func formatBankCaps(caps []models.BankCapUsage) string {
+	if len(caps) == 0 {
+		return "🏦 Общих лимитов банков нет.\n\n" +
+			"Если банк ограничивает кэшбэк на все категории сразу, задайте лимит: /bankcap Тинькофф 5000"
+	}
+
+	text := "🏦 Общие лимиты кэшбэка банков:\n"
+	for i := range caps {
+		usage := &caps[i]
+		mark := "✅"
+		switch {
+		case usage.Exhausted():
+			mark = "⛔"
+		case usage.NearlyUsed():
+			mark = "⚠️"
+		}
+		text += fmt.Sprintf("\n%s %s: %s из %s %s, осталось %s",
+			mark, usage.BankName, formatRubles(usage.Earned), formatRubles(usage.Amount),
+			formatCapPeriod(usage.Period), formatRubles(usage.Remaining))
+	}
+	return text
+}
+
+// formatSpend форматирует записанную покупку и состояние лимита банка.
+func formatSpend(resp *models.SpendResponse) string {
+	text := fmt.Sprintf("🧾 Покупка записана: %s на \"%s\" картой %s, кэшбэк %s",
+		formatRubles(resp.Amount), resp.Category, resp.BankName, formatRubles(resp.Cashback))
+
+	if usage := resp.BankCap; usage != nil {
+		text += fmt.Sprintf("\n\n🏦 Лимит банка: %s из %s %s",
+			formatRubles(usage.Earned), formatRubles(usage.Amount), formatCapPeriod(usage.Period))
+		text += formatBankCapLine(usage, "")
+	}
+	return text
+}
+
+// formatBankCapLine предупреждает, что общий лимит банка владельца карты
+// почти или полностью исчерпан.
+func formatBankCapLine(usage *models.BankCapUsage, indent string) string {
+	switch {
+	case usage.Exhausted():
+		return fmt.Sprintf("\n%s⛔ Общий лимит банка исчерпан: кэшбэк не начислят до конца периода", indent)
+	case usage.NearlyUsed():
+		return fmt.Sprintf("\n%s⚠️ Лимит банка почти исчерпан: осталось %s из %s",
+			indent, formatRubles(usage.Remaining), formatRubles(usage.Amount))
+	}
+	return ""
+}
+
+// formatCapPeriod возвращает период лимита словами.
+func formatCapPeriod(period string) string {
+	if period == models.BankCapPeriodQuarter {
+		return "в квартал"
+	}
+	return "в месяц"
+}
@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestParseBankCapArgs(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantBank   string
+		wantAmount float64
+		wantPeriod string
+		wantRemove bool
+		wantErr    bool
+	}{
+		{"Тинькофф 5000", "Тинькофф", 5000, models.BankCapPeriodMonth, false, false},
+		{"Альфа Банк 9000₽ квартал", "Альфа Банк", 9000, models.BankCapPeriodQuarter, false, false},
+		{"Тинькофф 3000 месяц", "Тинькофф", 3000, models.BankCapPeriodMonth, false, false},
+		{"Тинькофф выкл", "Тинькофф", 0, "", true, false},
+		{"выкл", "", 0, "", false, true},
+		{"5000", "", 0, "", false, true},
+		{"Тинькофф много", "", 0, "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			bank, req, err := parseBankCapArgs(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBankCapArgs(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if bank != tt.wantBank || (req == nil) != tt.wantRemove {
+				t.Fatalf("parseBankCapArgs(%q) = %q, %+v", tt.input, bank, req)
+			}
+			if req != nil && (req.Amount != tt.wantAmount || req.Period != tt.wantPeriod) {
+				t.Errorf("parseBankCapArgs(%q) = %+v", tt.input, req)
+			}
+		})
+	}
+}
+
+func TestParseSpendArgs(t *testing.T) {
+	amount, category, err := parseSpendArgs("820,50 Доставка еды")
+	if err != nil || amount != 820.5 || category != "Доставка еды" {
+		t.Errorf("parseSpendArgs() = %v, %q, %v", amount, category, err)
+	}
+
+	if _, _, err := parseSpendArgs("1500"); err == nil {
+		t.Error("parseSpendArgs() без категории: ожидалась ошибка")
+	}
+}
+
+func TestFormatBankCapLine(t *testing.T) {
+	usage := &models.BankCapUsage{
+		BankCap: models.BankCap{BankName: "Тинькофф", Period: models.BankCapPeriodMonth, Amount: 5000},
+		Earned:  4200, Remaining: 800,
+	}
+	if line := formatBankCapLine(usage, ""); !strings.Contains(line, "почти исчерпан: осталось 800₽ из 5000₽") {
+		t.Errorf("formatBankCapLine() = %q", line)
+	}
+
+	usage.Earned, usage.Remaining = 5000, 0
+	if line := formatBankCapLine(usage, ""); !strings.Contains(line, "⛔ Общий лимит банка исчерпан") {
+		t.Errorf("formatBankCapLine() исчерпан = %q", line)
+	}
+
+	usage.Earned, usage.Remaining = 1000, 4000
+	if line := formatBankCapLine(usage, ""); line != "" {
+		t.Errorf("formatBankCapLine() с запасом = %q", line)
+	}
+	if line := formatBankCapLine(nil, ""); line != "" {
+		t.Errorf("formatBankCapLine(nil) = %q", line)
+	}
+}
+
+func TestFormatBankCaps(t *testing.T) {
+	text := formatBankCaps([]models.BankCapUsage{{
+		BankCap: models.BankCap{BankName: "Альфа", Period: models.BankCapPeriodQuarter, Amount: 9000},
+		Earned:  8000, Remaining: 1000,
+	}})
+	if !strings.Contains(text, "⚠️ Альфа: 8000₽ из 9000₽ в квартал, осталось 1000₽") {
+		t.Errorf("formatBankCaps() = %q", text)
+	}
+
+	if text := formatBankCaps(nil); !strings.Contains(text, "/bankcap Тинькофф 5000") {
+		t.Errorf("formatBankCaps() без лимитов = %q", text)
+	}
+}
@@ -2,9 +2,12 @@
 package bot
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/rymax1e/open-cashback-advisor/internal/models"
@@ -30,61 +33,123 @@ const (
 	StateAwaitingDeleteID           UserStateType = "awaiting_delete_id"
 	StateAwaitingJoinGroupName      UserStateType = "awaiting_joingroup_name"
 	StateAwaitingCreateGroupName    UserStateType = "awaiting_creategroup_name"
+	StateAwaitingOCRConfirm         UserStateType = "awaiting_ocr_confirmation"
+	StateAwaitingImportConfirm      UserStateType = "awaiting_import_confirmation"
+	StateAwaitingDuplicateChoice    UserStateType = "awaiting_duplicate_choice"
+	StateAwaitingPaymentAmount      UserStateType = "awaiting_payment_amount"
 )
 
 // UserState хранит состояние диалога с пользователем.
+// Сериализуется в JSON для хранения в StateStore.
 type UserState struct {
-	State       UserStateType
-	Data        *ParsedData
-	Suggestion  *models.SuggestResponse
-	RuleID      int64
-	KeyboardPage int // Текущая страница клавиатуры
+	State        UserStateType           `json:"state"`
+	Data         *ParsedData             `json:"data,omitempty"`
+	Suggestion   *models.SuggestResponse `json:"suggestion,omitempty"`
+	RuleID       int64                   `json:"rule_id,omitempty"`
+	KeyboardPage int                     `json:"keyboard_page,omitempty"` // Текущая страница клавиатуры
+	Lines        []string                `json:"lines,omitempty"`         // Строки для многострочного добавления
+	FileID       string                  `json:"file_id,omitempty"`       // Файл импорта в Telegram
+	FileName     string                  `json:"file_name,omitempty"`
 }
 
 // Bot представляет Telegram бота для работы с кэшбэком.
+// Обработчики обновлений выполняются конкурентно в пуле воркеров,
+// поэтому все поля после создания бота только читаются.
 type Bot struct {
-	api        *tgbotapi.BotAPI
-	client     *APIClient
-	userStates map[int64]*UserState
+	api       *tgbotapi.BotAPI
+	client    *APIClient
+	callbacks *CallbackCodec
+	states    StateStore
+	ocr       OCREngine
+	workers   int
 }
 
 // NewBot создаёт нового бота.
-func NewBot(token string, apiClient *APIClient, debug bool) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(token)
+// Состояния диалогов хранятся в states (in-memory или PostgreSQL).
+// ocr может быть nil — тогда распознавание скриншотов отключено.
+func NewBot(cfg *Config, apiClient *APIClient, states StateStore, ocr OCREngine) (*Bot, error) {
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.TelegramToken, cfg.TelegramAPI)
 	if err != nil {
 		return nil, fmt.Errorf("не удалось создать бота: %w", err)
 	}
 
-	api.Debug = debug
+	api.Debug = cfg.Debug
 	log.Printf("✅ Авторизован как @%s", api.Self.UserName)
 
 	return &Bot{
-		api:        api,
-		client:     apiClient,
-		userStates: make(map[int64]*UserState),
+		api:       api,
+		client:    apiClient,
+		callbacks: NewCallbackCodec(cfg.TelegramToken),
+		states:    states,
+		ocr:       ocr,
+		workers:   cfg.Workers,
 	}, nil
 }
 
-// Start запускает основной цикл обработки сообщений.
-func (b *Bot) Start() {
+// Start получает обновления через long polling и обрабатывает их до отмены ctx.
+// После отмены прекращает приём новых обновлений и дожидается завершения
+// уже запущенных обработчиков.
+func (b *Bot) Start(ctx context.Context) {
+	// Long polling не работает, пока у бота зарегистрирован webhook
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		log.Printf("⚠️ Не удалось удалить webhook: %v", err)
+	}
+
 	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
+	u.Timeout = UpdateTimeout
 
 	updates := b.api.GetUpdatesChan(u)
 
-	log.Println("🤖 Бот запущен и ожидает сообщений...")
+	go b.cleanupStates(ctx)
+	go b.runDigests(ctx)
+	go b.runActivationReminders(ctx)
+
+	log.Printf("🤖 Бот запущен и ожидает сообщений (воркеров: %d)...", b.workers)
+
+	b.serve(ctx, updates)
+	b.api.StopReceivingUpdates()
+
+	log.Println("✅ Все обработчики завершены, бот остановлен")
+}
 
-	for update := range updates {
-		if update.Message != nil {
-			b.handleMessage(update.Message)
-		} else if update.CallbackQuery != nil {
-			b.handleCallback(update.CallbackQuery)
+// serve передаёт обновления из канала в пул воркеров до отмены ctx
+// или закрытия канала, после чего дожидается обработки принятых обновлений.
+func (b *Bot) serve(ctx context.Context, updates tgbotapi.UpdatesChannel) {
+	d := newDispatcher(b.workers, WorkerQueueSize, b.handleUpdate)
+	d.start()
+	defer d.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case update, ok := <-updates:
+			if !ok {
+				return
+			}
+			d.dispatch(update)
 		}
 	}
 }
 
+// handleUpdate маршрутизирует обновление к обработчику по его типу.
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	if update.Message != nil {
+		b.handleMessage(update.Message)
+	} else if update.CallbackQuery != nil {
+		b.handleCallback(update.CallbackQuery)
+	} else if update.InlineQuery != nil {
+		b.handleInlineQuery(update.InlineQuery)
+	}
+}
+
 // handleMessage маршрутизирует входящие сообщения.
 func (b *Bot) handleMessage(message *tgbotapi.Message) {
+	if isGroupChat(message.Chat) {
+		b.handleGroupChatMessage(message)
+		return
+	}
+
 	log.Printf("📨 Сообщение от @%s: %s", message.From.UserName, message.Text)
 
 	// Обработка кнопок навигации (до обработки команд)
@@ -102,7 +167,7 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 
 	// Проверяем, есть ли активное состояние, связанное с присоединением/созданием группы
 	// Если есть - пропускаем проверку членства, т.к. пользователь как раз пытается присоединиться
-	state, hasState := b.userStates[message.From.ID]
+	state, hasState := b.getState(message.From.ID)
 	skipGroupCheck := hasState && (state.State == StateAwaitingJoinGroupName || state.State == StateAwaitingCreateGroupName)
 
 	// Проверяем членство в группе (только если нет состояния присоединения)
@@ -112,6 +177,18 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 		}
 	}
 
+	// Скриншот банковского приложения
+	if isImageMessage(message) {
+		b.handlePhoto(message)
+		return
+	}
+
+	// Таблица с кэшбэками (CSV/XLSX)
+	if isSpreadsheetDocument(message) {
+		b.handleImportDocument(message)
+		return
+	}
+
 	// Обработка состояний пользователя
 	if b.handleUserState(message) {
 		return
@@ -159,6 +236,28 @@ func (b *Bot) routeCommand(message *tgbotapi.Message) {
 		b.handleUserInfo(message)
 	case "userlist":
 		b.handleUserList(message)
+	case "export":
+		b.handleExport(message)
+	case "addcard":
+		b.handleAddCard(message)
+	case "cards":
+		b.handleCards(message)
+	case "advice":
+		b.handleAdvice(message)
+	case "gaps":
+		b.handleGaps(message)
+	case "stats":
+		b.handleStats(message)
+	case "trend":
+		b.handleTrend(message)
+	case "digest":
+		b.handleDigest(message)
+	case "balance":
+		b.handleBalance(message)
+	case "bankcap":
+		b.handleBankCap(message)
+	case "spent":
+		b.handleSpent(message)
 	case "cancel":
 		b.handleCancel(message)
 	default:
@@ -187,7 +286,7 @@ func (b *Bot) handleUserState(message *tgbotapi.Message) bool {
 	userID := message.From.ID
 	userIDStr := strconv.FormatInt(userID, 10)
 	
-	state, exists := b.userStates[userID]
+	state, exists := b.getState(userID)
 	if !exists {
 		log.Printf("🔍 [HANDLE_STATE] Пользователь @%s (ID: %s) не имеет активного состояния", 
 			message.From.UserName, userIDStr)
@@ -222,6 +321,14 @@ func (b *Bot) handleUserState(message *tgbotapi.Message) bool {
 		b.handleUpdateIDInput(message)
 	case StateAwaitingDeleteID:
 		b.handleDeleteIDInput(message)
+	case StateAwaitingOCRConfirm:
+		b.handleOCRConfirmation(message, state)
+	case StateAwaitingImportConfirm:
+		b.handleImportConfirmation(message, state)
+	case StateAwaitingDuplicateChoice:
+		b.handleDuplicateChoice(message, state)
+	case StateAwaitingPaymentAmount:
+		b.handlePaymentAmountInput(message, state)
 	case StateAwaitingJoinGroupName:
 		log.Printf("🔍 [HANDLE_STATE] Вызываю handleJoinGroupNameInput для пользователя @%s", message.From.UserName)
 		b.handleJoinGroupNameInput(message)
@@ -237,24 +344,77 @@ func (b *Bot) handleUserState(message *tgbotapi.Message) bool {
 	return true
 }
 
-// handleCallback обрабатывает callback от inline кнопок.
-func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
-	b.api.Send(tgbotapi.NewCallback(callback.ID, ""))
+// getState получает состояние пользователя из хранилища.
+// Ошибки хранилища логируются и трактуются как отсутствие состояния.
+func (b *Bot) getState(userID int64) (*UserState, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), StateStoreTimeout)
+	defer cancel()
+
+	state, err := b.states.Get(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, ErrStateNotFound) {
+			log.Printf("❌ Ошибка чтения состояния пользователя %d: %v", userID, err)
+		}
+		return nil, false
+	}
+	return state, true
+}
+
+// saveState сохраняет состояние пользователя в хранилище.
+func (b *Bot) saveState(userID int64, state *UserState) {
+	ctx, cancel := context.WithTimeout(context.Background(), StateStoreTimeout)
+	defer cancel()
+
+	if err := b.states.Set(ctx, userID, state); err != nil {
+		log.Printf("❌ Ошибка сохранения состояния пользователя %d: %v", userID, err)
+	}
 }
 
 // setState устанавливает состояние пользователя.
 func (b *Bot) setState(userID int64, state UserStateType, data *ParsedData, suggestion *models.SuggestResponse, ruleID int64) {
-	b.userStates[userID] = &UserState{
+	b.saveState(userID, &UserState{
 		State:      state,
 		Data:       data,
 		Suggestion: suggestion,
 		RuleID:     ruleID,
-	}
+	})
 }
 
 // clearState очищает состояние пользователя.
 func (b *Bot) clearState(userID int64) {
-	delete(b.userStates, userID)
+	ctx, cancel := context.WithTimeout(context.Background(), StateStoreTimeout)
+	defer cancel()
+
+	if err := b.states.Delete(ctx, userID); err != nil {
+		log.Printf("❌ Ошибка удаления состояния пользователя %d: %v", userID, err)
+	}
+}
+
+// cleanupStates периодически удаляет брошенные состояния с истёкшим сроком жизни
+// до отмены ctx.
+func (b *Bot) cleanupStates(ctx context.Context) {
+	ticker := time.NewTicker(StateCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cleanupCtx, cancel := context.WithTimeout(ctx, StateStoreTimeout)
+		deleted, err := b.states.DeleteExpired(cleanupCtx)
+		cancel()
+
+		if err != nil {
+			log.Printf("❌ Ошибка очистки состояний: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("🧹 Удалено истёкших состояний: %d", deleted)
+		}
+	}
 }
 
 // getUserGroup получает группу пользователя или пустую строку.
@@ -286,7 +446,7 @@ func (b *Bot) handleNavigationButtons(message *tgbotapi.Message) bool {
 	userID := message.From.ID
 	
 	// Получаем текущую страницу
-	state, exists := b.userStates[userID]
+	state, exists := b.getState(userID)
 	currentPage := 0
 	if exists {
 		currentPage = state.KeyboardPage
@@ -321,12 +481,12 @@ func (b *Bot) handleNavigationButtons(message *tgbotapi.Message) bool {
 
 // setKeyboardPage устанавливает текущую страницу клавиатуры для пользователя.
 func (b *Bot) setKeyboardPage(userID int64, page int) {
-	state, exists := b.userStates[userID]
+	state, exists := b.getState(userID)
 	if !exists {
 		state = &UserState{}
-		b.userStates[userID] = state
 	}
 	state.KeyboardPage = page
+	b.saveState(userID, state)
 }
 
 // sendTextWithPage отправляет сообщение с клавиатурой на указанной странице.
@@ -334,9 +494,7 @@ func (b *Bot) sendTextWithPage(chatID int64, text string, page int) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "HTML"
 
-	kb := tgbotapi.NewReplyKeyboard(buildKeyboardWithPage(nil, page)...)
-	kb.ResizeKeyboard = true
-	msg.ReplyMarkup = kb
+	setMainKeyboard(&msg, page)
 
 	if _, err := b.api.Send(msg); err != nil {
 		log.Printf("❌ Ошибка отправки сообщения: %v", err)
@@ -0,0 +1,465 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// CallbackAction определяет действие, закодированное в callback-данных inline кнопки.
+type CallbackAction string
+
+// Действия inline кнопок.
+const (
+	CallbackConfirm            CallbackAction = "cf"
+	CallbackBankCorrection     CallbackAction = "bk"
+	CallbackCategoryCorrection CallbackAction = "ct"
+	CallbackDelete             CallbackAction = "dl"
+	CallbackListPage           CallbackAction = "pg"
+	CallbackOCRConfirm         CallbackAction = "oc"
+	CallbackImportConfirm      CallbackAction = "im"
+	CallbackExport             CallbackAction = "ex"
+	CallbackDuplicate          CallbackAction = "du"
+	CallbackPaymentRequest     CallbackAction = "pr"
+	CallbackPaymentAnswer      CallbackAction = "pa"
+	CallbackSettleDebt         CallbackAction = "sd"
+	CallbackActivateRule       CallbackAction = "ar"
+)
+
+// Параметры протокола callback-данных.
+const (
+	// callbackMaxLength — ограничение Telegram на длину callback_data в байтах.
+	callbackMaxLength = 64
+
+	// callbackSignatureBytes — количество байт HMAC, попадающих в подпись.
+	callbackSignatureBytes = 6
+
+	// callbackSeparator — разделитель частей callback-данных.
+	callbackSeparator = ":"
+)
+
+// CallbackData представляет раскодированные данные inline кнопки.
+type CallbackData struct {
+	Action  CallbackAction
+	Payload string
+}
+
+// CallbackCodec кодирует и проверяет callback-данные inline кнопок.
+//
+// Формат: "действие:данные:подпись". Подпись — усечённый HMAC-SHA256 от действия,
+// данных и ID пользователя, поэтому кнопку может нажать только тот, кому она отправлена,
+// а подделать данные без секрета нельзя.
+type CallbackCodec struct {
+	secret []byte
+}
+
+// NewCallbackCodec создаёт кодек с секретом, полученным из токена бота.
+func NewCallbackCodec(secret string) *CallbackCodec {
+	key := sha256.Sum256([]byte("callback:" + secret))
+	return &CallbackCodec{secret: key[:]}
+}
+
+// Encode кодирует действие и данные для указанного пользователя.
+func (c *CallbackCodec) Encode(action CallbackAction, payload string, userID int64) (string, error) {
+	if strings.Contains(string(action), callbackSeparator) || strings.Contains(payload, callbackSeparator) {
+		return "", fmt.Errorf("%w: недопустимый символ '%s'", ErrCallbackInvalid, callbackSeparator)
+	}
+
+	data := string(action) + callbackSeparator + payload + callbackSeparator + c.sign(action, payload, userID)
+	if len(data) > callbackMaxLength {
+		return "", fmt.Errorf("%w: %d байт", ErrCallbackTooLong, len(data))
+	}
+
+	return data, nil
+}
+
+// Decode проверяет подпись и раскодирует callback-данные.
+func (c *CallbackCodec) Decode(data string, userID int64) (*CallbackData, error) {
+	parts := strings.Split(data, callbackSeparator)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: неверный формат", ErrCallbackInvalid)
+	}
+
+	action, payload, signature := CallbackAction(parts[0]), parts[1], parts[2]
+	if !hmac.Equal([]byte(signature), []byte(c.sign(action, payload, userID))) {
+		return nil, fmt.Errorf("%w: неверная подпись", ErrCallbackInvalid)
+	}
+
+	return &CallbackData{Action: action, Payload: payload}, nil
+}
+
+// sign вычисляет подпись callback-данных.
+func (c *CallbackCodec) sign(action CallbackAction, payload string, userID int64) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(string(action) + callbackSeparator + payload + callbackSeparator + strconv.FormatInt(userID, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:callbackSignatureBytes])
+}
+
+// inlineButton описывает inline кнопку до кодирования.
+type inlineButton struct {
+	Text    string
+	Action  CallbackAction
+	Payload string
+}
+
+// buildInlineKeyboard формирует inline клавиатуру с подписанными callback-данными.
+func (b *Bot) buildInlineKeyboard(userID int64, rows [][]inlineButton) (tgbotapi.InlineKeyboardMarkup, error) {
+	keyboard := make([][]tgbotapi.InlineKeyboardButton, 0, len(rows))
+	for _, row := range rows {
+		keyboardRow := make([]tgbotapi.InlineKeyboardButton, 0, len(row))
+		for _, btn := range row {
+			data, err := b.callbacks.Encode(btn.Action, btn.Payload, userID)
+			if err != nil {
+				return tgbotapi.InlineKeyboardMarkup{}, err
+			}
+			keyboardRow = append(keyboardRow, tgbotapi.NewInlineKeyboardButtonData(btn.Text, data))
+		}
+		keyboard = append(keyboard, keyboardRow)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(keyboard...), nil
+}
+
+// sendWithInlineButtons отправляет сообщение с inline кнопками.
+func (b *Bot) sendWithInlineButtons(chatID, userID int64, text string, rows [][]inlineButton) {
+	if len(rows) == 0 {
+		b.sendTextPlain(chatID, text)
+		return
+	}
+
+	markup, err := b.buildInlineKeyboard(userID, rows)
+	if err != nil {
+		log.Printf("❌ Ошибка формирования inline клавиатуры: %v", err)
+		b.sendText(chatID, text)
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = markup
+
+	if _, err := b.api.Send(msg); err != nil {
+		log.Printf("❌ Ошибка отправки сообщения: %v", err)
+	}
+}
+
+// editMessage заменяет текст сообщения и убирает inline кнопки.
+func (b *Bot) editMessage(chatID int64, messageID int, text string) {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	if _, err := b.api.Send(edit); err != nil {
+		log.Printf("❌ Ошибка редактирования сообщения: %v", err)
+	}
+}
+
+// editMessageWithInlineButtons заменяет текст сообщения и его inline кнопки.
+func (b *Bot) editMessageWithInlineButtons(chatID int64, messageID int, userID int64, text string, rows [][]inlineButton) {
+	if len(rows) == 0 {
+		b.editMessage(chatID, messageID, text)
+		return
+	}
+
+	markup, err := b.buildInlineKeyboard(userID, rows)
+	if err != nil {
+		log.Printf("❌ Ошибка формирования inline клавиатуры: %v", err)
+		b.editMessage(chatID, messageID, text)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, markup)
+	if _, err := b.api.Send(edit); err != nil {
+		log.Printf("❌ Ошибка редактирования сообщения: %v", err)
+	}
+}
+
+// answerCallback отвечает на нажатие кнопки (убирает индикатор загрузки).
+func (b *Bot) answerCallback(callbackID, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(callbackID, text)); err != nil {
+		log.Printf("❌ Ошибка ответа на callback: %v", err)
+	}
+}
+
+// callbackMessage формирует сообщение от имени нажавшего кнопку пользователя,
+// чтобы переиспользовать обработчики, рассчитанные на входящие сообщения.
+func callbackMessage(callback *tgbotapi.CallbackQuery) *tgbotapi.Message {
+	message := *callback.Message
+	message.From = callback.From
+	message.Text = ""
+	return &message
+}
+
+// handleCallback обрабатывает callback от inline кнопок.
+func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
+	if callback.Message == nil {
+		b.answerCallback(callback.ID, "")
+		return
+	}
+
+	data, err := b.callbacks.Decode(callback.Data, callback.From.ID)
+	if err != nil {
+		log.Printf("⚠️ Некорректный callback от @%s: %v", callback.From.UserName, err)
+		b.answerCallback(callback.ID, MsgButtonExpired)
+		return
+	}
+
+	log.Printf("🔘 Callback от @%s: %s:%s", callback.From.UserName, data.Action, data.Payload)
+
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	message := callbackMessage(callback)
+	state, hasState := b.getState(callback.From.ID)
+
+	switch data.Action {
+	case CallbackListPage:
+		page, err := strconv.Atoi(data.Payload)
+		if err != nil {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.answerCallback(callback.ID, "")
+		b.showListPage(message, page, messageID)
+		return
+
+	case CallbackExport:
+		if !isExportFormat(data.Payload) {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.answerCallback(callback.ID, "")
+		b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+strings.ToUpper(data.Payload))
+		b.sendExport(message, data.Payload)
+		return
+
+	case CallbackDelete:
+		// Данные удаления: вариант ответа (1 символ) и ID правила
+		if data.Payload == "" {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		choice := answerChoice(data.Payload[:1])
+		ruleID, err := strconv.ParseInt(data.Payload[1:], 10, 64)
+		if err != nil || !hasState || state.State != StateAwaitingDeleteConfirm || state.RuleID != ruleID {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			b.editMessage(chatID, messageID, callback.Message.Text)
+			return
+		}
+		b.answerCallback(callback.ID, "")
+		b.editMessage(chatID, messageID, b.applyDeleteConfirmation(message, state, choice == choiceYes))
+		return
+
+	case CallbackPaymentRequest:
+		ruleID, err := strconv.ParseInt(data.Payload, 10, 64)
+		if err != nil {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.answerCallback(callback.ID, "")
+		b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+BtnAskToPay)
+		b.startPaymentRequest(message, ruleID)
+		return
+
+	case CallbackPaymentAnswer:
+		// Данные ответа: вариант ответа (1 символ) и ID просьбы
+		if data.Payload == "" {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		paymentID, err := strconv.ParseInt(data.Payload[1:], 10, 64)
+		if err != nil {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.applyPaymentAnswer(callback, paymentID, answerChoice(data.Payload[:1]) == choiceYes)
+		return
+
+	case CallbackSettleDebt:
+		debt, err := parseSettlePayload(data.Payload)
+		if err != nil {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.applySettleDebt(callback, debt)
+		return
+
+	case CallbackActivateRule:
+		ruleID, err := strconv.ParseInt(data.Payload, 10, 64)
+		if err != nil {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			return
+		}
+		b.applyActivation(callback, ruleID)
+		return
+
+	case CallbackOCRConfirm:
+		choice := answerChoice(data.Payload)
+		if !hasState || state.State != StateAwaitingOCRConfirm {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			b.editMessage(chatID, messageID, callback.Message.Text)
+			return
+		}
+		label := BtnSaveAll
+		if choice != choiceYes {
+			label = choice.Label()
+		}
+		b.answerCallback(callback.ID, "")
+		b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+label)
+		b.applyOCRConfirmation(message, state, choice)
+		return
+
+	case CallbackImportConfirm:
+		choice := answerChoice(data.Payload)
+		if !hasState || state.State != StateAwaitingImportConfirm {
+			b.answerCallback(callback.ID, MsgButtonExpired)
+			b.editMessage(chatID, messageID, callback.Message.Text)
+			return
+		}
+		label := BtnImport
+		if choice != choiceYes {
+			label = choice.Label()
+		}
+		b.answerCallback(callback.ID, "")
+		b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+label)
+		b.applyImportConfirmation(message, state, choice)
+		return
+	}
+
+	expected := map[CallbackAction]UserStateType{
+		CallbackDuplicate:          StateAwaitingDuplicateChoice,
+		CallbackConfirm:            StateAwaitingConfirmation,
+		CallbackBankCorrection:     StateAwaitingBankCorrection,
+		CallbackCategoryCorrection: StateAwaitingCategoryCorrection,
+	}
+
+	stateType, known := expected[data.Action]
+	if !known || !hasState || state.State != stateType {
+		b.answerCallback(callback.ID, MsgButtonExpired)
+		b.editMessage(chatID, messageID, callback.Message.Text)
+		return
+	}
+
+	choice := answerChoice(data.Payload)
+	b.answerCallback(callback.ID, "")
+	b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+choice.Label())
+
+	switch data.Action {
+	case CallbackDuplicate:
+		b.applyDuplicateChoice(message, state, choice)
+	case CallbackConfirm:
+		b.applyConfirmation(message, state, choice)
+	case CallbackBankCorrection:
+		b.applyBankCorrection(message, state, choice)
+	case CallbackCategoryCorrection:
+		b.applyCategoryCorrection(message, state, choice)
+	}
+}
+
+// answerChoice — вариант ответа пользователя на вопрос бота.
+type answerChoice string
+
+// Варианты ответа.
+const (
+	choiceUnknown answerChoice = ""
+	choiceYes     answerChoice = "y"
+	choiceNo      answerChoice = "n"
+	choiceManual  answerChoice = "m"
+	choiceCancel  answerChoice = "c"
+
+	// Варианты ответа на вопрос о дубликате правила
+	choiceReplace  answerChoice = "r"
+	choiceKeepBoth answerChoice = "k"
+)
+
+// Label возвращает подпись выбранного варианта для отображения.
+func (c answerChoice) Label() string {
+	switch c {
+	case choiceYes:
+		return BtnYesCorrect
+	case choiceNo:
+		return BtnNoKeepAsIs
+	case choiceManual:
+		return BtnManualEdit
+	case choiceCancel:
+		return BtnCancel
+	case choiceReplace:
+		return BtnReplace
+	case choiceKeepBoth:
+		return BtnKeepBoth
+	default:
+		return string(c)
+	}
+}
+
+// parseAnswerChoice распознаёт вариант ответа из текста сообщения.
+func parseAnswerChoice(text string) answerChoice {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	switch {
+	case isYesAnswer(text):
+		return choiceYes
+	case isNoAnswer(text):
+		return choiceNo
+	case isManualEditAnswer(text):
+		return choiceManual
+	case isCancelAnswer(text):
+		return choiceCancel
+	default:
+		return choiceUnknown
+	}
+}
+
+// confirmButtons — кнопки подтверждения исправления с ручным вводом и отменой.
+func confirmButtons(action CallbackAction) [][]inlineButton {
+	return [][]inlineButton{
+		{
+			{Text: BtnYesCorrect, Action: action, Payload: string(choiceYes)},
+			{Text: BtnNoKeepAsIs, Action: action, Payload: string(choiceNo)},
+		},
+		{{Text: BtnManualEdit, Action: action, Payload: string(choiceManual)}},
+		{{Text: BtnCancel, Action: action, Payload: string(choiceCancel)}},
+	}
+}
+
+// confirmSimpleButtons — кнопки да/нет с ручным вводом.
+func confirmSimpleButtons(action CallbackAction) [][]inlineButton {
+	return [][]inlineButton{
+		{
+			{Text: BtnYesCorrect, Action: action, Payload: string(choiceYes)},
+			{Text: BtnNoKeepAsIs, Action: action, Payload: string(choiceNo)},
+		},
+		{{Text: BtnManualEdit, Action: action, Payload: string(choiceManual)}},
+	}
+}
+
+// deleteButtons — кнопки подтверждения удаления правила.
+func deleteButtons(ruleID int64) [][]inlineButton {
+	id := strconv.FormatInt(ruleID, 10)
+	return [][]inlineButton{{
+		{Text: BtnYesDelete, Action: CallbackDelete, Payload: string(choiceYes) + id},
+		{Text: BtnCancelShort, Action: CallbackDelete, Payload: string(choiceNo) + id},
+	}}
+}
+
+// listPageButtons — кнопки навигации по страницам списка.
+func listPageButtons(page, totalPages int) [][]inlineButton {
+	if totalPages <= 1 {
+		return nil
+	}
+
+	var row []inlineButton
+	if page > 0 {
+		row = append(row, inlineButton{Text: BtnNavPrev, Action: CallbackListPage, Payload: strconv.Itoa(page - 1)})
+	}
+	row = append(row, inlineButton{
+		Text:    fmt.Sprintf("%d / %d", page+1, totalPages),
+		Action:  CallbackListPage,
+		Payload: strconv.Itoa(page),
+	})
+	if page < totalPages-1 {
+		row = append(row, inlineButton{Text: BtnNavNext, Action: CallbackListPage, Payload: strconv.Itoa(page + 1)})
+	}
+
+	return [][]inlineButton{row}
+}
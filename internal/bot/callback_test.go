@@ -0,0 +1,82 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCallbackCodecRoundTrip(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+
+	data, err := codec.Encode(CallbackDelete, "y12345", 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(data) > callbackMaxLength {
+		t.Errorf("Callback data too long: %d", len(data))
+	}
+
+	decoded, err := codec.Decode(data, 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if decoded.Action != CallbackDelete || decoded.Payload != "y12345" {
+		t.Errorf("Decode() = %+v, want action %s payload y12345", decoded, CallbackDelete)
+	}
+}
+
+func TestCallbackCodecRejectsForeignUser(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+
+	data, err := codec.Encode(CallbackConfirm, string(choiceYes), 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, err := codec.Decode(data, 43); !errors.Is(err, ErrCallbackInvalid) {
+		t.Errorf("Expected ErrCallbackInvalid for another user, got %v", err)
+	}
+}
+
+func TestCallbackCodecRejectsTampering(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+
+	data, err := codec.Encode(CallbackDelete, "n1", 42)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tampered := strings.Replace(data, "n1", "y1", 1)
+	if _, err := codec.Decode(tampered, 42); !errors.Is(err, ErrCallbackInvalid) {
+		t.Errorf("Expected ErrCallbackInvalid for tampered data, got %v", err)
+	}
+
+	other := NewCallbackCodec("other-token")
+	if _, err := other.Decode(data, 42); !errors.Is(err, ErrCallbackInvalid) {
+		t.Errorf("Expected ErrCallbackInvalid for another secret, got %v", err)
+	}
+}
+
+func TestCallbackCodecLimits(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+
+	tests := []struct {
+		name    string
+		payload string
+		wantErr error
+	}{
+		{"Separator in payload", "a:b", ErrCallbackInvalid},
+		{"Too long payload", strings.Repeat("x", callbackMaxLength), ErrCallbackTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := codec.Encode(CallbackListPage, tt.payload, 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Encode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
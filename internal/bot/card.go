@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// paymentSystemLabels — названия платёжных систем для показа.
+var paymentSystemLabels = map[string]string{
+	models.PaymentSystemMir:        "Мир",
+	models.PaymentSystemVisa:       "Visa",
+	models.PaymentSystemMastercard: "Mastercard",
+	models.PaymentSystemUnionPay:   "UnionPay",
+}
+
+// paymentSystemKeywords сопоставляет написания платёжных систем с их кодами.
+var paymentSystemKeywords = map[string]string{
+	"мир":        models.PaymentSystemMir,
+	"mir":        models.PaymentSystemMir,
+	"visa":       models.PaymentSystemVisa,
+	"виза":       models.PaymentSystemVisa,
+	"mastercard": models.PaymentSystemMastercard,
+	"мастеркард": models.PaymentSystemMastercard,
+	"mc":         models.PaymentSystemMastercard,
+	"unionpay":   models.PaymentSystemUnionPay,
+	"юнионпей":   models.PaymentSystemUnionPay,
+}
+
+// cardLast4Pattern находит в тексте правила карту вида "*1234" или "***1234".
+var cardLast4Pattern = regexp.MustCompile(`\*+\s*(\d{4})\b`)
+
+// formatCardLabel возвращает подпись карты, например "Карта Мир Сбер ***1234".
+func formatCardLabel(card *models.Card) string {
+	system, ok := paymentSystemLabels[card.PaymentSystem]
+	if !ok {
+		system = card.PaymentSystem
+	}
+
+	label := fmt.Sprintf("Карта %s %s ***%s", system, card.BankName, card.Last4)
+	if card.Nickname != "" {
+		label += fmt.Sprintf(" (%s)", card.Nickname)
+	}
+	return label
+}
+
+// formatCardLine возвращает строку с картой правила
+// или пустую строку, если карта не указана.
+func formatCardLine(rule *models.CashbackRule, indent string) string {
+	if rule.Card == nil {
+		return ""
+	}
+	return fmt.Sprintf("\n%s💳 %s", indent, formatCardLabel(rule.Card))
+}
+
+// extractCardLast4 вынимает из текста последние цифры карты ("***1234").
+// Возвращает цифры и текст без них.
+func extractCardLast4(text string) (string, string) {
+	match := cardLast4Pattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", text
+	}
+	return match[1], strings.Replace(text, match[0], " ", 1)
+}
+
+// parseCardArgs разбирает аргументы /addcard: "Банк, Платёжная система, 1234[, Название]".
+func parseCardArgs(args string) (*models.CreateCardRequest, error) {
+	parts := strings.Split(args, ",")
+	if len(parts) < 3 {
+		return nil, NewParseError("", "нужно: Банк, Платёжная система, последние 4 цифры[, Название]")
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	system, ok := paymentSystemKeywords[strings.ToLower(parts[1])]
+	if !ok {
+		return nil, NewParseError("платёжная система", "укажите Мир, Visa, Mastercard или UnionPay")
+	}
+
+	last4 := strings.TrimLeft(parts[2], "* ")
+	if len(last4) != 4 {
+		return nil, NewParseError("номер карты", "нужны последние 4 цифры, например 1234")
+	}
+	if _, err := strconv.Atoi(last4); err != nil {
+		return nil, NewParseError("номер карты", "нужны последние 4 цифры, например 1234")
+	}
+
+	req := &models.CreateCardRequest{
+		BankName:      parts[0],
+		PaymentSystem: system,
+		Last4:         last4,
+	}
+	if len(parts) > 3 {
+		req.Nickname = strings.Join(parts[3:], ", ")
+	}
+	return req, nil
+}
+
+// handleAddCard обрабатывает команду /addcard Банк, Платёжная система, 1234[, Название].
+func (b *Bot) handleAddCard(message *tgbotapi.Message) {
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.sendText(message.Chat.ID, "💳 Укажите карту через запятую:\n"+
+			"Банк, Платёжная система, последние 4 цифры[, Название]\n\n"+
+			"Например: /addcard Сбер, Мир, 1234, Зарплатная")
+		return
+	}
+
+	req, err := parseCardArgs(args)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ %s\n\nНапример: /addcard Сбер, Мир, 1234", err))
+		return
+	}
+
+	if correctedBank, found := FindSimilarBank(req.BankName); found {
+		req.BankName = correctedBank
+	}
+	req.UserID = strconv.FormatInt(message.From.ID, 10)
+
+	card, err := b.client.CreateCard(req)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось добавить карту: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, fmt.Sprintf("✅ Добавлена %s (ID: %d)\n\n"+
+		"Новые кэшбэки %s будут привязаны к ней автоматически, если это ваша единственная карта банка. "+
+		"Иначе укажите карту в конце сообщения: %s, Такси, 5, 3000, ***%s",
+		formatCardLabel(card), card.ID, card.BankName, card.BankName, card.Last4))
+}
+
+// handleCards обрабатывает команду /cards [удалить ID].
+func (b *Bot) handleCards(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	args := strings.Fields(message.CommandArguments())
+
+	if len(args) == 2 && strings.EqualFold(args[0], "удалить") {
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			b.sendText(message.Chat.ID, "❌ Укажите ID карты. Например: /cards удалить 3")
+			return
+		}
+
+		if err := b.client.DeleteCard(id, userIDStr); err != nil {
+			if errors.Is(err, ErrCardNotFound) {
+				b.sendText(message.Chat.ID, "❌ У вас нет карты с таким ID")
+				return
+			}
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось удалить карту: %v", err))
+			return
+		}
+
+		b.sendText(message.Chat.ID, "✅ Карта удалена. Её кэшбэки остались без привязки к карте.")
+		return
+	}
+
+	cards, err := b.client.ListUserCards(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось получить карты: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatCardList(cards))
+}
+
+// formatCardList форматирует список карт пользователя.
+func formatCardList(cards []models.Card) string {
+	if len(cards) == 0 {
+		return "💳 У вас пока нет карт.\n\nДобавьте карту: /addcard Сбер, Мир, 1234"
+	}
+
+	text := "💳 Ваши карты:\n\n"
+	for _, card := range cards {
+		text += fmt.Sprintf("• %s — ID: %d\n", formatCardLabel(&card), card.ID)
+	}
+	text += "\nУдалить карту: /cards удалить ID"
+	return text
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatCardLabel(t *testing.T) {
+	card := &models.Card{BankName: "Сбер", Last4: "1234", PaymentSystem: models.PaymentSystemMir}
+	if got := formatCardLabel(card); got != "Карта Мир Сбер ***1234" {
+		t.Errorf("formatCardLabel() = %q", got)
+	}
+
+	card.Nickname = "Зарплатная"
+	if got := formatCardLabel(card); got != "Карта Мир Сбер ***1234 (Зарплатная)" {
+		t.Errorf("formatCardLabel() с названием = %q", got)
+	}
+}
+
+func TestParseCardArgs(t *testing.T) {
+	req, err := parseCardArgs("Сбер, visa, ***5678, Кредитная")
+	if err != nil {
+		t.Fatalf("parseCardArgs() error = %v", err)
+	}
+	if req.BankName != "Сбер" || req.PaymentSystem != models.PaymentSystemVisa ||
+		req.Last4 != "5678" || req.Nickname != "Кредитная" {
+		t.Errorf("parseCardArgs() = %+v", req)
+	}
+
+	for _, args := range []string{"Сбер, Мир", "Сбер, Амекс, 1234", "Сбер, Мир, 12a4"} {
+		if _, err := parseCardArgs(args); err == nil {
+			t.Errorf("parseCardArgs(%q) ожидалась ошибка", args)
+		}
+	}
+}
+
+func TestParseMessageCardLast4(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantLast4 string
+	}{
+		{"Сбер, Такси, 5, 3000, ***1234", "1234"},
+		{"Сбер, Такси, 5, 3000, 31.12.2099, *5678 выходные", "5678"},
+		{"Сбер, Такси, 5, 3000", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			data, err := ParseMessage(tt.input)
+			if err != nil {
+				t.Fatalf("ParseMessage() error = %v", err)
+			}
+			if data.CardLast4 != tt.wantLast4 {
+				t.Errorf("ParseMessage() карта = %q, ожидалось %q", data.CardLast4, tt.wantLast4)
+			}
+		})
+	}
+}
+
+func TestFormatBestCashbackShowsCard(t *testing.T) {
+	rule := &models.CashbackRule{
+		BankName: "Сбер", Category: "Такси", CashbackPercent: 5,
+		Card: &models.Card{BankName: "Сбер", Last4: "1234", PaymentSystem: models.PaymentSystemMir},
+	}
+
+	if text := formatBestCashback(rule, requestedLevel("Такси")); !strings.Contains(text, "💳 Карта Мир Сбер ***1234") {
+		t.Errorf("formatBestCashback() не содержит карту: %q", text)
+	}
+	if text := formatUserInfo([]models.CashbackRule{*rule}, "Семья"); !strings.Contains(text, "Карта Мир Сбер ***1234") {
+		t.Errorf("formatUserInfo() не содержит карту: %q", text)
+	}
+}
@@ -1,6 +1,7 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
@@ -62,60 +63,51 @@ func (b *Bot) handleNewCashback(message *tgbotapi.Message, userID int64) {
 }
 
 // handleMultilineCashback обрабатывает добавление нескольких кэшбэков за раз.
+// Распознанные строки сохраняются пакетными запросами по BatchMaxOperations
+// операций: ошибка в одной строке не мешает сохранить остальные.
 func (b *Bot) handleMultilineCashback(message *tgbotapi.Message, lines []string) {
 	b.sendText(message.Chat.ID, fmt.Sprintf("📝 Обрабатываю %d строк...\n", len(lines)))
 	
-	var results []string
+	results := make([]string, len(lines))
+	var conflicts []string // строки, для которых уже есть правило на этот месяц
 	successCount := 0
 	errorCount := 0
+
+	var operations []models.BatchOperation
+	var operationLines []int // номер строки для каждой операции
 	
 	for i, line := range lines {
-		// Парсим строку
-		data, err := ParseMessage(line)
+		data, err := parseCashbackLine(line)
 		if err != nil {
-			results = append(results, fmt.Sprintf("❌ Строка %d: %s", i+1, err))
-			errorCount++
-			continue
-		}
-		
-		// Проверяем полноту данных
-		missing := ValidateParsedData(data)
-		if len(missing) > 0 {
-			results = append(results, fmt.Sprintf("❌ Строка %d: не хватает %s", i+1, strings.Join(missing, ", ")))
+			results[i] = fmt.Sprintf("❌ Строка %d: %s", i+1, err)
 			errorCount++
 			continue
 		}
-		
-		// Проверяем опечатки в банке (автоматическая коррекция)
-		if correctedBank, found := FindSimilarBank(data.BankName); found && correctedBank != data.BankName {
-			log.Printf("💡 Автокоррекция банка: '%s' → '%s'", data.BankName, correctedBank)
-			data.BankName = correctedBank
-		}
-		
-		// Сохраняем без валидации через API (упрощенный режим)
-		userIDStr := strconv.FormatInt(message.From.ID, 10)
-		groupName := b.getUserGroup(message.From.ID)
-		
-		req := &models.CreateCashbackRequest{
-			GroupName:       groupName,
-			Category:        data.Category,
-			BankName:        data.BankName,
-			UserID:          userIDStr,
-			UserDisplayName: getUserDisplayName(message.From),
-			MonthYear:       data.MonthYear,
-			CashbackPercent: data.CashbackPercent,
-			MaxAmount:       data.MaxAmount,
-			Force:           true,
-		}
-		
-		rule, err := b.client.CreateCashback(req)
-		if err != nil {
-			results = append(results, fmt.Sprintf("❌ Строка %d: %s", i+1, err))
-			errorCount++
-		} else {
-			results = append(results, fmt.Sprintf("✅ Строка %d: %s - %s (ID: %d)", 
-				i+1, rule.BankName, rule.Category, rule.ID))
-			successCount++
+
+		operations = append(operations, models.BatchOperation{
+			Op:     models.BatchOpCreate,
+			Create: b.newCreateRequest(message.From, data, false),
+		})
+		operationLines = append(operationLines, i)
+	}
+
+	if len(operations) > 0 {
+		for _, result := range batchInChunks(b.client, operations) {
+			i := operationLines[result.Index]
+			switch {
+			case result.Status == models.BatchStatusOK:
+				results[i] = fmt.Sprintf("✅ Строка %d: %s - %s (ID: %d)",
+					i+1, result.Rule.BankName, result.Rule.Category, result.ID)
+				successCount++
+			case result.Existing != nil && result.Identical:
+				results[i] = fmt.Sprintf("♻️ Строка %d: уже сохранено (ID: %d)", i+1, result.Existing.ID)
+			case result.Existing != nil:
+				results[i] = fmt.Sprintf("⚠️ Строка %d: %s", i+1, formatDuplicateShort(result.Existing))
+				conflicts = append(conflicts, lines[i])
+			default:
+				results[i] = fmt.Sprintf("❌ Строка %d: %s", i+1, result.Error)
+				errorCount++
+			}
 		}
 	}
 	
@@ -124,6 +116,68 @@ func (b *Bot) handleMultilineCashback(message *tgbotapi.Message, lines []string)
 	b.sendText(message.Chat.ID, summary+strings.Join(results, "\n"))
 	
 	b.clearState(message.From.ID)
+
+	if len(conflicts) > 0 {
+		b.askMultilineDuplicateResolution(message, conflicts)
+	}
+}
+
+// batchInChunks сохраняет операции пакетами не больше BatchMaxOperations
+// в режиме best_effort и возвращает результаты с номерами операций во всём
+// списке. Если пакет не удалось отправить, все его операции получают ошибку,
+// а следующие пакеты всё равно отправляются.
+func batchInChunks(client *APIClient, operations []models.BatchOperation) []models.BatchResult {
+	results := make([]models.BatchResult, 0, len(operations))
+
+	for start := 0; start < len(operations); start += BatchMaxOperations {
+		end := start + BatchMaxOperations
+		if end > len(operations) {
+			end = len(operations)
+		}
+
+		resp, err := client.BatchCashback(&models.BatchRequest{
+			Mode:       models.BatchModeBestEffort,
+			Operations: operations[start:end],
+		})
+		if err != nil {
+			for index := start; index < end; index++ {
+				results = append(results, models.BatchResult{
+					Index:  index,
+					Op:     operations[index].Op,
+					Status: models.BatchStatusError,
+					Error:  fmt.Sprintf("ошибка сохранения: %s", err),
+				})
+			}
+			continue
+		}
+
+		for _, result := range resp.Results {
+			result.Index += start
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// parseCashbackLine разбирает строку многострочного добавления
+// и автоматически исправляет опечатки в названии банка.
+func parseCashbackLine(line string) (*ParsedData, error) {
+	data, err := ParseMessage(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := ValidateParsedData(data); len(missing) > 0 {
+		return nil, fmt.Errorf("не хватает %s", strings.Join(missing, ", "))
+	}
+
+	if correctedBank, found := FindSimilarBank(data.BankName); found && correctedBank != data.BankName {
+		log.Printf("💡 Автокоррекция банка: '%s' → '%s'", data.BankName, correctedBank)
+		data.BankName = correctedBank
+	}
+
+	return data, nil
 }
 
 // suggestBankCorrection предлагает исправление названия банка.
@@ -140,7 +194,7 @@ func (b *Bot) suggestBankCorrection(message *tgbotapi.Message, data *ParsedData,
 	correctedData.BankName = correctedBank
 
 	b.setState(message.From.ID, StateAwaitingBankCorrection, &correctedData, nil, 0)
-	b.sendWithButtons(message.Chat.ID, text, ButtonsConfirmSimple)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, confirmSimpleButtons(CallbackBankCorrection))
 }
 
 // continueWithValidation продолжает валидацию через API.
@@ -186,7 +240,7 @@ func (b *Bot) continueWithValidation(message *tgbotapi.Message, data *ParsedData
 		text += "\n\n❓ Исправить и сохранить?"
 
 		b.setState(userID, StateAwaitingConfirmation, data, suggestion, 0)
-		b.sendWithButtons(message.Chat.ID, text, ButtonsConfirm)
+		b.sendWithInlineButtons(message.Chat.ID, userID, text, confirmButtons(CallbackConfirm))
 	} else {
 		b.saveCashback(message.Chat.ID, message.From, data, false)
 		b.clearState(userID)
@@ -221,32 +275,44 @@ func (b *Bot) findRealSuggestions(data *ParsedData, suggestion *models.SuggestRe
 }
 
 // saveCashback сохраняет кэшбэк через API.
+// force сохраняет правило, даже если на этот месяц уже есть такое же.
 func (b *Bot) saveCashback(chatID int64, user *tgbotapi.User, data *ParsedData, force bool) {
-	userIDStr := strconv.FormatInt(user.ID, 10)
-	groupName := b.getUserGroup(user.ID)
-
-	req := &models.CreateCashbackRequest{
-		GroupName:       groupName,
-		Category:        data.Category,
-		BankName:        data.BankName,
-		UserID:          userIDStr,
-		UserDisplayName: getUserDisplayName(user),
-		MonthYear:       data.MonthYear,
-		CashbackPercent: data.CashbackPercent,
-		MaxAmount:       data.MaxAmount,
-		Force:           force,
-	}
+	req := b.newCreateRequest(user, data, force)
 
 	log.Printf("💾 Сохранение в API: Bank='%s', Category='%s', Force=%v",
 		req.BankName, req.Category, force)
 
 	rule, err := b.client.CreateCashback(req)
 	if err != nil {
+		var duplicate *DuplicateError
+		if errors.As(err, &duplicate) {
+			b.askDuplicateResolution(chatID, user.ID, data, duplicate)
+			return
+		}
 		b.sendText(chatID, fmt.Sprintf("❌ Ошибка сохранения: %s", err))
 		return
 	}
 
-	b.sendText(chatID, formatSavedCashback(rule))
+	b.sendSavedCashback(chatID, user.ID, rule)
+}
+
+// newCreateRequest формирует запрос на создание правила от имени пользователя.
+func (b *Bot) newCreateRequest(user *tgbotapi.User, data *ParsedData, force bool) *models.CreateCashbackRequest {
+	return &models.CreateCashbackRequest{
+		GroupName:       b.getUserGroup(user.ID),
+		Category:        data.Category,
+		BankName:        data.BankName,
+		UserID:          strconv.FormatInt(user.ID, 10),
+		UserDisplayName: getUserDisplayName(user),
+		MonthYear:       data.MonthYear,
+		CashbackPercent: data.CashbackPercent,
+		MaxAmount:       data.MaxAmount,
+		RewardProgram:   data.RewardProgram,
+		Conditions:      conditionsPtr(data.Conditions),
+		CardLast4:       data.CardLast4,
+		Activation:      data.Activation,
+		Force:           force,
+	}
 }
 
 // handleBestQueryByCategory обрабатывает поиск лучшего кэшбэка по категории.
@@ -261,9 +327,8 @@ func (b *Bot) handleBestQueryWithCorrection(message *tgbotapi.Message, category
 		return
 	}
 
-	userIDStr := strconv.FormatInt(message.From.ID, 10)
-	groupName, err := b.client.GetUserGroup(userIDStr)
-	if err != nil {
+	groupName := b.resolveGroup(message)
+	if groupName == "" {
 		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
 		return
 	}
@@ -273,6 +338,11 @@ func (b *Bot) handleBestQueryWithCorrection(message *tgbotapi.Message, category
 
 	b.sendText(message.Chat.ID, fmt.Sprintf("🔍 Ищу лучший кэшбэк для \"%s\" в группе \"%s\"...", category, groupName))
 
+	// Сначала проверяем, не магазин ли это из справочника
+	if !skipSuggestion && b.tryBestByMerchant(message, category, groupName, monthYear) {
+		return
+	}
+
 	// Получаем все кэшбэки по точной категории
 	allRules, err := b.getAllCashbacksByCategory(groupName, category, monthYear)
 	
@@ -286,7 +356,18 @@ func (b *Bot) handleBestQueryWithCorrection(message *tgbotapi.Message, category
 				i+1, rule.BankName, rule.Category, rule.CashbackPercent, rule.MaxAmount, rule.Category)
 		}
 		
-		b.sendText(message.Chat.ID, formatAllCashbackResults(allRules, category, false))
+		text := formatAllCashbackResults(allRules, requestedLevel(category))
+
+		// Кэшбэк на родительскую категорию может оказаться выгоднее
+		if parentRules, match := b.findParentCashbacks(groupName, category, monthYear); len(parentRules) > 0 &&
+			parentRules[0].EffectivePercent > allRules[0].EffectivePercent {
+			text += formatParentLevelHint(&parentRules[0], match)
+		}
+		summary, best := b.personalBestSummary(message.From.ID, groupName, category, monthYear)
+		text += summary
+
+		b.sendText(message.Chat.ID, text)
+		b.offerPaymentRequest(message, best)
 		return
 	}
 	
@@ -296,16 +377,9 @@ func (b *Bot) handleBestQueryWithCorrection(message *tgbotapi.Message, category
 			b.trySuggestSimilarCategory(message, category, groupName, monthYear)
 		} else {
 		// skipSuggestion=true означает, что уже была попытка с исправлением
-		// Пробуем "Все покупки" как последний вариант
-		log.Printf("⚠️ Уже была попытка исправления, пробуем 'Все покупки'")
-		allPurchasesRules, errAll := b.getAllCashbacksByCategory(groupName, "Все покупки", monthYear)
-		if errAll == nil && len(allPurchasesRules) > 0 {
-			log.Printf("✅ Найдено %d кешбеков для 'Все покупки' как fallback", len(allPurchasesRules))
-			b.sendText(message.Chat.ID, formatAllCashbackResults(allPurchasesRules, category, true))
-		return
-	}
-		log.Printf("❌ 'Все покупки' тоже не найдены, показываю 'не найдено'")
-		b.sendText(message.Chat.ID, formatNotFoundMessage(category, monthYear))
+		// Поднимаемся по дереву категорий как последний вариант
+		log.Printf("⚠️ Уже была попытка исправления, ищу на родительских категориях")
+		b.sendParentCashbacks(message, groupName, category, monthYear)
 	}
 }
 
@@ -327,15 +401,8 @@ func (b *Bot) trySuggestSimilarCategory(message *tgbotapi.Message, category, gro
 	// В этом случае НЕ выполняем поиск снова (чтобы избежать бесконечного цикла)
 	// Вместо этого сразу пробуем fallback на "Все покупки"
 	if simPercent == 100.0 && strings.EqualFold(category, similar) {
-		log.Printf("⚠️ Категория '%s' существует, но все кешбеки истекли. Пробуем 'Все покупки'", category)
-		allPurchasesRules, errAll := b.getAllCashbacksByCategory(groupName, "Все покупки", monthYear)
-		if errAll == nil && len(allPurchasesRules) > 0 {
-			log.Printf("✅ Найдено %d кешбеков для 'Все покупки' как fallback", len(allPurchasesRules))
-			b.sendText(message.Chat.ID, formatAllCashbackResults(allPurchasesRules, category, true))
-			return
-		}
-		log.Printf("❌ 'Все покупки' тоже не найдены, показываю 'не найдено'")
-		b.sendText(message.Chat.ID, formatNotFoundMessage(category, monthYear))
+		log.Printf("⚠️ Категория '%s' существует, но все кешбеки истекли. Ищу на родительских категориях", category)
+		b.sendParentCashbacks(message, groupName, category, monthYear)
 		return
 	}
 
@@ -349,17 +416,9 @@ func (b *Bot) trySuggestSimilarCategory(message *tgbotapi.Message, category, gro
 		return
 	}
 
-	// Ничего похожего не нашли - пробуем "Все покупки" как fallback
-	log.Printf("❌ Похожесть слишком низкая (%.1f%%), пробую 'Все покупки'", simPercent)
-	allPurchasesRules, errAll := b.getAllCashbacksByCategory(groupName, "Все покупки", monthYear)
-	if errAll == nil && len(allPurchasesRules) > 0 {
-		b.sendText(message.Chat.ID, formatAllCashbackResults(allPurchasesRules, category, true))
-		return
-	}
-	
-	// Даже "Все покупки" не найдены - показываем "не найдено"
-	log.Printf("❌ 'Все покупки' тоже не найдены")
-	b.sendText(message.Chat.ID, formatNotFoundMessage(category, monthYear))
+	// Ничего похожего не нашли - поднимаемся по дереву категорий
+	log.Printf("❌ Похожесть слишком низкая (%.1f%%), ищу на родительских категориях", simPercent)
+	b.sendParentCashbacks(message, groupName, category, monthYear)
 }
 
 // suggestCategoryCorrection предлагает уверенное исправление категории.
@@ -376,7 +435,7 @@ func (b *Bot) suggestCategoryCorrection(message *tgbotapi.Message, original, sug
 		original, suggested, distance, simPercent)
 
 	b.setState(message.From.ID, StateAwaitingCategoryCorrection, &ParsedData{Category: suggested}, nil, 0)
-	b.sendWithButtons(message.Chat.ID, text, ButtonsConfirmSimple)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, confirmSimpleButtons(CallbackCategoryCorrection))
 }
 
 // suggestWeakCategoryCorrection предлагает слабое исправление категории.
@@ -393,7 +452,7 @@ func (b *Bot) suggestWeakCategoryCorrection(message *tgbotapi.Message, original,
 		original, suggested, distance, simPercent)
 
 	b.setState(message.From.ID, StateAwaitingCategoryCorrection, &ParsedData{Category: suggested}, nil, 0)
-	b.sendWithButtons(message.Chat.ID, text, ButtonsConfirmSimple)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, confirmSimpleButtons(CallbackCategoryCorrection))
 }
 
 // logSuggestions логирует полученные предложения.
@@ -427,8 +486,14 @@ func (b *Bot) getAllCashbacksByCategory(groupName, category, monthYear string) (
 	var filtered []models.CashbackRule
 	var matchedButExpired int
 	now := time.Now()
+	today := models.PurchaseContext{Weekday: isoWeekday(now)}
 	
 	for _, rule := range list.Rules {
+		// Спецпредложения магазинов показываются только при поиске по магазину
+		if rule.Merchant != "" {
+			continue
+		}
+
 		// Проверяем, что категория содержит введенное слово (без учета регистра)
 		ruleCategoryLower := strings.ToLower(rule.Category)
 		containsCategory := strings.Contains(ruleCategoryLower, categoryLower)
@@ -437,6 +502,12 @@ func (b *Bot) getAllCashbacksByCategory(groupName, category, monthYear string) (
 		exactMatch := strings.EqualFold(rule.Category, category)
 		
 		if exactMatch || containsCategory {
+			// Правила, которые сегодня не действуют (например, только по выходным), не показываем
+			if !rule.Conditions.Allows(today) {
+				log.Printf("📌 Кешбек %s для '%s' сегодня не действует: %s",
+					rule.BankName, category, formatConditions(rule.Conditions))
+				continue
+			}
 			if rule.MonthYear.After(now.AddDate(0, 0, -1)) {
 				filtered = append(filtered, rule)
 			} else {
@@ -460,17 +531,17 @@ func (b *Bot) getAllCashbacksByCategory(groupName, category, monthYear string) (
 	return filtered, nil
 }
 
-// sortCashbackByCategoryAndPercent сортирует кэшбэки по убыванию процента кэшбэка.
+// sortCashbackByCategoryAndPercent сортирует кэшбэки по убыванию эффективного процента.
 func sortCashbackByCategoryAndPercent(rules []models.CashbackRule, searchCategory string) {
 	for i := 0; i < len(rules)-1; i++ {
 		for j := i + 1; j < len(rules); j++ {
-			// Сортируем по убыванию процента кэшбэка
+			// Сортируем по убыванию эффективного процента (в рублях)
 			// При равном проценте - по убыванию максимальной суммы
 			shouldSwap := false
 			
-			if rules[j].CashbackPercent > rules[i].CashbackPercent {
+			if rules[j].EffectivePercent > rules[i].EffectivePercent {
 				shouldSwap = true
-			} else if rules[j].CashbackPercent == rules[i].CashbackPercent && rules[j].MaxAmount > rules[i].MaxAmount {
+			} else if rules[j].EffectivePercent == rules[i].EffectivePercent && rules[j].MaxAmount > rules[i].MaxAmount {
 				shouldSwap = true
 			}
 			
@@ -498,29 +569,19 @@ func (b *Bot) trySuggestSimilarBank(message *tgbotapi.Message, bankName, groupNa
 	if simPercent > 60.0 || (simPercent > 40.0 && distance <= 2) {
 		log.Printf("✅ Предлагаю исправление банка: '%s' → '%s' (расстояние: %d, похожесть: %.1f%%)", bankName, similar, distance, simPercent)
 		text := fmt.Sprintf("🤔 Возможно, вы имели в виду банк \"%s\"?", similar)
-		buttons := [][]tgbotapi.KeyboardButton{
+		buttons := [][]inlineButton{
 			{
-				tgbotapi.NewKeyboardButton(fmt.Sprintf("✅ Да, показать для \"%s\"", similar)),
+				{Text: fmt.Sprintf("✅ Да, показать для \"%s\"", similar), Action: CallbackBankCorrection, Payload: string(choiceYes)},
 			},
 			{
-				tgbotapi.NewKeyboardButton("✏️ Изменить вручную"),
-				tgbotapi.NewKeyboardButton("🚫 Отменить"),
+				{Text: BtnManualEdit, Action: CallbackBankCorrection, Payload: string(choiceManual)},
+				{Text: "🚫 Отменить", Action: CallbackBankCorrection, Payload: string(choiceCancel)},
 			},
 		}
 
-		reply := tgbotapi.NewMessage(message.Chat.ID, text)
-		reply.ReplyMarkup = tgbotapi.ReplyKeyboardMarkup{
-			Keyboard:        buttons,
-			ResizeKeyboard:  true,
-			OneTimeKeyboard: true,
-		}
-
-		if _, err := b.api.Send(reply); err != nil {
-			log.Printf("❌ Ошибка отправки: %v", err)
-		}
-
 		// Сохраняем имя банка и название группы в ParsedData
 		b.setState(message.From.ID, StateAwaitingBankCorrection, &ParsedData{BankName: similar, Category: groupName}, nil, 0)
+		b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, buttons)
 		return
 	}
 
@@ -0,0 +1,49 @@
+package bot
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestBatchInChunks(t *testing.T) {
+	var sizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req models.BatchRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		sizes = append(sizes, len(req.Operations))
+
+		// Второй пакет API отклоняет целиком
+		if len(sizes) == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(models.ErrorResponse{Error: "Ошибка"})
+			return
+		}
+
+		resp := models.BatchResponse{Mode: req.Mode, Committed: true}
+		for i := range req.Operations {
+			resp.Results = append(resp.Results, models.BatchResult{Index: i, Status: models.BatchStatusOK})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	operations := make([]models.BatchOperation, 2*BatchMaxOperations+5)
+	results := batchInChunks(NewAPIClient(server.URL), operations)
+
+	if len(sizes) != 3 || sizes[0] != BatchMaxOperations || sizes[2] != 5 {
+		t.Fatalf("batchInChunks() размеры пакетов = %v", sizes)
+	}
+	if len(results) != len(operations) {
+		t.Fatalf("batchInChunks() вернул %d результатов, ожидалось %d", len(results), len(operations))
+	}
+	for i, result := range results {
+		failed := i >= BatchMaxOperations && i < 2*BatchMaxOperations
+		if result.Index != i || (result.Status == models.BatchStatusError) != failed {
+			t.Fatalf("batchInChunks()[%d] = %+v", i, result)
+		}
+	}
+}
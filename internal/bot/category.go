@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// requestedLevel — совпадение на самой запрошенной категории.
+func requestedLevel(category string) models.CategoryMatch {
+	return models.CategoryMatch{RequestedCategory: category, Category: category, Path: []string{category}}
+}
+
+// categoryPath получает путь категории к корню дерева.
+// Если API недоступен, остаётся прежний запасной вариант — "Все покупки".
+func (b *Bot) categoryPath(category string) []string {
+	path, err := b.client.GetCategoryPath(category)
+	if err != nil || len(path) == 0 {
+		log.Printf("⚠️ Не удалось получить путь категории '%s': %v", category, err)
+		return []string{category, models.AllPurchasesCategory}
+	}
+	return path
+}
+
+// findParentCashbacks ищет кэшбэки на родительских категориях запрошенной.
+// Возвращает правила уровня с самым выгодным кэшбэком (при равенстве —
+// ближайшего к категории) и объяснение уровня.
+func (b *Bot) findParentCashbacks(groupName, category, monthYear string) ([]models.CashbackRule, models.CategoryMatch) {
+	path := b.categoryPath(category)
+
+	var best []models.CashbackRule
+	var match models.CategoryMatch
+
+	for level := 1; level < len(path); level++ {
+		rules, err := b.getAllCashbacksByCategory(groupName, path[level], monthYear)
+		if err != nil {
+			continue
+		}
+
+		// На родительских уровнях нужна сама категория, а не её соседи по подстроке
+		exact := rules[:0]
+		for _, rule := range rules {
+			if strings.EqualFold(rule.Category, path[level]) {
+				exact = append(exact, rule)
+			}
+		}
+		if len(exact) == 0 {
+			continue
+		}
+
+		if best == nil || exact[0].EffectivePercent > best[0].EffectivePercent {
+			best = exact
+			match = models.CategoryMatch{
+				RequestedCategory: category,
+				Category:          path[level],
+				Level:             level,
+				Path:              path,
+			}
+		}
+	}
+
+	return best, match
+}
+
+// sendParentCashbacks показывает кэшбэк на родительских категориях
+// или сообщение, что кэшбэк не найден.
+func (b *Bot) sendParentCashbacks(message *tgbotapi.Message, groupName, category, monthYear string) {
+	rules, match := b.findParentCashbacks(groupName, category, monthYear)
+	if len(rules) == 0 {
+		log.Printf("❌ На родительских категориях '%s' кэшбэк тоже не найден", category)
+		b.sendText(message.Chat.ID, formatNotFoundMessage(category, monthYear))
+		return
+	}
+
+	log.Printf("✅ Найдено %d кешбеков на категории '%s' (уровень %d)", len(rules), match.Category, match.Level)
+	summary, best := b.personalBestSummary(message.From.ID, groupName, category, monthYear)
+	b.sendText(message.Chat.ID, formatAllCashbackResults(rules, match)+summary)
+	b.offerPaymentRequest(message, best)
+}
+
+// formatCategoryPath показывает путь от запрошенной категории до найденной:
+// "Фастфуд → Рестораны и кафе".
+func formatCategoryPath(match models.CategoryMatch) string {
+	if match.Level < len(match.Path) {
+		return strings.Join(match.Path[:match.Level+1], " → ")
+	}
+	return match.RequestedCategory + " → " + match.Category
+}
+
+// formatFallbackHeader объясняет, что кэшбэк найден выше по дереву категорий.
+func formatFallbackHeader(match models.CategoryMatch) string {
+	return fmt.Sprintf("💡 Кэшбэк для категории \"%s\" не найден.\n"+
+		"🌳 %s\n"+
+		"Показываю кэшбэк на \"%s\"", match.RequestedCategory, formatCategoryPath(match), match.Category)
+}
+
+// formatParentLevelHint подсказывает, что на родительской категории кэшбэк выгоднее.
+func formatParentLevelHint(rule *models.CashbackRule, match models.CategoryMatch) string {
+	return fmt.Sprintf("\n💡 Выгоднее уровнем выше — \"%s\" (%s):\n"+
+		"🏦 %s — %s до %.0f₽, 👤 %s, 🆔 ID: %d",
+		match.Category, formatCategoryPath(match),
+		rule.BankName, formatRewardPercent(rule), rule.MaxAmount, rule.UserDisplayName, rule.ID)
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatFallbackShowsCategoryPath(t *testing.T) {
+	match := models.CategoryMatch{
+		RequestedCategory: "Фастфуд",
+		Category:          "Рестораны и кафе",
+		Level:             1,
+		Path:              []string{"Фастфуд", "Рестораны и кафе", "Все покупки"},
+	}
+	rule := models.CashbackRule{BankName: "Тинькофф", Category: "Рестораны и кафе", CashbackPercent: 5, EffectivePercent: 5}
+
+	if got := formatCategoryPath(match); got != "Фастфуд → Рестораны и кафе" {
+		t.Errorf("formatCategoryPath() = %q", got)
+	}
+
+	for name, text := range map[string]string{
+		"formatBestCashback":       formatBestCashback(&rule, match),
+		"formatAllCashbackResults": formatAllCashbackResults([]models.CashbackRule{rule}, match),
+	} {
+		if !strings.Contains(text, "🌳 Фастфуд → Рестораны и кафе") || !strings.Contains(text, "\"Фастфуд\" не найден") {
+			t.Errorf("%s() не объясняет уровень дерева: %q", name, text)
+		}
+	}
+
+	if text := formatBestCashback(&rule, requestedLevel("Рестораны и кафе")); strings.Contains(text, "🌳") {
+		t.Errorf("formatBestCashback() без подъёма по дереву показывает путь: %q", text)
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/rymax1e/open-cashback-advisor/internal/models"
@@ -139,14 +142,83 @@ func (c *APIClient) Suggest(req *models.SuggestRequest) (*models.SuggestResponse
 }
 
 // CreateCashback создаёт новое правило кэшбэка.
+// Если такое правило уже есть, возвращает *DuplicateError.
 func (c *APIClient) CreateCashback(req *models.CreateCashbackRequest) (*models.CashbackRule, error) {
 	body, statusCode, err := c.post(EndpointCashback, req)
 	if err != nil {
 		return nil, err
 	}
+
+	if statusCode == http.StatusConflict {
+		var conflict models.ConflictResponse
+		if err := json.Unmarshal(body, &conflict); err == nil && conflict.Existing != nil {
+			return nil, &DuplicateError{Existing: conflict.Existing, Identical: conflict.Identical}
+		}
+	}
 	return parseResponse[models.CashbackRule](body, statusCode, http.StatusCreated)
 }
 
+// BatchCashback выполняет пакет операций над правилами в одной транзакции.
+func (c *APIClient) BatchCashback(req *models.BatchRequest) (*models.BatchResponse, error) {
+	body, statusCode, err := c.post(EndpointCashbackBatch, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.BatchResponse](body, statusCode, http.StatusOK)
+}
+
+// ImportCashback загружает CSV/XLSX файл с правилами кэшбэка.
+// При dryRun правила не сохраняются, возвращается только отчёт о проверке.
+func (c *APIClient) ImportCashback(req *models.ImportRequest) (*models.ImportReport, error) {
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+
+	fields := map[string]string{
+		"group_name":        req.GroupName,
+		"user_id":           req.UserID,
+		"user_display_name": req.UserDisplayName,
+		"dry_run":           strconv.FormatBool(req.DryRun),
+	}
+	for name, value := range fields {
+		if err := form.WriteField(name, value); err != nil {
+			return nil, fmt.Errorf("ошибка формирования запроса: %w", err)
+		}
+	}
+
+	part, err := form.CreateFormFile("file", req.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка формирования запроса: %w", err)
+	}
+	if _, err := part.Write(req.Data); err != nil {
+		return nil, fmt.Errorf("ошибка формирования запроса: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка формирования запроса: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, c.baseURL+EndpointCashbackImport, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", form.FormDataContentType())
+
+	body, statusCode, err := c.doRequest(httpReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusBadRequest {
+		var errResp models.ErrorResponse
+		if err := json.Unmarshal(body, &errResp); err == nil && len(errResp.Details) > 0 {
+			return nil, fmt.Errorf("%s", strings.Join(errResp.Details, "; "))
+		}
+	}
+	if statusCode == http.StatusCreated {
+		statusCode = http.StatusOK
+	}
+	return parseResponse[models.ImportReport](body, statusCode, http.StatusOK)
+}
+
 // GetCashbackByID получает правило по ID.
 func (c *APIClient) GetCashbackByID(id int64) (*models.CashbackRule, error) {
 	endpoint := fmt.Sprintf("%s/%d", EndpointCashback, id)
@@ -194,20 +266,345 @@ func (c *APIClient) ListCashback(groupName string, limit, offset int) (*models.L
 	return parseResponse[models.ListCashbackResponse](body, statusCode, http.StatusOK)
 }
 
-// GetBestCashback получает лучший кэшбэк.
-func (c *APIClient) GetBestCashback(groupName, category, monthYear string) (*models.CashbackRule, error) {
+// GetBestCashback получает лучший кэшбэк с уровнем дерева категорий, где он найден.
+// Если передан userID, в ответе также лучший кэшбэк по картам этого пользователя.
+func (c *APIClient) GetBestCashback(groupName, category, monthYear, userID string) (*models.BestCashbackResponse, error) {
 	params := url.Values{}
 	params.Add("group_name", groupName)
 	params.Add("category", category)
 	params.Add("month_year", monthYear)
+	if userID != "" {
+		params.Add("user_id", userID)
+	}
 
 	body, statusCode, err := c.get(EndpointCashbackBest, params)
 	if err != nil {
 		return nil, err
 	}
+	return parseResponse[models.BestCashbackResponse](body, statusCode, http.StatusOK)
+}
+
+// GetMerchantBestCashback ищет лучший кэшбэк по названию магазина:
+// сначала спецпредложения магазина, затем его категория и «Все покупки».
+// weekday (1-7) отсекает правила, которые в этот день не действуют.
+func (c *APIClient) GetMerchantBestCashback(groupName, merchant, monthYear string, weekday int) (*models.MerchantBestResponse, error) {
+	params := url.Values{}
+	params.Add("group_name", groupName)
+	params.Add("merchant", merchant)
+	params.Add("month_year", monthYear)
+	params.Add("weekday", strconv.Itoa(weekday))
+
+	body, statusCode, err := c.get(EndpointCashbackBestMerchant, params)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.MerchantBestResponse](body, statusCode, http.StatusOK)
+}
+
+// GetGroupStats получает аналитику группы за период. Пустые from и to —
+// период по умолчанию.
+func (c *APIClient) GetGroupStats(groupName, from, to string) (*models.GroupStats, error) {
+	params := url.Values{}
+	if from != "" {
+		params.Add("from", from)
+	}
+	if to != "" {
+		params.Add("to", to)
+	}
+
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupStats, url.PathEscape(groupName)), params)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.GroupStats](body, statusCode, http.StatusOK)
+}
+
+// historyParams собирает query параметры запроса истории правил группы.
+func historyParams(req *models.HistoryRequest) url.Values {
+	params := url.Values{}
+	for key, value := range map[string]string{
+		"bank": req.BankName, "category": req.Category, "from": req.From, "to": req.To,
+	} {
+		if value != "" {
+			params.Add(key, value)
+		}
+	}
+	return params
+}
+
+// GetGroupHistory получает правила группы за период, включая закончившиеся.
+func (c *APIClient) GetGroupHistory(req *models.HistoryRequest) ([]models.CashbackRule, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupHistory, url.PathEscape(req.GroupName)), historyParams(req))
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := parseResponse[[]models.CashbackRule](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+// GetBankTrend получает динамику процента и лимита банка на категорию по месяцам.
+func (c *APIClient) GetBankTrend(req *models.HistoryRequest) (*models.BankTrend, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupTrend, url.PathEscape(req.GroupName)), historyParams(req))
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.BankTrend](body, statusCode, http.StatusOK)
+}
+
+// GetGroupCoverage получает важные категории группы без выгодного кэшбэка.
+// Нулевой minPercent означает порог по умолчанию.
+func (c *APIClient) GetGroupCoverage(groupName, monthYear string, minPercent float64) (*models.CoverageReport, error) {
+	params := url.Values{}
+	params.Add("month_year", monthYear)
+	if minPercent > 0 {
+		params.Add("min_percent", strconv.FormatFloat(minPercent, 'f', -1, 64))
+	}
+
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupCoverage, url.PathEscape(groupName)), params)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.CoverageReport](body, statusCode, http.StatusOK)
+}
+
+// AdviseCategories подбирает участникам группы категории из меню банков на месяц.
+func (c *APIClient) AdviseCategories(groupName string, req *models.CategoryAdviceRequest) (*models.CategoryAdviceResponse, error) {
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointGroupAdvice, url.PathEscape(groupName)), req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.CategoryAdviceResponse](body, statusCode, http.StatusOK)
+}
+
+// GetDigestSettings получает расписание ежемесячной сводки группы.
+func (c *APIClient) GetDigestSettings(groupName string) (*models.DigestSettings, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupDigestSettings, url.PathEscape(groupName)), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.DigestSettings](body, statusCode, http.StatusOK)
+}
+
+// SetDigestSettings меняет расписание ежемесячной сводки группы.
+func (c *APIClient) SetDigestSettings(groupName string, req *models.DigestSettingsRequest) (*models.DigestSettings, error) {
+	body, statusCode, err := c.put(fmt.Sprintf(EndpointGroupDigestSettings, url.PathEscape(groupName)), req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.DigestSettings](body, statusCode, http.StatusOK)
+}
+
+// GetGroupDigest получает сводку группы за месяц, не отмечая её отправленной.
+func (c *APIClient) GetGroupDigest(groupName, monthYear string) (*models.GroupDigest, error) {
+	params := url.Values{}
+	if monthYear != "" {
+		params.Add("month_year", monthYear)
+	}
+
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupDigest, url.PathEscape(groupName)), params)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.GroupDigest](body, statusCode, http.StatusOK)
+}
+
+// ClaimGroupDigest получает сводку группы за месяц и отмечает её отправленной.
+// Если сводку уже забрал другой экземпляр бота, возвращает ErrDigestAlreadySent.
+func (c *APIClient) ClaimGroupDigest(groupName, monthYear string) (*models.GroupDigest, error) {
+	req := &models.DigestClaimRequest{MonthYear: monthYear}
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointGroupDigestClaim, url.PathEscape(groupName)), req)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusConflict {
+		return nil, ErrDigestAlreadySent
+	}
+	return parseResponse[models.GroupDigest](body, statusCode, http.StatusOK)
+}
+
+// ListDueDigests получает сводки, которые пора отправить, с их получателями.
+func (c *APIClient) ListDueDigests() ([]models.DigestDelivery, error) {
+	body, statusCode, err := c.get(EndpointDigestsDue, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries, err := parseResponse[[]models.DigestDelivery](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *deliveries, nil
+}
+
+// CreatePaymentRequest создаёт просьбу оплатить покупку картой из правила кэшбэка.
+func (c *APIClient) CreatePaymentRequest(groupName string, req *models.CreatePaymentRequest) (*models.PaymentRequest, error) {
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointPaymentRequests, url.PathEscape(groupName)), req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.PaymentRequest](body, statusCode, http.StatusCreated)
+}
+
+// AnswerPaymentRequest передаёт ответ владельца карты на просьбу оплатить.
+func (c *APIClient) AnswerPaymentRequest(id int64, userID string, accept bool) (*models.PaymentRequest, error) {
+	req := &models.PaymentAnswerRequest{UserID: userID, Accept: accept}
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointPaymentAnswer, id), req)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusConflict {
+		return nil, ErrPaymentAnswered
+	}
+	return parseResponse[models.PaymentRequest](body, statusCode, http.StatusOK)
+}
+
+// GetGroupBalances получает долги участников группы после взаимозачёта.
+func (c *APIClient) GetGroupBalances(groupName string) (*models.GroupBalances, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointGroupBalances, url.PathEscape(groupName)), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.GroupBalances](body, statusCode, http.StatusOK)
+}
+
+// SettleDebt подтверждает, что должник вернул пользователю userID показанный долг.
+// Если долг с тех пор изменился, возвращает ErrDebtChanged.
+func (c *APIClient) SettleDebt(groupName, userID string, debt *models.Debt) (*models.SettleDebtResponse, error) {
+	req := &models.SettleDebtRequest{
+		UserID:        userID,
+		DebtorID:      debt.DebtorID,
+		LastPaymentID: debt.LastPaymentID,
+		Amount:        debt.Amount,
+	}
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointSettleDebt, url.PathEscape(groupName)), req)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusCode == http.StatusConflict {
+		return nil, ErrDebtChanged
+	}
+	return parseResponse[models.SettleDebtResponse](body, statusCode, http.StatusOK)
+}
+
+// ListBankCaps получает общие лимиты банков пользователя с кэшбэком за текущий период.
+func (c *APIClient) ListBankCaps(userID string) ([]models.BankCapUsage, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointBankCaps, userID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	caps, err := parseResponse[[]models.BankCapUsage](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *caps, nil
+}
+
+// SetBankCap устанавливает общий лимит кэшбэка банка пользователя.
+func (c *APIClient) SetBankCap(userID, bankName string, req *models.BankCapRequest) (*models.BankCap, error) {
+	body, statusCode, err := c.put(fmt.Sprintf(EndpointBankCap, userID, url.PathEscape(bankName)), req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.BankCap](body, statusCode, http.StatusOK)
+}
+
+// DeleteBankCap удаляет общий лимит кэшбэка банка пользователя.
+func (c *APIClient) DeleteBankCap(userID, bankName string) error {
+	statusCode, err := c.delete(fmt.Sprintf(EndpointBankCap, userID, url.PathEscape(bankName)))
+	if err != nil {
+		return err
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrBankCapNotFound
+	default:
+		return fmt.Errorf("ошибка удаления лимита банка: статус %d", statusCode)
+	}
+}
+
+// RecordSpend записывает покупку пользователя в журнал трат.
+func (c *APIClient) RecordSpend(req *models.CreateSpendRequest) (*models.SpendResponse, error) {
+	body, statusCode, err := c.post(EndpointSpends, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.SpendResponse](body, statusCode, http.StatusCreated)
+}
+
+// ActivateCashback отмечает, что владелец включил категорию правила в приложении банка.
+func (c *APIClient) ActivateCashback(id int64, userID string) (*models.CashbackRule, error) {
+	req := &models.ActivateCashbackRequest{UserID: userID}
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointCashbackActivate, id), req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusNotFound:
+		return nil, ErrRuleNotFound
+	case http.StatusForbidden:
+		return nil, ErrNotRuleOwner
+	}
+	return parseResponse[models.CashbackRule](body, statusCode, http.StatusOK)
+}
+
+// ListDueActivations получает неактивированные правила, срок активации
+// которых скоро наступит.
+func (c *APIClient) ListDueActivations() ([]models.CashbackRule, error) {
+	body, statusCode, err := c.get(EndpointActivationsDue, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := parseResponse[[]models.CashbackRule](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+// ClaimActivationReminder отмечает напоминание об активации правила отправленным.
+func (c *APIClient) ClaimActivationReminder(id int64) (*models.CashbackRule, error) {
+	body, statusCode, err := c.post(fmt.Sprintf(EndpointActivationRemind, id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch statusCode {
+	case http.StatusNotFound:
+		return nil, ErrRuleNotFound
+	case http.StatusConflict:
+		return nil, ErrReminderAlreadySent
+	}
 	return parseResponse[models.CashbackRule](body, statusCode, http.StatusOK)
 }
 
+// GetCategoryPath получает путь категории к корню дерева категорий:
+// саму категорию, её родителей и "Все покупки".
+func (c *APIClient) GetCategoryPath(category string) ([]string, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointCategoryPath, url.PathEscape(category)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := parseResponse[[]string](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *path, nil
+}
+
 // ListAllCategories получает список всех уникальных категорий.
 func (c *APIClient) ListAllCategories(groupName, monthYear string) ([]string, error) {
 	params := url.Values{}
@@ -494,3 +891,119 @@ func (c *APIClient) GetGroupMembers(groupName string) ([]string, error) {
 
 	return result.Members, nil
 }
+
+// --- Методы для работы с групповыми чатами ---
+
+// ExportGroup выгружает правила группы в указанном формате (csv, xlsx, json, ics).
+func (c *APIClient) ExportGroup(groupName, format string) ([]byte, error) {
+	endpoint := fmt.Sprintf(EndpointGroupExport, url.PathEscape(groupName))
+	params := url.Values{}
+	params.Set("format", format)
+
+	body, statusCode, err := c.get(endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode != http.StatusOK {
+		return nil, parseAPIError(body, statusCode)
+	}
+	return body, nil
+}
+
+// GetChatGroup получает группу, к которой привязан групповой чат.
+func (c *APIClient) GetChatGroup(chatID int64) (string, error) {
+	endpoint := fmt.Sprintf(EndpointChatGroup, chatID)
+	body, statusCode, err := c.get(endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode == http.StatusNotFound {
+		return "", ErrChatNotBound
+	}
+
+	binding, err := parseResponse[models.ChatBinding](body, statusCode, http.StatusOK)
+	if err != nil {
+		return "", err
+	}
+	return binding.GroupName, nil
+}
+
+// BindChat привязывает групповой чат к группе.
+func (c *APIClient) BindChat(chatID int64, groupName, userID string) error {
+	endpoint := fmt.Sprintf(EndpointChatGroup, chatID)
+	payload := &models.BindChatRequest{
+		GroupName: groupName,
+		UserID:    userID,
+	}
+
+	body, statusCode, err := c.put(endpoint, payload)
+	if err != nil {
+		return err
+	}
+
+	if statusCode != http.StatusOK {
+		return parseAPIError(body, statusCode)
+	}
+	return nil
+}
+
+// UnbindChat отвязывает групповой чат от группы.
+func (c *APIClient) UnbindChat(chatID int64) error {
+	endpoint := fmt.Sprintf(EndpointChatGroup, chatID)
+	statusCode, err := c.delete(endpoint)
+	if err != nil {
+		return err
+	}
+
+	if statusCode == http.StatusNotFound {
+		return ErrChatNotBound
+	}
+	if statusCode != http.StatusOK {
+		return fmt.Errorf("ошибка отвязки чата: статус %d", statusCode)
+	}
+	return nil
+}
+
+// --- Методы для работы с картами ---
+
+// CreateCard добавляет карту пользователя.
+func (c *APIClient) CreateCard(req *models.CreateCardRequest) (*models.Card, error) {
+	body, statusCode, err := c.post(EndpointCards, req)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[models.Card](body, statusCode, http.StatusCreated)
+}
+
+// ListUserCards получает карты пользователя.
+func (c *APIClient) ListUserCards(userID string) ([]models.Card, error) {
+	body, statusCode, err := c.get(fmt.Sprintf(EndpointUserCards, userID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	cards, err := parseResponse[[]models.Card](body, statusCode, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	return *cards, nil
+}
+
+// DeleteCard удаляет карту пользователя.
+func (c *APIClient) DeleteCard(id int64, userID string) error {
+	endpoint := fmt.Sprintf("%s/%d?user_id=%s", EndpointCards, id, url.QueryEscape(userID))
+	statusCode, err := c.delete(endpoint)
+	if err != nil {
+		return err
+	}
+
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound, http.StatusForbidden:
+		return ErrCardNotFound
+	default:
+		return fmt.Errorf("ошибка удаления карты: статус %d", statusCode)
+	}
+}
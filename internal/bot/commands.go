@@ -45,12 +45,15 @@ var commandHelpMap = map[string]CommandHelp{
 		LongDesc: "Добавляет новый кэшбэк в базу данных группы.\n\n" +
 			"Сначала отправьте команду /add, затем введите данные в формате:\n" +
 			"Банк, Категория, Процент, Сумма[, Дата окончания]\n\n" +
-			"Поддерживается мультистрочный ввод - вы можете добавить несколько кэшбэков одним сообщением.",
+			"Поддерживается мультистрочный ввод - вы можете добавить несколько кэшбэков одним сообщением.\n\n" +
+			"Если категорию нужно включить в приложении банка, добавьте \"активация\" или \"активировать до 05.12\" — " +
+			"бот напомнит об активации за несколько дней до срока.",
 		Usage: "/add (затем отправьте данные через запятую)",
 		Examples: []string{
 			"/add",
 			"→ Тинькофф, Такси, 5%, 3000",
 			"→ Сбер, Супермаркеты, 10, 5000, 31.01.2025",
+			"→ Альфа, Рестораны, 7, 3000, активировать до 05.12",
 		},
 	},
 	"best": {
@@ -137,6 +140,111 @@ var commandHelpMap = map[string]CommandHelp{
 			"/userlist 1,3,5 - пользователи 1, 3 и 5",
 		},
 	},
+	"export": {
+		Name:      "/export",
+		ShortDesc: "Выгрузить кэшбэки группы в файл",
+		LongDesc: "Присылает все кэшбэки группы файлом.\n\n" +
+			"Форматы: csv и xlsx — таблица для Excel и Google Sheets (её можно загрузить обратно), " +
+			"json — для скриптов, ics — календарь, где окончание каждого кэшбэка — событие.\n\n" +
+			"Без параметров бот предложит выбрать формат кнопками.",
+		Usage:    "/export [csv|xlsx|json|ics]",
+		Examples: []string{"/export", "/export xlsx", "/export ics"},
+	},
+	"addcard": {
+		Name:      "/addcard",
+		ShortDesc: "Добавить свою карту",
+		LongDesc: "Добавляет вашу банковскую карту: банк, платёжную систему, последние 4 цифры и, по желанию, название.\n\n" +
+			"Если у вас одна карта банка, новые кэшбэки этого банка привязываются к ней сами. " +
+			"Если карт несколько, укажите нужную в сообщении с кэшбэком: \"Сбер, Такси, 5, 3000, ***1234\".",
+		Usage:    "/addcard Банк, Платёжная система, 1234[, Название]",
+		Examples: []string{"/addcard Сбер, Мир, 1234", "/addcard Сбер, Visa, 5678, Кредитная"},
+	},
+	"cards": {
+		Name:      "/cards",
+		ShortDesc: "Ваши карты",
+		LongDesc:  "Показывает ваши карты с ID. Удалённая карта пропадает из кэшбэков, сами кэшбэки остаются.",
+		Usage:     "/cards [удалить ID]",
+		Examples:  []string{"/cards", "/cards удалить 3"},
+	},
+	"advice": {
+		Name:      "/advice",
+		ShortDesc: "Какие категории выбрать в банке",
+		LongDesc: "Подбирает каждому участнику категории из меню банков на текущий месяц (Тинькофф, Альфа и другие банки, " +
+			"где нужно выбрать 3–4 категории), чтобы группа покрыла больше покупок с большим кэшбэком.\n\n" +
+			"Учитываются кэшбэки, которые уже есть у группы, и категории, которые группа выбирала раньше. " +
+			"Можно указать свои траты по категориям в месяц — тогда совет считается в рублях.",
+		Usage:    "/advice [Категория сумма, ...]",
+		Examples: []string{"/advice", "/advice Аптеки 5000, Такси 3000, Рестораны 8000"},
+	},
+	"gaps": {
+		Name:      "/gaps",
+		ShortDesc: "Где группа недополучает кэшбэк",
+		LongDesc: "Показывает важные для группы категории (из справочника категорий и тех, где у группы раньше был кэшбэк), " +
+			"на которые в этом месяце нет кэшбэка или есть только слабый — ниже порога (по умолчанию 3%).\n\n" +
+			"Рядом с каждой категорией — лучший вариант, который есть сейчас, в том числе на родительскую категорию.",
+		Usage:    "/gaps [порог в %]",
+		Examples: []string{"/gaps", "/gaps 5"},
+	},
+	"stats": {
+		Name:      "/stats",
+		ShortDesc: "Статистика кэшбэка группы",
+		LongDesc: "Показывает статистику кэшбэков группы по месяцам, участникам, банкам и категориям: " +
+			"сколько кэшбэков, средний процент, сколько можно заработать и как это меняется от месяца к месяцу.\n\n" +
+			"По умолчанию — за последние 6 месяцев.",
+		Usage:    "/stats [число месяцев]",
+		Examples: []string{"/stats", "/stats 12"},
+	},
+	"trend": {
+		Name:      "/trend",
+		ShortDesc: "Как менялся кэшбэк банка на категорию",
+		LongDesc: "Показывает по месяцам, какой процент и лимит давал банк на категорию участникам группы " +
+			"за последние 12 месяцев, включая закончившиеся кэшбэки, и как они менялись. " +
+			"Помогает решить, какие карты стоит оставить.\n\n" +
+			"Если название банка из нескольких слов, разделите банк и категорию запятой.",
+		Usage:    "/trend (банк) (категория)",
+		Examples: []string{"/trend Тинькофф Такси", "/trend Альфа Банк, Рестораны"},
+	},
+	"digest": {
+		Name:      "/digest",
+		ShortDesc: "Ежемесячная сводка группы",
+		LongDesc: "В конце месяца бот присылает группе сводку: какие кэшбэки заканчиваются, лучшие кэшбэки " +
+			"на следующий месяц и категории, на которые выгодного кэшбэка нет. Сводка приходит в привязанный " +
+			"групповой чат, а если его нет — каждому участнику лично.\n\n" +
+			"Без аргументов показывает сводку за текущий месяц и расписание. По умолчанию сводка приходит " +
+			"в последний день месяца в 19:00.",
+		Usage:    "/digest [день ЧЧ:ММ | последний ЧЧ:ММ | выкл | вкл]",
+		Examples: []string{"/digest", "/digest 28 20:00", "/digest последний 19:00", "/digest выкл"},
+	},
+	"balance": {
+		Name:      "/balance",
+		ShortDesc: "Кто кому должен в группе",
+		LongDesc: "Если лучшая карта для покупки у другого участника, под ответом /best появляется кнопка " +
+			"«💸 Попросить оплатить»: бот спросит сумму и отправит владельцу карты просьбу с кнопками " +
+			"«Оплачу» и «Не могу». Принятые просьбы превращаются в долги.\n\n" +
+			"/balance показывает долги группы после взаимозачёта. Когда вам вернули деньги, " +
+			"нажмите кнопку под сообщением — долг закроется.",
+		Usage:    "/balance",
+		Examples: []string{"/balance"},
+	},
+	"bankcap": {
+		Name:      "/bankcap",
+		ShortDesc: "Общий лимит кэшбэка банка",
+		LongDesc: "Некоторые банки ограничивают кэшбэк сразу на все категории, например 5000₽ в месяц. " +
+			"Задайте такой лимит для своих карт — бот будет считать заработанный кэшбэк по покупкам из /spent " +
+			"и просьб оплатить и предупредит в /best, когда лимит почти исчерпан. Карту с исчерпанным " +
+			"лимитом бот советует, только если других вариантов нет.\n\n" +
+			"Без аргументов показывает ваши лимиты и остаток за текущий период. По умолчанию лимит месячный.",
+		Usage:    "/bankcap [банк сумма [месяц | квартал] | банк выкл]",
+		Examples: []string{"/bankcap", "/bankcap Тинькофф 5000", "/bankcap Альфа Банк 9000 квартал", "/bankcap Тинькофф выкл"},
+	},
+	"spent": {
+		Name:      "/spent",
+		ShortDesc: "Записать покупку по своей карте",
+		LongDesc: "Записывает покупку по вашей карте с лучшим кэшбэком на категорию и показывает, " +
+			"сколько кэшбэка она принесла с учётом лимита правила и общего лимита банка (/bankcap).",
+		Usage:    "/spent (сумма) (категория)",
+		Examples: []string{"/spent 1500 Такси", "/spent 820,50 Аптеки"},
+	},
 	"creategroup": {
 		Name:      "/creategroup",
 		ShortDesc: "Создать новую группу",
@@ -267,22 +375,39 @@ func (b *Bot) handleHelp(message *tgbotapi.Message) {
 • /list — Список всех кэшбеков группы
 • /update — Обновить свой кешбек
 • /delete — Удалить свой кешбек
+• /addcard — Добавить свою карту
+• /cards — Ваши карты
+• /bankcap — Общий лимит кэшбэка банка
+• /spent — Записать покупку по своей карте
 
 🔍 Поиск информации:
 • /best — Найти лучший кэшбэк для категории
 • /bankinfo — Все кэшбэки конкретного банка
 • /categorylist — Список всех категорий
 • /banklist — Список всех банков
+• /advice — Какие категории выбрать в меню банков
+• /gaps — Категории без выгодного кэшбэка
+• /stats — Статистика кэшбэка группы
+• /trend — Как менялся кэшбэк банка на категорию
+• /digest — Ежемесячная сводка группы
 
 👤 Пользователи:
 • /userinfo — Кэшбэки конкретного пользователя
 • /userlist — Список всех участников группы
+• /balance — Кто кому должен за оплату чужой картой
+
+📤 Выгрузка:
+• /export — Выгрузить кэшбэки группы в CSV, XLSX, JSON или календарь
+• Отправьте CSV/XLSX файл — импорт кэшбэков из таблицы
 
 ⚙️ Другое:
 • /cancel — Отменить текущую операцию
 • /start — Показать приветствие
 
-ℹ️ Версия: %s`, BuildInfo())
+💬 Inline-режим:
+Напишите в любом чате @%s категория, например "аптеки", — бот предложит лучшие карты группы.
+
+ℹ️ Версия: %s`, b.api.Self.UserName, BuildInfo())
 
 	b.sendText(message.Chat.ID, text)
 }
@@ -379,6 +504,12 @@ func (b *Bot) handleList(message *tgbotapi.Message) {
 		return
 	}
 
+	// По умолчанию показываем первую страницу с inline навигацией
+	if !showAll && indices == nil {
+		b.showListPage(message, 0, 0)
+		return
+	}
+
 	// Получаем все записи группы
 	list, err := b.client.ListCashback(groupName, 1000, 0)
 	if err != nil {
@@ -390,13 +521,6 @@ func (b *Bot) handleList(message *tgbotapi.Message) {
 	var filtered []models.CashbackRule
 	if showAll {
 		filtered = list.Rules
-	} else if indices == nil {
-		// По умолчанию - последние 5 (т.к. list.Rules уже отсортирован по created_at DESC)
-		limit := 5
-		if len(list.Rules) < limit {
-			limit = len(list.Rules)
-		}
-		filtered = list.Rules[:limit]
 	} else {
 		// Выбираем по индексам
 		for _, idx := range indices {
@@ -413,23 +537,48 @@ func (b *Bot) handleList(message *tgbotapi.Message) {
 
 	text := formatCashbackListTable(filtered, list.Total, showAll, indices)
 
-	// Ограничение Telegram ~4096 символов. Если слишком длинно, показываем последнее 5 по умолчанию.
+	// Ограничение Telegram ~4096 символов. Если слишком длинно, показываем постраничный список.
 	if len(text) > 3800 {
-		log.Printf("⚠️ /list ответ слишком длинный (%d символов), показываю последние 5", len(text))
-		// Берем последние 5 записей
-		limit := 5
-		if len(list.Rules) < limit {
-			limit = len(list.Rules)
-		}
-		fallback := list.Rules[:limit]
-		b.sendText(message.Chat.ID, "⚠️ Список слишком длинный, показываю последние 5 записей.\nИспользуйте /list 1-20 или кнопки навигации.")
-		b.sendTextPlain(message.Chat.ID, formatCashbackListTable(fallback, list.Total, false, nil))
+		log.Printf("⚠️ /list ответ слишком длинный (%d символов), показываю постраничный список", len(text))
+		b.sendText(message.Chat.ID, "⚠️ Список слишком длинный, показываю по страницам.\nИспользуйте /list 1-20 или кнопки навигации.")
+		b.showListPage(message, 0, 0)
 		return
 	}
 
 	b.sendTextPlain(message.Chat.ID, text)
 }
 
+// showListPage показывает страницу списка кэшбэков группы с inline навигацией.
+// Если editMessageID не равен 0, страница заменяет содержимое исходного сообщения.
+func (b *Bot) showListPage(message *tgbotapi.Message, page int, editMessageID int) {
+	groupName := b.resolveGroup(message)
+	if groupName == "" {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	if page < 0 {
+		page = 0
+	}
+
+	list, err := b.client.ListCashback(groupName, ListPageSize, page*ListPageSize)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка: %s", err))
+		return
+	}
+
+	totalPages := (list.Total + ListPageSize - 1) / ListPageSize
+	text := formatCashbackListPage(list.Rules, list.Total, page, totalPages)
+	buttons := listPageButtons(page, totalPages)
+
+	if editMessageID != 0 {
+		b.editMessageWithInlineButtons(message.Chat.ID, editMessageID, message.From.ID, text, buttons)
+		return
+	}
+
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, buttons)
+}
+
 // handleUpdateCommand обрабатывает команду /update ID.
 func (b *Bot) handleUpdateCommand(message *tgbotapi.Message) {
 	args := strings.Fields(message.Text)
@@ -462,13 +611,7 @@ func (b *Bot) handleUpdateCommand(message *tgbotapi.Message) {
 	b.sendText(message.Chat.ID, formatUpdatePrompt(rule))
 	
 	// Отправляем второе сообщение только со строкой для копирования
-	copyLine := fmt.Sprintf("%s, %s, %.1f, %.0f, %s",
-		rule.BankName,
-		rule.Category,
-		rule.CashbackPercent,
-		rule.MaxAmount,
-		rule.MonthYear.Format("02.01.2006"),
-	)
+	copyLine := formatUpdateCopyLine(rule)
 	b.sendTextPlain(message.Chat.ID, copyLine)
 	
 	b.setState(message.From.ID, StateAwaitingUpdateData, nil, nil, id)
@@ -502,8 +645,8 @@ func (b *Bot) handleDeleteCommand(message *tgbotapi.Message) {
 		return
 	}
 
-	b.sendWithButtons(message.Chat.ID, formatDeletePrompt(rule), ButtonsDelete)
 	b.setState(message.From.ID, StateAwaitingDeleteConfirm, nil, nil, id)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, formatDeletePrompt(rule), deleteButtons(id))
 }
 
 // handleCancel обрабатывает команду /cancel.
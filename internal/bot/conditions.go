@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// Паттерны сумм в условиях: "траты от 10000₽" и "от 1000₽".
+var (
+	monthlySpendPattern = regexp.MustCompile(`(?i)трат\S*\s+от\s+(\d[\d\s]*(?:[.,]\d+)?)\s*(?:₽|руб\S*|р\b)?`)
+	minPurchasePattern  = regexp.MustCompile(`(?i)(?:^|\s)от\s+(\d[\d\s]*(?:[.,]\d+)?)\s*(?:₽|руб\S*|р\b)?`)
+)
+
+// weekdayNames — короткие названия дней недели (1 — понедельник).
+var weekdayNames = []string{"", "пн", "вт", "ср", "чт", "пт", "сб", "вс"}
+
+// paymentMethodNames — названия способов оплаты для показа пользователю.
+var paymentMethodNames = map[string]string{
+	models.PaymentMethodCard: "карта",
+	models.PaymentMethodSBP:  "СБП",
+	models.PaymentMethodQR:   "QR",
+}
+
+// conditionFillers — служебные слова, которые можно писать между условиями.
+var conditionFillers = map[string]bool{
+	"по": true, "в": true, "через": true, "только": true, "при": true,
+	"и": true, "дни": true, "оплата": true, "оплате": true, "покупка": true, "покупки": true,
+}
+
+// parseConditions разбирает условия правила из текста,
+// например "от 1000₽ траты от 10000₽ выходные сбп".
+func parseConditions(text string) (models.RuleConditions, error) {
+	var c models.RuleConditions
+
+	if match := monthlySpendPattern.FindStringSubmatch(text); match != nil {
+		amount, err := parseConditionAmount(match[1])
+		if err != nil {
+			return c, err
+		}
+		c.MinMonthlySpend = amount
+		text = strings.Replace(text, match[0], " ", 1)
+	}
+
+	if match := minPurchasePattern.FindStringSubmatch(text); match != nil {
+		amount, err := parseConditionAmount(match[1])
+		if err != nil {
+			return c, err
+		}
+		c.MinPurchase = amount
+		text = strings.Replace(text, match[0], " ", 1)
+	}
+
+	var unknown []string
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,;:")
+		switch {
+		case word == "" || conditionFillers[word]:
+		case strings.HasPrefix(word, "выходн"):
+			c.Weekdays = append(c.Weekdays, 6, 7)
+		case strings.HasPrefix(word, "будн"):
+			c.Weekdays = append(c.Weekdays, 1, 2, 3, 4, 5)
+		case weekdayIndex(word) > 0:
+			c.Weekdays = append(c.Weekdays, weekdayIndex(word))
+		case word == "сбп" || word == "sbp":
+			c.PaymentMethods = append(c.PaymentMethods, models.PaymentMethodSBP)
+		case word == "qr" || strings.HasPrefix(word, "qr-"):
+			c.PaymentMethods = append(c.PaymentMethods, models.PaymentMethodQR)
+		case strings.HasPrefix(word, "карт"):
+			c.PaymentMethods = append(c.PaymentMethods, models.PaymentMethodCard)
+		default:
+			unknown = append(unknown, word)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return c, fmt.Errorf("не удалось распознать условия: %s", strings.Join(unknown, ", "))
+	}
+
+	return c, nil
+}
+
+// parseConditionAmount разбирает сумму условия ("10 000", "999,50").
+func parseConditionAmount(value string) (float64, error) {
+	value = strings.ReplaceAll(value, " ", "")
+	value = strings.ReplaceAll(value, ",", ".")
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("неверная сумма в условиях: %s", value)
+	}
+	return amount, nil
+}
+
+// weekdayIndex возвращает номер дня недели по короткому названию или 0.
+func weekdayIndex(word string) int {
+	for i, name := range weekdayNames {
+		if i > 0 && word == name {
+			return i
+		}
+	}
+	return 0
+}
+
+// conditionsPtr возвращает условия для запроса к API или nil, если их нет.
+func conditionsPtr(c models.RuleConditions) *models.RuleConditions {
+	if c.IsEmpty() {
+		return nil
+	}
+	return &c
+}
+
+// formatConditions форматирует условия в том же виде, в каком их принимает
+// parseConditions: "от 1000₽ траты от 10000₽ сб вс сбп".
+func formatConditions(c models.RuleConditions) string {
+	var parts []string
+
+	if c.MinPurchase > 0 {
+		parts = append(parts, fmt.Sprintf("от %.0f₽", c.MinPurchase))
+	}
+	if c.MinMonthlySpend > 0 {
+		parts = append(parts, fmt.Sprintf("траты от %.0f₽", c.MinMonthlySpend))
+	}
+	for _, day := range c.Weekdays {
+		if day >= 1 && day <= 7 {
+			parts = append(parts, weekdayNames[day])
+		}
+	}
+	for _, method := range c.PaymentMethods {
+		if name, ok := paymentMethodNames[method]; ok {
+			parts = append(parts, name)
+		} else {
+			parts = append(parts, method)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatConditionsLine возвращает строку "📌 Условия: ..." или пустую строку.
+func formatConditionsLine(c models.RuleConditions, indent string) string {
+	if c.IsEmpty() {
+		return ""
+	}
+	return fmt.Sprintf("\n%s📌 Условия: %s", indent, formatConditions(c))
+}
+
+// isoWeekday возвращает номер дня недели: 1 — понедельник … 7 — воскресенье.
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
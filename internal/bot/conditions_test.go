@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestParseConditions(t *testing.T) {
+	tests := []struct {
+		input string
+		want  models.RuleConditions
+	}{
+		{"от 1000₽", models.RuleConditions{MinPurchase: 1000}},
+		{"при тратах от 10 000 руб", models.RuleConditions{MinMonthlySpend: 10000}},
+		{"только по выходным через СБП", models.RuleConditions{
+			Weekdays: []int{6, 7}, PaymentMethods: []string{models.PaymentMethodSBP}}},
+		{"от 500₽ траты от 20000₽ пн ср qr", models.RuleConditions{
+			MinPurchase: 500, MinMonthlySpend: 20000, Weekdays: []int{1, 3},
+			PaymentMethods: []string{models.PaymentMethodQR}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseConditions(tt.input)
+			if err != nil {
+				t.Fatalf("parseConditions() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConditions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseConditions("по праздникам"); err == nil {
+		t.Error("parseConditions() должна сообщать о нераспознанных условиях")
+	}
+}
+
+func TestFormatConditionsRoundTrip(t *testing.T) {
+	c := models.RuleConditions{MinPurchase: 1000, MinMonthlySpend: 10000, Weekdays: []int{6, 7},
+		PaymentMethods: []string{models.PaymentMethodSBP, models.PaymentMethodQR}}
+
+	got, err := parseConditions(formatConditions(c))
+	if err != nil {
+		t.Fatalf("parseConditions(%q) error = %v", formatConditions(c), err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("round trip = %+v, want %+v", got, c)
+	}
+}
+
+func TestParseMessageConditions(t *testing.T) {
+	data, err := ParseMessage("Альфа, Рестораны, 10, 3000, 31.12.2099, от 1500₽, сб, вс")
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	want := models.RuleConditions{MinPurchase: 1500, Weekdays: []int{6, 7}}
+	if !reflect.DeepEqual(data.Conditions, want) {
+		t.Errorf("Conditions = %+v, want %+v", data.Conditions, want)
+	}
+}
@@ -2,7 +2,13 @@ package bot
 
 import (
 	"fmt"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // Константы переменных окружения.
@@ -10,12 +16,41 @@ const (
 	EnvTelegramToken = "TELEGRAM_BOT_TOKEN"
 	EnvAPIBaseURL    = "API_BASE_URL"
 	EnvBotDebug      = "BOT_DEBUG"
+	EnvStateStore    = "BOT_STATE_STORE"
+	EnvStateTTL      = "BOT_STATE_TTL"
+	EnvWorkers       = "BOT_WORKERS"
+	EnvMode          = "BOT_MODE"
+	EnvWebhookURL    = "BOT_WEBHOOK_URL"
+	EnvWebhookListen = "BOT_WEBHOOK_LISTEN"
+	EnvWebhookSecret = "BOT_WEBHOOK_SECRET"
+	EnvTelegramAPI   = "TELEGRAM_API_ENDPOINT"
+	EnvTesseractPath = "BOT_TESSERACT_PATH"
+	EnvOCRLanguages  = "BOT_OCR_LANG"
+)
+
+// Режимы получения обновлений.
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// Типы хранилищ состояний.
+const (
+	StateStoreMemory   = "memory"
+	StateStorePostgres = "postgres"
 )
 
 // Значения по умолчанию.
 const (
 	DefaultAPIBaseURL = "http://localhost:8080"
 	DefaultDebug      = false
+	DefaultStateStore = StateStoreMemory
+	DefaultStateTTL   = 30 * time.Minute
+	DefaultWorkers    = 8
+	DefaultMode       = ModePolling
+	DefaultListen     = "0.0.0.0:8443"
+	DefaultTesseract  = "tesseract"
+	DefaultOCRLang    = "rus+eng"
 )
 
 // Config содержит настройки бота.
@@ -23,6 +58,23 @@ type Config struct {
 	TelegramToken string
 	APIBaseURL    string
 	Debug         bool
+	StateStore    string        // Тип хранилища состояний: memory или postgres
+	StateTTL      time.Duration // Время жизни брошенного состояния диалога
+	Workers       int           // Количество воркеров обработки обновлений
+	Mode          string        // Режим получения обновлений: polling или webhook
+	Webhook       WebhookConfig // Настройки webhook (только для режима webhook)
+	TelegramAPI   string        // Шаблон URL Telegram Bot API (для тестов и локального Bot API сервера)
+	TesseractPath string        // Путь к исполняемому файлу tesseract для распознавания скриншотов
+	OCRLanguages  string        // Языки распознавания tesseract
+}
+
+// WebhookConfig содержит настройки режима webhook.
+// TLS терминируется перед ботом (reverse proxy или балансировщик),
+// сам бот слушает обычный HTTP.
+type WebhookConfig struct {
+	URL    string // Публичный HTTPS URL, который регистрируется в Telegram
+	Listen string // Адрес HTTP листенера бота
+	Secret string // Секрет для заголовка X-Telegram-Bot-Api-Secret-Token
 }
 
 // LoadConfig загружает конфигурацию из переменных окружения.
@@ -31,6 +83,18 @@ func LoadConfig() *Config {
 		TelegramToken: getEnv(EnvTelegramToken, ""),
 		APIBaseURL:    getEnv(EnvAPIBaseURL, DefaultAPIBaseURL),
 		Debug:         getEnv(EnvBotDebug, "false") == "true",
+		StateStore:    getEnv(EnvStateStore, DefaultStateStore),
+		StateTTL:      getDurationEnv(EnvStateTTL, DefaultStateTTL),
+		Workers:       getIntEnv(EnvWorkers, DefaultWorkers),
+		Mode:          getEnv(EnvMode, DefaultMode),
+		Webhook: WebhookConfig{
+			URL:    getEnv(EnvWebhookURL, ""),
+			Listen: getEnv(EnvWebhookListen, DefaultListen),
+			Secret: getEnv(EnvWebhookSecret, ""),
+		},
+		TelegramAPI:   getEnv(EnvTelegramAPI, tgbotapi.APIEndpoint),
+		TesseractPath: getEnv(EnvTesseractPath, DefaultTesseract),
+		OCRLanguages:  getEnv(EnvOCRLanguages, DefaultOCRLang),
 	}
 }
 
@@ -39,9 +103,60 @@ func (c *Config) Validate() error {
 	if c.TelegramToken == "" {
 		return fmt.Errorf("%s не установлен в переменных окружения", EnvTelegramToken)
 	}
+	if c.StateStore != StateStoreMemory && c.StateStore != StateStorePostgres {
+		return fmt.Errorf("%s должен быть %q или %q, получено %q",
+			EnvStateStore, StateStoreMemory, StateStorePostgres, c.StateStore)
+	}
+	if c.StateTTL <= 0 {
+		return fmt.Errorf("%s должен быть положительным", EnvStateTTL)
+	}
+	if c.Workers < 1 {
+		return fmt.Errorf("%s должен быть не меньше 1", EnvWorkers)
+	}
+
+	switch c.Mode {
+	case ModePolling:
+	case ModeWebhook:
+		if err := c.Webhook.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s должен быть %q или %q, получено %q",
+			EnvMode, ModePolling, ModeWebhook, c.Mode)
+	}
 	return nil
 }
 
+// webhookSecretPattern — допустимый формат секрета по требованиям Telegram.
+var webhookSecretPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// Validate проверяет настройки webhook.
+func (c *WebhookConfig) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("%s должен быть полным URL, получено %q", EnvWebhookURL, c.URL)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("%s должен использовать https (TLS терминируется перед ботом)", EnvWebhookURL)
+	}
+	if c.Listen == "" {
+		return fmt.Errorf("%s не может быть пустым", EnvWebhookListen)
+	}
+	if !webhookSecretPattern.MatchString(c.Secret) {
+		return fmt.Errorf("%s обязателен: 1-256 символов A-Z, a-z, 0-9, _ и -", EnvWebhookSecret)
+	}
+	return nil
+}
+
+// Path возвращает путь, на котором бот принимает обновления.
+func (c *WebhookConfig) Path() string {
+	u, err := url.Parse(c.URL)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
 // getEnv получает переменную окружения или возвращает значение по умолчанию.
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -49,3 +164,31 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getDurationEnv получает длительность из переменной окружения (например, "30m")
+// или возвращает значение по умолчанию, если переменная не задана или некорректна.
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue
+	}
+	return duration
+}
+
+// getIntEnv получает целое число из переменной окружения
+// или возвращает значение по умолчанию, если переменная не задана или некорректна.
+func getIntEnv(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return defaultValue
+	}
+	return number
+}
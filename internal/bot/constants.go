@@ -16,6 +16,54 @@ const (
 
 	// HTTPClientTimeout — таймаут HTTP клиента.
 	HTTPClientTimeout = 30 * time.Second
+
+	// ListPageSize — количество записей на странице /list.
+	ListPageSize = 5
+
+	// InlineResultsLimit — максимальное количество правил в ответе на inline-запрос.
+	InlineResultsLimit = 10
+
+	// InlineCacheTime — время кэширования ответа на inline-запрос в секундах.
+	InlineCacheTime = 30
+
+	// StateStoreTimeout — таймаут операций с хранилищем состояний.
+	StateStoreTimeout = 5 * time.Second
+
+	// StateCleanupInterval — интервал удаления истёкших состояний.
+	StateCleanupInterval = 5 * time.Minute
+
+	// WorkerQueueSize — размер очереди обновлений одного воркера.
+	WorkerQueueSize = 100
+
+	// WebhookReadTimeout — таймаут чтения заголовков webhook запроса.
+	WebhookReadTimeout = 10 * time.Second
+
+	// WebhookShutdownTimeout — время ожидания завершения webhook запросов при остановке.
+	WebhookShutdownTimeout = 10 * time.Second
+
+	// WebhookMaxBodySize — максимальный размер тела webhook запроса.
+	WebhookMaxBodySize = 1 << 20
+
+	// DigestCheckInterval — как часто проверять, не пора ли отправить ежемесячные сводки.
+	DigestCheckInterval = time.Minute
+
+	// ActivationCheckInterval — как часто проверять, кому напомнить об активации категорий.
+	ActivationCheckInterval = time.Hour
+
+	// OCRTimeout — таймаут распознавания одного скриншота.
+	OCRTimeout = 60 * time.Second
+
+	// OCRMaxImageSize — максимальный размер скриншота для распознавания.
+	OCRMaxImageSize = 10 << 20
+
+	// ImportMaxFileSize — максимальный размер CSV/XLSX файла для импорта.
+	ImportMaxFileSize = 5 << 20
+
+	// ImportPreviewRows — сколько строк файла показывать в предпросмотре.
+	ImportPreviewRows = 15
+
+	// BatchMaxOperations — сколько операций API принимает в одном пакетном запросе.
+	BatchMaxOperations = 100
 )
 
 // Пороги для fuzzy matching.
@@ -63,6 +111,8 @@ const (
 	EmojiStar        = "✨"
 	EmojiHandshake   = "🤝"
 	EmojiChart       = "📊"
+	EmojiNewspaper   = "🗞"
+	EmojiMoney       = "💸"
 )
 
 // Текстовые шаблоны ошибок.
@@ -99,6 +149,7 @@ const (
 	MsgKeepAsIs           = "Хорошо, оставляю как есть."
 	MsgSendAgain          = "Отправьте данные заново, если хотите продолжить."
 	MsgTryDifferentName   = "Хорошо, попробуйте ввести название категории по-другому."
+	MsgButtonExpired      = "⚠️ Кнопка устарела"
 )
 
 // Форматы дат.
@@ -113,9 +164,36 @@ const (
 	EndpointCashback       = "/api/v1/cashback"
 	EndpointCashbackSuggest = "/api/v1/cashback/suggest"
 	EndpointCashbackBest   = "/api/v1/cashback/best"
+	EndpointCashbackBestMerchant = "/api/v1/cashback/best/merchant"
+	EndpointCashbackImport = "/api/v1/cashback/import"
+	EndpointCashbackBatch  = "/api/v1/cashback/batch"
+	EndpointCashbackActivate = "/api/v1/cashback/%d/activate"
+	EndpointActivationRemind = "/api/v1/cashback/%d/activation/remind"
+	EndpointActivationsDue = "/api/v1/cashback/activations/due"
 	EndpointGroups         = "/api/v1/groups"
 	EndpointGroupsCheck    = "/api/v1/groups/check"
 	EndpointGroupsMembers  = "/api/v1/groups/members"
 	EndpointUserGroup      = "/api/v1/users/%s/group"
+	EndpointChatGroup      = "/api/v1/chats/%d/group"
+	EndpointGroupExport    = "/api/v1/groups/%s/export"
+	EndpointGroupAdvice    = "/api/v1/groups/%s/advice"
+	EndpointGroupCoverage  = "/api/v1/groups/%s/coverage"
+	EndpointGroupStats     = "/api/v1/groups/%s/stats"
+	EndpointGroupHistory   = "/api/v1/groups/%s/history"
+	EndpointGroupTrend     = "/api/v1/groups/%s/trend"
+	EndpointGroupDigest    = "/api/v1/groups/%s/digest"
+	EndpointGroupDigestClaim = "/api/v1/groups/%s/digest/claim"
+	EndpointGroupDigestSettings = "/api/v1/groups/%s/digest/settings"
+	EndpointDigestsDue     = "/api/v1/digests/due"
+	EndpointPaymentRequests = "/api/v1/groups/%s/payment-requests"
+	EndpointPaymentAnswer  = "/api/v1/payment-requests/%d/answer"
+	EndpointGroupBalances  = "/api/v1/groups/%s/balances"
+	EndpointSettleDebt     = "/api/v1/groups/%s/balances/settle"
+	EndpointBankCaps       = "/api/v1/users/%s/bank-caps"
+	EndpointBankCap        = "/api/v1/users/%s/bank-caps/%s"
+	EndpointSpends         = "/api/v1/spends"
+	EndpointCards          = "/api/v1/cards"
+	EndpointCategoryPath   = "/api/v1/categories/%s/path"
+	EndpointUserCards      = "/api/v1/users/%s/cards"
 )
 
@@ -0,0 +1,237 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// digestSectionSize — сколько правил и категорий показывать в разделе сводки.
+const digestSectionSize = 10
+
+// runDigests периодически проверяет, каким группам пора отправить
+// ежемесячную сводку, и отправляет её.
+func (b *Bot) runDigests(ctx context.Context) {
+	ticker := time.NewTicker(DigestCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		b.sendDueDigests()
+	}
+}
+
+// sendDueDigests отправляет сводки, время которых наступило. Сводка уходит
+// в привязанные чаты группы, а если их нет — каждому участнику лично.
+func (b *Bot) sendDueDigests() {
+	deliveries, err := b.client.ListDueDigests()
+	if err != nil {
+		log.Printf("❌ Ошибка получения сводок к отправке: %v", err)
+		return
+	}
+
+	for _, delivery := range deliveries {
+		digest, err := b.client.ClaimGroupDigest(delivery.GroupName, delivery.Month.Format(DateFormatYearMonth))
+		if err != nil {
+			if !errors.Is(err, ErrDigestAlreadySent) {
+				log.Printf("❌ Ошибка получения сводки группы %s: %v", delivery.GroupName, err)
+			}
+			continue
+		}
+
+		text := formatGroupDigest(digest)
+		for _, chatID := range digestRecipients(delivery) {
+			b.sendText(chatID, text)
+		}
+		log.Printf("🗞 Отправлена сводка группы %s за %s", delivery.GroupName, delivery.Month.Format(DateFormatDisplay))
+	}
+}
+
+// digestRecipients возвращает чаты, в которые нужно отправить сводку.
+func digestRecipients(delivery models.DigestDelivery) []int64 {
+	if len(delivery.ChatIDs) > 0 {
+		return delivery.ChatIDs
+	}
+
+	var chats []int64
+	for _, member := range delivery.Members {
+		chatID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		chats = append(chats, chatID)
+	}
+	return chats
+}
+
+// handleDigest обрабатывает команду /digest [день время | выкл | вкл].
+func (b *Bot) handleDigest(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName, err := b.client.GetUserGroup(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	settings, err := b.client.GetDigestSettings(groupName)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось получить расписание сводки: %v", err))
+		return
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		digest, err := b.client.GetGroupDigest(groupName, "")
+		if err != nil {
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось собрать сводку: %v", err))
+			return
+		}
+		b.sendText(message.Chat.ID, formatGroupDigest(digest)+"\n\n"+formatDigestSchedule(settings))
+		return
+	}
+
+	req, err := parseDigestArgs(args, settings)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ %v\n\nПример: /digest 28 20:00, /digest последний 19:00 или /digest выкл", err))
+		return
+	}
+
+	settings, err = b.client.SetDigestSettings(groupName, req)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось сохранить расписание сводки: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, "✅ Расписание сохранено.\n\n"+formatDigestSchedule(settings))
+}
+
+// parseDigestArgs разбирает аргументы /digest: "28 20:00", "последний 19:00",
+// "выкл" или "вкл". Не указанные поля берутся из текущего расписания.
+func parseDigestArgs(args string, current *models.DigestSettings) (*models.DigestSettingsRequest, error) {
+	req := &models.DigestSettingsRequest{
+		Day:     current.Day,
+		Time:    fmt.Sprintf("%02d:%02d", current.Hour, current.Minute),
+		Enabled: current.Enabled,
+	}
+
+	fields := strings.Fields(strings.ToLower(args))
+	switch fields[0] {
+	case "выкл", "off":
+		req.Enabled = false
+		return req, nil
+	case "вкл", "on":
+		req.Enabled = true
+		return req, nil
+	case "последний", "last":
+		req.Day = models.DigestDefaultDay
+	default:
+		day, err := strconv.Atoi(fields[0])
+		if err != nil || day < 1 || day > 28 {
+			return nil, fmt.Errorf("день должен быть числом от 1 до 28 или словом \"последний\"")
+		}
+		req.Day = day
+	}
+
+	if len(fields) > 1 {
+		if _, err := time.Parse("15:04", fields[1]); err != nil {
+			return nil, fmt.Errorf("время указывается как ЧЧ:ММ")
+		}
+		req.Time = fields[1]
+	}
+	req.Enabled = true
+
+	return req, nil
+}
+
+// formatDigestSchedule форматирует расписание сводки группы.
+func formatDigestSchedule(settings *models.DigestSettings) string {
+	if !settings.Enabled {
+		return "🔕 Ежемесячная сводка выключена. Включить: /digest вкл"
+	}
+
+	day := "в последний день месяца"
+	if settings.Day > 0 {
+		day = fmt.Sprintf("%d-го числа", settings.Day)
+	}
+	return fmt.Sprintf("📅 Сводка приходит %s в %02d:%02d. Изменить: /digest день ЧЧ:ММ",
+		day, settings.Hour, settings.Minute)
+}
+
+// formatGroupDigest форматирует итоги месяца для группы.
+func formatGroupDigest(digest *models.GroupDigest) string {
+	text := fmt.Sprintf("%s Итоги %s для группы <b>%s</b>\n",
+		EmojiNewspaper, digest.Month.Format("01.2006"), digest.GroupName)
+
+	if len(digest.Expired) == 0 {
+		text += "\n⌛ В этом месяце ни один кэшбэк не заканчивается.\n"
+	} else {
+		text += fmt.Sprintf("\n⌛ Заканчиваются в этом месяце (%d):\n", len(digest.Expired))
+		for i, rule := range digest.Expired {
+			if i == digestSectionSize {
+				text += fmt.Sprintf("… и ещё %d\n", len(digest.Expired)-digestSectionSize)
+				break
+			}
+			text += fmt.Sprintf("• %s — %s, %s, 👤 %s\n",
+				rule.BankName, rule.Category, formatRewardPercent(&rule), rule.UserDisplayName)
+		}
+	}
+
+	if len(digest.Earned) > 0 {
+		text += "\n💰 Заработано по покупкам:\n"
+		for i, earned := range digest.Earned {
+			if i == digestSectionSize {
+				text += fmt.Sprintf("… и ещё %d\n", len(digest.Earned)-digestSectionSize)
+				break
+			}
+			text += fmt.Sprintf("• %s — %s (покупок: %d)\n",
+				earned.UserDisplayName, formatRubles(earned.Cashback), earned.Spends)
+		}
+	}
+
+	next := digest.NextMonth.Format("01.2006")
+	if len(digest.Best) == 0 {
+		text += fmt.Sprintf("\n🏆 На %s кэшбэков пока нет — добавьте новые через /add\n", next)
+	} else {
+		text += fmt.Sprintf("\n🏆 Лучшее на %s:\n", next)
+		for i, rule := range digest.Best {
+			if i == digestSectionSize {
+				text += fmt.Sprintf("… и ещё %d\n", len(digest.Best)-digestSectionSize)
+				break
+			}
+			text += fmt.Sprintf("• %s — %s %s до %.0f₽, 👤 %s%s\n",
+				rule.Category, rule.BankName, formatRewardPercent(&rule), rule.MaxAmount,
+				rule.UserDisplayName, formatCardLine(&rule, "   "))
+		}
+	}
+
+	if len(digest.Gaps) > 0 {
+		text += fmt.Sprintf("\n🕳 Без выгодного кэшбэка на %s:\n", next)
+		for i, gap := range digest.Gaps {
+			if i == digestSectionSize {
+				text += fmt.Sprintf("… и ещё %d\n", len(digest.Gaps)-digestSectionSize)
+				break
+			}
+			status := "нет кэшбэка"
+			if gap.Status == models.CoverageWeak {
+				status = "только слабый кэшбэк"
+			}
+			text += fmt.Sprintf("• %s — %s\n", gap.Category, status)
+		}
+		text += "\n💡 Какие категории выбрать в банках: /advice"
+	}
+
+	return strings.TrimRight(text, "\n")
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestParseDigestArgs(t *testing.T) {
+	current := &models.DigestSettings{Day: 0, Hour: 19, Minute: 0, Enabled: true}
+
+	tests := []struct {
+		args string
+		want models.DigestSettingsRequest
+	}{
+		{"28 20:30", models.DigestSettingsRequest{Day: 28, Time: "20:30", Enabled: true}},
+		{"последний 21:00", models.DigestSettingsRequest{Day: 0, Time: "21:00", Enabled: true}},
+		{"5", models.DigestSettingsRequest{Day: 5, Time: "19:00", Enabled: true}},
+		{"выкл", models.DigestSettingsRequest{Day: 0, Time: "19:00", Enabled: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			req, err := parseDigestArgs(tt.args, current)
+			if err != nil {
+				t.Fatalf("parseDigestArgs() error = %v", err)
+			}
+			if *req != tt.want {
+				t.Errorf("parseDigestArgs() = %+v, ожидалось %+v", *req, tt.want)
+			}
+		})
+	}
+
+	for _, args := range []string{"31 19:00", "завтра", "5 7 вечера"} {
+		if _, err := parseDigestArgs(args, current); err == nil {
+			t.Errorf("parseDigestArgs(%q) ожидалась ошибка", args)
+		}
+	}
+}
+
+func TestFormatGroupDigest(t *testing.T) {
+	digest := &models.GroupDigest{
+		GroupName: "Семья",
+		Month:     time.Date(2099, time.October, 1, 0, 0, 0, 0, time.UTC),
+		NextMonth: time.Date(2099, time.November, 1, 0, 0, 0, 0, time.UTC),
+		Expired: []models.CashbackRule{
+			{BankName: "Сбер", Category: "Такси", CashbackPercent: 5, EffectivePercent: 5, UserDisplayName: "Иван"},
+		},
+		Gaps:   []models.CoverageGap{{Category: "Аптеки", Status: models.CoverageMissing}},
+		Earned: []models.EarnedCashback{{UserID: "1", UserDisplayName: "Иван", Spends: 3, Cashback: 150}},
+	}
+
+	text := formatGroupDigest(digest)
+	for _, want := range []string{
+		"Итоги 10.2099 для группы <b>Семья</b>",
+		"• Сбер — Такси, 5.0%, 👤 Иван",
+		"• Иван — " + formatRubles(150) + " (покупок: 3)",
+		"На 11.2099 кэшбэков пока нет",
+		"• Аптеки — нет кэшбэка",
+		"/advice",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatGroupDigest() не содержит %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestDigestRecipients(t *testing.T) {
+	chats := digestRecipients(models.DigestDelivery{ChatIDs: []int64{-100}, Members: []string{"1"}})
+	if len(chats) != 1 || chats[0] != -100 {
+		t.Errorf("digestRecipients() с чатом = %v", chats)
+	}
+
+	chats = digestRecipients(models.DigestDelivery{Members: []string{"1", "web-user", "2"}})
+	if len(chats) != 2 || chats[0] != 1 || chats[1] != 2 {
+		t.Errorf("digestRecipients() без чата = %v", chats)
+	}
+}
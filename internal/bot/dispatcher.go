@@ -0,0 +1,92 @@
+package bot
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// dispatcher распределяет обновления по пулу воркеров.
+// Обновления одного пользователя всегда попадают в один и тот же воркер,
+// поэтому обрабатываются строго по порядку, а разные пользователи —
+// параллельно и не блокируют друг друга.
+type dispatcher struct {
+	handle func(tgbotapi.Update)
+	queues []chan tgbotapi.Update
+	wg     sync.WaitGroup
+}
+
+// newDispatcher создаёт диспетчер с указанным числом воркеров.
+func newDispatcher(workers, queueSize int, handle func(tgbotapi.Update)) *dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+
+	queues := make([]chan tgbotapi.Update, workers)
+	for i := range queues {
+		queues[i] = make(chan tgbotapi.Update, queueSize)
+	}
+
+	return &dispatcher{
+		handle: handle,
+		queues: queues,
+	}
+}
+
+// start запускает воркеры.
+func (d *dispatcher) start() {
+	for _, queue := range d.queues {
+		d.wg.Add(1)
+		go d.work(queue)
+	}
+}
+
+// dispatch ставит обновление в очередь воркера, отвечающего за пользователя.
+// Блокируется, если очередь воркера заполнена.
+func (d *dispatcher) dispatch(update tgbotapi.Update) {
+	key := updateShardKey(update)
+	if key < 0 {
+		key = -key
+	}
+	d.queues[key%int64(len(d.queues))] <- update
+}
+
+// stop закрывает очереди и ждёт, пока воркеры обработают уже принятые обновления.
+// После вызова stop нельзя вызывать dispatch.
+func (d *dispatcher) stop() {
+	for _, queue := range d.queues {
+		close(queue)
+	}
+	d.wg.Wait()
+}
+
+// work обрабатывает обновления из очереди до её закрытия.
+func (d *dispatcher) work(queue <-chan tgbotapi.Update) {
+	defer d.wg.Done()
+
+	for update := range queue {
+		d.safeHandle(update)
+	}
+}
+
+// safeHandle обрабатывает обновление, не давая панике в обработчике остановить воркер.
+func (d *dispatcher) safeHandle(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("❌ Паника при обработке обновления %d: %v\n%s", update.UpdateID, r, debug.Stack())
+		}
+	}()
+
+	d.handle(update)
+}
+
+// updateShardKey возвращает ключ шардирования обновления — ID пользователя.
+// Для обновлений без пользователя используется ID обновления.
+func updateShardKey(update tgbotapi.Update) int64 {
+	if user := update.SentFrom(); user != nil {
+		return user.ID
+	}
+	return int64(update.UpdateID)
+}
@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func messageUpdate(updateID int, userID int64) tgbotapi.Update {
+	return tgbotapi.Update{
+		UpdateID: updateID,
+		Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: userID},
+			Chat: &tgbotapi.Chat{ID: userID},
+		},
+	}
+}
+
+func TestDispatcherKeepsPerUserOrder(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[int64][]int)
+
+	d := newDispatcher(4, 10, func(update tgbotapi.Update) {
+		mu.Lock()
+		defer mu.Unlock()
+		userID := update.Message.From.ID
+		seen[userID] = append(seen[userID], update.UpdateID)
+	})
+	d.start()
+
+	const perUser = 50
+	users := []int64{1, 2, 3, 4, 5, 6, 7}
+	updateID := 0
+	for i := 0; i < perUser; i++ {
+		for _, userID := range users {
+			updateID++
+			d.dispatch(messageUpdate(updateID, userID))
+		}
+	}
+	d.stop()
+
+	for _, userID := range users {
+		ids := seen[userID]
+		if len(ids) != perUser {
+			t.Fatalf("User %d: expected %d updates, got %d", userID, perUser, len(ids))
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] <= ids[i-1] {
+				t.Fatalf("User %d: updates out of order: %v", userID, ids)
+			}
+		}
+	}
+}
+
+func TestDispatcherSurvivesPanic(t *testing.T) {
+	var mu sync.Mutex
+	handled := 0
+
+	d := newDispatcher(1, 10, func(update tgbotapi.Update) {
+		if update.UpdateID == 1 {
+			panic("boom")
+		}
+		mu.Lock()
+		handled++
+		mu.Unlock()
+	})
+	d.start()
+	d.dispatch(messageUpdate(1, 1))
+	d.dispatch(messageUpdate(2, 1))
+	d.stop()
+
+	if handled != 1 {
+		t.Errorf("Expected 1 handled update after panic, got %d", handled)
+	}
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// askDuplicateResolution предлагает заменить существующее правило,
+// сохранить оба или отменить добавление.
+// Полный дубликат не сохраняется — о нём достаточно сообщить.
+func (b *Bot) askDuplicateResolution(chatID, userID int64, data *ParsedData, duplicate *DuplicateError) {
+	existing := duplicate.Existing
+
+	if duplicate.Identical {
+		b.clearState(userID)
+		b.sendText(chatID, fmt.Sprintf("♻️ Такой кэшбэк уже сохранён (ID: %d)\n\n%s",
+			existing.ID, formatDuplicateShort(existing)))
+		return
+	}
+
+	text := fmt.Sprintf(
+		"⚠️ На этот месяц уже есть кэшбэк %s — %s:\n\n"+
+			"Сейчас: %.1f%%, до %.0f₽ (ID: %d)\n"+
+			"Новый: %.1f%%, до %.0f₽\n\n"+
+			"❓ Что сделать?",
+		existing.BankName, existing.Category,
+		existing.CashbackPercent, existing.MaxAmount, existing.ID,
+		data.CashbackPercent, data.MaxAmount,
+	)
+
+	b.setState(userID, StateAwaitingDuplicateChoice, data, nil, existing.ID)
+	b.sendWithInlineButtons(chatID, userID, text, duplicateButtons())
+}
+
+// askMultilineDuplicateResolution предлагает одно решение для всех строк
+// многострочного добавления, конфликтующих с сохранёнными правилами.
+func (b *Bot) askMultilineDuplicateResolution(message *tgbotapi.Message, lines []string) {
+	text := fmt.Sprintf("⚠️ Для %d строк на этот месяц уже есть кэшбэк:\n\n%s\n\n❓ Что сделать?",
+		len(lines), strings.Join(lines, "\n"))
+
+	b.saveState(message.From.ID, &UserState{State: StateAwaitingDuplicateChoice, Lines: lines})
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, duplicateButtons())
+}
+
+// handleDuplicateChoice обрабатывает текстовый ответ на вопрос о дубликате.
+func (b *Bot) handleDuplicateChoice(message *tgbotapi.Message, state *UserState) {
+	b.applyDuplicateChoice(message, state, parseDuplicateChoice(message.Text))
+}
+
+// applyDuplicateChoice применяет решение пользователя о дубликате.
+func (b *Bot) applyDuplicateChoice(message *tgbotapi.Message, state *UserState, choice answerChoice) {
+	userID := message.From.ID
+
+	switch choice {
+	case choiceReplace, choiceKeepBoth:
+		b.clearState(userID)
+		if len(state.Lines) > 0 {
+			b.resolveMultilineDuplicates(message, state.Lines, choice)
+			return
+		}
+		if choice == choiceKeepBoth {
+			b.saveCashback(message.Chat.ID, message.From, state.Data, true)
+			return
+		}
+		rule, err := b.replaceCashback(state.RuleID, state.Data)
+		if err != nil {
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка замены: %s", err))
+			return
+		}
+		b.sendSavedCashback(message.Chat.ID, userID, rule)
+
+	case choiceCancel, choiceNo:
+		b.clearState(userID)
+		b.sendText(message.Chat.ID, "🚫 Добавление отменено, существующий кэшбэк не изменён")
+
+	default:
+		b.sendText(message.Chat.ID, "❓ Ответьте \"заменить\", \"оставить оба\" или \"отмена\"")
+	}
+}
+
+// resolveMultilineDuplicates сохраняет конфликтующие строки с выбранным решением.
+func (b *Bot) resolveMultilineDuplicates(message *tgbotapi.Message, lines []string, choice answerChoice) {
+	var results []string
+
+	for i, line := range lines {
+		data, err := parseCashbackLine(line)
+		if err != nil {
+			results = append(results, fmt.Sprintf("❌ Строка %d: %s", i+1, err))
+			continue
+		}
+
+		rule, err := b.client.CreateCashback(b.newCreateRequest(message.From, data, choice == choiceKeepBoth))
+		var duplicate *DuplicateError
+		if errors.As(err, &duplicate) {
+			// Правило могло измениться с момента вопроса — заменяем актуальное
+			rule, err = b.replaceCashback(duplicate.Existing.ID, data)
+		}
+		if err != nil {
+			results = append(results, fmt.Sprintf("❌ Строка %d: %s", i+1, err))
+			continue
+		}
+		results = append(results, fmt.Sprintf("✅ Строка %d: %s - %s (ID: %d)", i+1, rule.BankName, rule.Category, rule.ID))
+	}
+
+	b.sendText(message.Chat.ID, "📊 Результаты:\n\n"+strings.Join(results, "\n"))
+}
+
+// replaceCashback заменяет параметры существующего правила целиком: лимит,
+// программа, условия и активация берутся из новой строки, даже если пусты.
+func (b *Bot) replaceCashback(ruleID int64, data *ParsedData) (*models.CashbackRule, error) {
+	log.Printf("🔄 Замена правила %d: Bank='%s', Category='%s'", ruleID, data.BankName, data.Category)
+
+	_, err := b.client.UpdateCashback(ruleID, &models.UpdateCashbackRequest{
+		Category:        data.Category,
+		BankName:        data.BankName,
+		MonthYear:       data.MonthYear,
+		CashbackPercent: data.CashbackPercent,
+		MaxAmount:       data.MaxAmount,
+		RewardProgram:   data.RewardProgram,
+		Conditions:      &data.Conditions,
+		Activation:      data.Activation,
+		Replace:         true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.client.GetCashbackByID(ruleID)
+}
+
+// parseDuplicateChoice распознаёт текстовый ответ на вопрос о дубликате.
+func parseDuplicateChoice(text string) answerChoice {
+	text = strings.ToLower(strings.TrimSpace(text))
+
+	switch {
+	case strings.Contains(text, "замен"):
+		return choiceReplace
+	case strings.Contains(text, "оба"), strings.Contains(text, "остав"):
+		return choiceKeepBoth
+	case isCancelAnswer(text), isNoAnswer(text):
+		return choiceCancel
+	default:
+		return choiceUnknown
+	}
+}
+
+// formatDuplicateShort кратко описывает существующее правило.
+func formatDuplicateShort(rule *models.CashbackRule) string {
+	return fmt.Sprintf("уже есть %s — %s %.1f%%, до %.0f₽ (ID: %d)",
+		rule.BankName, rule.Category, rule.CashbackPercent, rule.MaxAmount, rule.ID)
+}
+
+// duplicateButtons — кнопки выбора действия при дубликате.
+func duplicateButtons() [][]inlineButton {
+	return [][]inlineButton{
+		{
+			{Text: BtnReplace, Action: CallbackDuplicate, Payload: string(choiceReplace)},
+			{Text: BtnKeepBoth, Action: CallbackDuplicate, Payload: string(choiceKeepBoth)},
+		},
+		{{Text: BtnCancel, Action: CallbackDuplicate, Payload: string(choiceCancel)}},
+	}
+}
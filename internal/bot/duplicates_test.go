@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestCreateCashbackReturnsDuplicateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		json.NewEncoder(w).Encode(models.ConflictResponse{
+			Error:    "Правило уже существует",
+			Existing: &models.CashbackRule{ID: 7, BankName: "Тинькофф", Category: "Такси"},
+		})
+	}))
+	defer server.Close()
+
+	_, err := NewAPIClient(server.URL).CreateCashback(&models.CreateCashbackRequest{})
+
+	var duplicate *DuplicateError
+	if !errors.As(err, &duplicate) {
+		t.Fatalf("Expected DuplicateError, got %v", err)
+	}
+	if duplicate.Existing.ID != 7 || duplicate.Identical {
+		t.Errorf("Unexpected duplicate: %+v", duplicate)
+	}
+}
+
+func TestParseDuplicateChoice(t *testing.T) {
+	tests := map[string]answerChoice{
+		"Заменить":      choiceReplace,
+		"оставить оба":  choiceKeepBoth,
+		"отмена":        choiceCancel,
+		"нет":           choiceCancel,
+		"что-то другое": choiceUnknown,
+	}
+
+	for text, want := range tests {
+		if got := parseDuplicateChoice(text); got != want {
+			t.Errorf("parseDuplicateChoice(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
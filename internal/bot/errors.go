@@ -3,6 +3,8 @@ package bot
 import (
 	"errors"
 	"fmt"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
 )
 
 // Стандартные ошибки бота.
@@ -14,6 +16,15 @@ var (
 	ErrNotRuleOwner     = errors.New("вы не владелец этого правила")
 	ErrInvalidInput     = errors.New("некорректные входные данные")
 	ErrAPIUnavailable   = errors.New("API недоступен")
+	ErrCallbackInvalid  = errors.New("некорректные callback-данные")
+	ErrCallbackTooLong  = errors.New("callback-данные превышают лимит Telegram")
+	ErrChatNotBound     = errors.New("чат не привязан к группе")
+	ErrCardNotFound     = errors.New("карта не найдена")
+	ErrDigestAlreadySent = errors.New("сводка за месяц уже отправлена")
+	ErrPaymentAnswered  = errors.New("на просьбу оплатить уже ответили")
+	ErrBankCapNotFound  = errors.New("лимит банка не найден")
+	ErrReminderAlreadySent = errors.New("напоминание об активации уже отправлено")
+	ErrDebtChanged      = errors.New("долг изменился")
 )
 
 // APIError представляет ошибку от API.
@@ -39,6 +50,17 @@ func NewAPIError(statusCode int, message, details string) *APIError {
 	}
 }
 
+// DuplicateError возвращается API при попытке создать правило,
+// которое конфликтует с уже сохранённым.
+type DuplicateError struct {
+	Existing  *models.CashbackRule
+	Identical bool
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("правило уже существует (ID: %d)", e.Existing.ID)
+}
+
 // ParseError представляет ошибку парсинга.
 type ParseError struct {
 	Field   string
@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// exportFormats — форматы выгрузки /export с подписями кнопок.
+var exportFormats = []struct {
+	Format string
+	Label  string
+}{
+	{"xlsx", "📊 Excel"},
+	{"csv", "📄 CSV"},
+	{"json", "🧾 JSON"},
+	{"ics", "📅 Календарь"},
+}
+
+// handleExport обрабатывает команду /export [формат].
+// Без аргумента предлагает выбрать формат кнопками.
+func (b *Bot) handleExport(message *tgbotapi.Message) {
+	format := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if format == "" {
+		b.sendWithInlineButtons(message.Chat.ID, message.From.ID,
+			"📤 В каком формате выгрузить кэшбэки группы?", exportButtons())
+		return
+	}
+
+	if !isExportFormat(format) {
+		b.sendText(message.Chat.ID, "❌ Неизвестный формат. Доступны: csv, xlsx, json, ics\n\n"+
+			"Например: /export xlsx")
+		return
+	}
+
+	b.sendExport(message, format)
+}
+
+// sendExport выгружает кэшбэки группы и отправляет файл документом.
+func (b *Bot) sendExport(message *tgbotapi.Message, format string) {
+	groupName := b.resolveGroup(message)
+	if groupName == "" {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	data, err := b.client.ExportGroup(groupName, format)
+	if err != nil {
+		log.Printf("❌ Ошибка выгрузки группы %s: %v", groupName, err)
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка выгрузки: %s", err))
+		return
+	}
+
+	document := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("cashback-%s.%s", groupName, format),
+		Bytes: data,
+	})
+	document.Caption = fmt.Sprintf("📤 Кэшбэки группы \"%s\"", groupName)
+
+	if _, err := b.api.Send(document); err != nil {
+		log.Printf("❌ Ошибка отправки выгрузки: %v", err)
+		b.sendText(message.Chat.ID, "❌ Не удалось отправить файл")
+	}
+}
+
+// isExportFormat проверяет, что формат поддерживается выгрузкой.
+func isExportFormat(format string) bool {
+	for _, known := range exportFormats {
+		if known.Format == format {
+			return true
+		}
+	}
+	return false
+}
+
+// exportButtons — кнопки выбора формата выгрузки.
+func exportButtons() [][]inlineButton {
+	row := make([]inlineButton, 0, len(exportFormats))
+	for _, format := range exportFormats {
+		row = append(row, inlineButton{Text: format.Label, Action: CallbackExport, Payload: format.Format})
+	}
+	return [][]inlineButton{row[:2], row[2:]}
+}
@@ -0,0 +1,78 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// handleGaps обрабатывает команду /gaps [процент].
+func (b *Bot) handleGaps(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName, err := b.client.GetUserGroup(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	var minPercent float64
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		minPercent, err = strconv.ParseFloat(strings.ReplaceAll(strings.TrimSuffix(args, "%"), ",", "."), 64)
+		if err != nil || minPercent <= 0 || minPercent > 100 {
+			b.sendText(message.Chat.ID, "❌ Укажите порог в процентах. Например: /gaps 5")
+			return
+		}
+	}
+
+	now := time.Now()
+	monthYear := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
+
+	report, err := b.client.GetGroupCoverage(groupName, monthYear, minPercent)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось проверить покрытие категорий: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatCoverageReport(report))
+}
+
+// formatCoverageReport форматирует отчёт о пробелах в покрытии категорий.
+func formatCoverageReport(report *models.CoverageReport) string {
+	if len(report.Gaps) == 0 {
+		return fmt.Sprintf("✅ Все важные категории (%d) покрыты кэшбэком от %.1f%%", report.Checked, report.MinPercent)
+	}
+
+	text := fmt.Sprintf("🕳 Где группа недополучает кэшбэк (меньше %.1f%%) — %d из %d категорий:\n",
+		report.MinPercent, len(report.Gaps), report.Checked)
+
+	for _, gap := range report.Gaps {
+		if gap.Status == models.CoverageWeak {
+			text += fmt.Sprintf("\n⚠️ %s — только слабый кэшбэк", gap.Category)
+		} else {
+			text += fmt.Sprintf("\n❌ %s — нет кэшбэка", gap.Category)
+		}
+		if gap.HistoryMonths > 0 {
+			text += fmt.Sprintf(" (был %d мес.)", gap.HistoryMonths)
+		}
+
+		rule := gap.BestRule
+		if rule == nil {
+			text += "\n   Лучшего варианта нет\n"
+			continue
+		}
+		where := ""
+		if !strings.EqualFold(gap.BestCategory, gap.Category) {
+			where = fmt.Sprintf(" на \"%s\"", gap.BestCategory)
+		}
+		text += fmt.Sprintf("\n   Лучшее сейчас: %s — %s%s, 👤 %s\n",
+			rule.BankName, formatRewardPercent(rule), where, rule.UserDisplayName)
+	}
+
+	text += "\n💡 Посмотрите, какие категории выбрать в банках: /advice"
+	return text
+}
@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatCoverageReport(t *testing.T) {
+	report := &models.CoverageReport{
+		MinPercent: 3,
+		Checked:    10,
+		Gaps: []models.CoverageGap{
+			{Category: "Зоотовары", Status: models.CoverageMissing, HistoryMonths: 2, BestCategory: "Все покупки",
+				BestRule: &models.CashbackRule{BankName: "Альфа", CashbackPercent: 1.5, UserDisplayName: "Иван"}},
+			{Category: "Аптеки", Status: models.CoverageWeak, BestCategory: "Аптеки",
+				BestRule: &models.CashbackRule{BankName: "Сбер", CashbackPercent: 1, UserDisplayName: "Мария"}},
+			{Category: "Кино", Status: models.CoverageMissing},
+		},
+	}
+
+	text := formatCoverageReport(report)
+	for _, want := range []string{
+		"3 из 10", "❌ Зоотовары — нет кэшбэка (был 2 мес.)", "Альфа — 1.5% на \"Все покупки\"",
+		"⚠️ Аптеки — только слабый кэшбэк", "Сбер — 1.0%, 👤 Мария", "Лучшего варианта нет",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatCoverageReport() не содержит %q:\n%s", want, text)
+		}
+	}
+
+	if text := formatCoverageReport(&models.CoverageReport{MinPercent: 3, Checked: 4}); !strings.Contains(text, "✅") {
+		t.Errorf("formatCoverageReport() без пробелов = %q", text)
+	}
+}
@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// groupChatCommands — команды, доступные в групповом чате.
+// Остальные команды требуют диалога и работают только в личном чате с ботом.
+var groupChatCommands = map[string]bool{
+	"start":       true,
+	"help":        true,
+	"bindgroup":   true,
+	"unbindgroup": true,
+	"best":        true,
+	"list":        true,
+	"groupinfo":   true,
+}
+
+// isGroupChat проверяет, что сообщение пришло из группового чата.
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// isGroupChatID проверяет, что ID принадлежит групповому чату.
+// В Telegram ID групп и супергрупп отрицательные, ID личных чатов — положительные.
+func isGroupChatID(chatID int64) bool {
+	return chatID < 0
+}
+
+// handleGroupChatMessage обрабатывает сообщения из групповых чатов.
+// Бот реагирует только на команды: в режиме приватности Telegram
+// и так доставляет боту лишь команды и служебные сообщения, а при
+// отключённом режиме обычная переписка участников игнорируется.
+func (b *Bot) handleGroupChatMessage(message *tgbotapi.Message) {
+	if b.isBotAdded(message.NewChatMembers) {
+		b.handleBotAddedToChat(message)
+		return
+	}
+
+	if message.From == nil || message.From.IsBot || !message.IsCommand() {
+		return
+	}
+
+	// Команда адресована другому боту (/best@otherbot)
+	if !b.isCommandForMe(message) {
+		return
+	}
+
+	command := message.Command()
+	if !groupChatCommands[command] {
+		b.sendText(message.Chat.ID, fmt.Sprintf(
+			"ℹ️ Команда /%s доступна только в личном чате с ботом: @%s", command, b.api.Self.UserName))
+		return
+	}
+
+	switch command {
+	case "start", "help":
+		b.sendText(message.Chat.ID, b.formatGroupChatHelp())
+		return
+	case "bindgroup":
+		b.handleBindChat(message)
+		return
+	case "unbindgroup":
+		b.handleUnbindChat(message)
+		return
+	}
+
+	groupName, err := b.client.GetChatGroup(message.Chat.ID)
+	if err != nil {
+		if errors.Is(err, ErrChatNotBound) {
+			b.sendText(message.Chat.ID, "⚠️ Этот чат не привязан к группе кэшбэков.\n\n"+
+				"Участник группы может привязать его командой:\n/bindgroup название")
+			return
+		}
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка: %s", err))
+		return
+	}
+
+	b.ensureChatMember(message, groupName)
+
+	switch command {
+	case "best":
+		category := normalizeString(message.CommandArguments())
+		if category == "" {
+			b.sendText(message.Chat.ID, fmt.Sprintf(
+				"❌ Укажите категорию: /best@%s Такси", b.api.Self.UserName))
+			return
+		}
+		b.handleBestQueryWithCorrection(message, category, false)
+	case "list":
+		b.showListPage(message, 0, 0)
+	case "groupinfo":
+		b.showGroupInfo(message.Chat.ID, groupName)
+	}
+}
+
+// isCommandForMe проверяет, что команда без упоминания или с упоминанием этого бота.
+func (b *Bot) isCommandForMe(message *tgbotapi.Message) bool {
+	command := message.CommandWithAt()
+	at := strings.Index(command, "@")
+	if at < 0 {
+		return true
+	}
+	return strings.EqualFold(command[at+1:], b.api.Self.UserName)
+}
+
+// isBotAdded проверяет, что среди новых участников чата есть этот бот.
+func (b *Bot) isBotAdded(members []tgbotapi.User) bool {
+	for _, member := range members {
+		if member.ID == b.api.Self.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// handleBotAddedToChat приветствует чат после добавления бота.
+func (b *Bot) handleBotAddedToChat(message *tgbotapi.Message) {
+	log.Printf("👥 Бот добавлен в чат \"%s\" (ID: %d)", message.Chat.Title, message.Chat.ID)
+	b.sendText(message.Chat.ID, "👋 Привет! Я помогаю выбрать карту с лучшим кэшбэком.\n\n"+
+		"Чтобы начать, участник группы кэшбэков должен привязать этот чат:\n"+
+		"/bindgroup название\n\n"+
+		b.formatGroupChatHelp())
+}
+
+// handleBindChat привязывает групповой чат к группе кэшбэков.
+// Без аргументов используется группа вызвавшего пользователя.
+func (b *Bot) handleBindChat(message *tgbotapi.Message) {
+	if !b.isChatAdmin(message.Chat.ID, message.From.ID) {
+		b.sendText(message.Chat.ID, "⚠️ Привязать чат может только администратор чата")
+		return
+	}
+
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName := strings.TrimSpace(message.CommandArguments())
+	if groupName == "" {
+		groupName = b.getUserGroup(message.From.ID)
+		if groupName == "" {
+			b.sendText(message.Chat.ID, "❌ Укажите группу: /bindgroup название\n\n"+
+				"Вы должны состоять в этой группе.")
+			return
+		}
+	}
+
+	if err := b.client.BindChat(message.Chat.ID, groupName, userIDStr); err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ %s", err))
+		return
+	}
+
+	log.Printf("🔗 Чат %d привязан к группе \"%s\" пользователем @%s",
+		message.Chat.ID, groupName, message.From.UserName)
+	b.sendText(message.Chat.ID, fmt.Sprintf(
+		"✅ Чат привязан к группе \"%s\"!\n\n"+
+			"Участники чата, которые ещё не состоят в группе, будут добавлены в неё при первом обращении к боту.\n\n"+
+			"Попробуйте: /best@%s Такси", groupName, b.api.Self.UserName))
+}
+
+// handleUnbindChat отвязывает групповой чат от группы кэшбэков.
+func (b *Bot) handleUnbindChat(message *tgbotapi.Message) {
+	if !b.isChatAdmin(message.Chat.ID, message.From.ID) {
+		b.sendText(message.Chat.ID, "⚠️ Отвязать чат может только администратор чата")
+		return
+	}
+
+	if err := b.client.UnbindChat(message.Chat.ID); err != nil {
+		if errors.Is(err, ErrChatNotBound) {
+			b.sendText(message.Chat.ID, "ℹ️ Чат не привязан к группе")
+			return
+		}
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка: %s", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, "✅ Чат отвязан от группы")
+}
+
+// isChatAdmin проверяет, является ли пользователь администратором чата.
+func (b *Bot) isChatAdmin(chatID, userID int64) bool {
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil {
+		log.Printf("❌ Ошибка проверки прав пользователя %d в чате %d: %v", userID, chatID, err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// ensureChatMember автоматически добавляет участника чата в привязанную группу,
+// если он ещё не состоит ни в одной группе. Пользователи других групп не переводятся.
+func (b *Bot) ensureChatMember(message *tgbotapi.Message, groupName string) {
+	if b.getUserGroup(message.From.ID) != "" {
+		return
+	}
+
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	if err := b.client.JoinGroup(userIDStr, groupName); err != nil {
+		log.Printf("❌ Ошибка автодобавления @%s в группу \"%s\": %v", message.From.UserName, groupName, err)
+		return
+	}
+
+	log.Printf("👥 @%s автоматически добавлен в группу \"%s\" из чата %d",
+		message.From.UserName, groupName, message.Chat.ID)
+	b.sendText(message.Chat.ID, fmt.Sprintf("👋 %s добавлен(а) в группу \"%s\"",
+		getUserDisplayName(message.From), groupName))
+}
+
+// resolveGroup определяет группу для запроса: в групповом чате — привязанную
+// к чату группу, в личном — группу пользователя. Пустая строка, если группы нет.
+func (b *Bot) resolveGroup(message *tgbotapi.Message) string {
+	if isGroupChat(message.Chat) {
+		groupName, err := b.client.GetChatGroup(message.Chat.ID)
+		if err != nil {
+			return ""
+		}
+		return groupName
+	}
+	return b.getUserGroup(message.From.ID)
+}
+
+// formatGroupChatHelp форматирует справку для группового чата.
+func (b *Bot) formatGroupChatHelp() string {
+	name := b.api.Self.UserName
+	return fmt.Sprintf("📖 Команды в групповом чате:\n\n"+
+		"/best@%s категория — лучший кэшбэк группы\n"+
+		"/list@%s — список кэшбэков\n"+
+		"/groupinfo@%s — статистика группы\n"+
+		"/bindgroup@%s название — привязать чат к группе (админ)\n"+
+		"/unbindgroup@%s — отвязать чат (админ)\n\n"+
+		"Добавлять и изменять кэшбэки можно в личном чате с ботом: @%s",
+		name, name, name, name, name, name)
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestIsCommandForMe(t *testing.T) {
+	_, telegram := newFakeTelegram(t)
+	defer telegram.Close()
+
+	b := newTestBot(t, telegram.URL)
+
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"/best Такси", true},
+		{"/best@cashback_test_bot Такси", true},
+		{"/best@Cashback_Test_Bot", true},
+		{"/best@other_bot Такси", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			commandLen := len(tt.text)
+			for i, r := range tt.text {
+				if r == ' ' {
+					commandLen = i
+					break
+				}
+			}
+			message := &tgbotapi.Message{
+				Text:     tt.text,
+				Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: commandLen}},
+			}
+			if got := b.isCommandForMe(message); got != tt.want {
+				t.Errorf("isCommandForMe(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendHelpersSkipKeyboardInGroupChat(t *testing.T) {
+	fake, telegram := newFakeTelegram(t)
+	defer telegram.Close()
+
+	b := newTestBot(t, telegram.URL)
+
+	const groupChatID, privateChatID = -100123, 123
+	b.sendText(groupChatID, "текст")
+	b.sendTextPlain(groupChatID, "таблица")
+	b.sendTextWithPage(groupChatID, "страница", 1)
+	b.sendWithInlineButtons(groupChatID, privateChatID, "без кнопок", nil)
+	b.sendTextPlain(privateChatID, "таблица")
+
+	sent := fake.calls("sendMessage")
+	if len(sent) != 5 {
+		t.Fatalf("sendMessage вызван %d раз, ожидалось 5", len(sent))
+	}
+	for i, form := range sent[:4] {
+		if markup := form.Get("reply_markup"); markup != "" {
+			t.Errorf("Сообщение %d в группу с клавиатурой: %s", i+1, markup)
+		}
+	}
+	if sent[4].Get("reply_markup") == "" {
+		t.Error("Сообщение в личный чат без клавиатуры")
+	}
+}
@@ -148,22 +148,27 @@ func (b *Bot) handleGroupInfo(message *tgbotapi.Message) {
 		}
 	}
 
+	b.showGroupInfo(message.Chat.ID, groupName)
+}
+
+// showGroupInfo отправляет статистику группы в чат.
+func (b *Bot) showGroupInfo(chatID int64, groupName string) {
 	// Получаем информацию о пользователях
 	users, err := b.client.GetGroupUsers(groupName)
 	if err != nil {
-		b.sendText(message.Chat.ID, "❌ Ошибка получения участников")
+		b.sendText(chatID, "❌ Ошибка получения участников")
 		return
 	}
 
 	// Получаем все кешбеки группы для подсчета активности
 	list, err := b.client.ListCashback(groupName, 1000, 0)
 	if err != nil {
-		b.sendText(message.Chat.ID, "❌ Ошибка получения данных")
+		b.sendText(chatID, "❌ Ошибка получения данных")
 		return
 	}
 
 	text := b.formatGroupInfo(groupName, users, list.Rules)
-	b.sendText(message.Chat.ID, text)
+	b.sendText(chatID, text)
 }
 
 // formatGroupInfo форматирует информацию о группе.
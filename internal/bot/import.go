@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// spreadsheetMimeTypes — MIME типы таблиц, которые Telegram указывает для документов.
+var spreadsheetMimeTypes = map[string]bool{
+	"text/csv":                    true,
+	"text/comma-separated-values": true,
+	"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": true,
+}
+
+// importRowIcons — значки статусов строк в предпросмотре импорта.
+var importRowIcons = map[string]string{
+	models.ImportRowValid:     "✅",
+	models.ImportRowCreated:   "✅",
+	models.ImportRowDuplicate: "♻️",
+	models.ImportRowError:     "❌",
+}
+
+// isSpreadsheetDocument проверяет, что сообщение содержит CSV или XLSX файл.
+func isSpreadsheetDocument(message *tgbotapi.Message) bool {
+	if message.Document == nil {
+		return false
+	}
+	switch strings.ToLower(path.Ext(message.Document.FileName)) {
+	case ".csv", ".xlsx":
+		return true
+	}
+	return spreadsheetMimeTypes[message.Document.MimeType]
+}
+
+// handleImportDocument проверяет присланную таблицу и показывает предпросмотр импорта.
+// Файл не сохраняется в состоянии — при подтверждении он скачивается повторно по FileID.
+func (b *Bot) handleImportDocument(message *tgbotapi.Message) {
+	document := message.Document
+
+	if int64(document.FileSize) > ImportMaxFileSize {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Файл слишком большой. Максимум %d МБ", ImportMaxFileSize>>20))
+		return
+	}
+
+	report, err := b.importFile(message.From, document.FileID, document.FileName, true)
+	if err != nil {
+		log.Printf("❌ Ошибка проверки файла импорта от @%s: %v", message.From.UserName, err)
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось прочитать файл: %s\n\n%s",
+			html.EscapeString(err.Error()), formatImportHelp()))
+		return
+	}
+
+	if report.ValidRows == 0 {
+		b.sendText(message.Chat.ID, formatImportPreview(report)+"\n\n❌ Нет строк для импорта")
+		return
+	}
+
+	b.saveState(message.From.ID, &UserState{
+		State:    StateAwaitingImportConfirm,
+		FileID:   document.FileID,
+		FileName: document.FileName,
+	})
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID,
+		formatImportPreview(report)+"\n\n❓ Импортировать?", importButtons())
+}
+
+// handleImportConfirmation обрабатывает текстовый ответ на предпросмотр импорта.
+func (b *Bot) handleImportConfirmation(message *tgbotapi.Message, state *UserState) {
+	b.applyImportConfirmation(message, state, parseAnswerChoice(message.Text))
+}
+
+// applyImportConfirmation импортирует файл или отменяет импорт.
+func (b *Bot) applyImportConfirmation(message *tgbotapi.Message, state *UserState, choice answerChoice) {
+	userID := message.From.ID
+
+	switch choice {
+	case choiceYes:
+		b.clearState(userID)
+
+		report, err := b.importFile(message.From, state.FileID, state.FileName, false)
+		if err != nil {
+			log.Printf("❌ Ошибка импорта от @%s: %v", message.From.UserName, err)
+			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка импорта: %s", html.EscapeString(err.Error())))
+			return
+		}
+
+		log.Printf("📥 Импорт от @%s: создано правил: %d", message.From.UserName, report.Created)
+		b.sendText(message.Chat.ID, formatImportResult(report))
+
+	case choiceNo, choiceCancel:
+		b.clearState(userID)
+		b.sendText(message.Chat.ID, "🚫 Импорт отменён")
+
+	default:
+		b.sendText(message.Chat.ID, "❓ Ответьте \"да\", чтобы импортировать, или \"отмена\"")
+	}
+}
+
+// importFile скачивает файл из Telegram и отправляет его в API импорта.
+func (b *Bot) importFile(user *tgbotapi.User, fileID, fileName string, dryRun bool) (*models.ImportReport, error) {
+	data, err := b.downloadFile(fileID, ImportMaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.client.ImportCashback(&models.ImportRequest{
+		GroupName:       b.getUserGroup(user.ID),
+		UserID:          strconv.FormatInt(user.ID, 10),
+		UserDisplayName: getUserDisplayName(user),
+		FileName:        fileName,
+		Data:            data,
+		DryRun:          dryRun,
+	})
+}
+
+// formatImportPreview форматирует результат проверки файла.
+func formatImportPreview(report *models.ImportReport) string {
+	var sb strings.Builder
+	sb.WriteString("📄 <b>Предпросмотр импорта</b>\n\n")
+
+	sb.WriteString("Колонки:\n")
+	for _, field := range []struct{ key, label string }{
+		{"bank_name", "Банк"},
+		{"category", "Категория"},
+		{"cashback_percent", "Процент"},
+		{"max_amount", "Лимит"},
+		{"month_year", "Дата"},
+	} {
+		column, ok := report.Mapping[field.key]
+		if !ok {
+			column = "—"
+		}
+		sb.WriteString(fmt.Sprintf("  %s ← %s\n", field.label, html.EscapeString(column)))
+	}
+
+	sb.WriteString(fmt.Sprintf("\nСтрок: %d, к импорту: %d", report.TotalRows, report.ValidRows))
+	if report.DuplicateRows > 0 {
+		sb.WriteString(fmt.Sprintf(", дубликатов: %d", report.DuplicateRows))
+	}
+	if report.ErrorRows > 0 {
+		sb.WriteString(fmt.Sprintf(", с ошибками: %d", report.ErrorRows))
+	}
+	sb.WriteString("\n\n")
+
+	for i, row := range report.Rows {
+		if i == ImportPreviewRows {
+			sb.WriteString(fmt.Sprintf("… и ещё %d\n", len(report.Rows)-ImportPreviewRows))
+			break
+		}
+		sb.WriteString(formatImportRow(row))
+	}
+
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// formatImportRow форматирует строку отчёта импорта.
+func formatImportRow(row models.ImportRow) string {
+	line := fmt.Sprintf("%s %d.", importRowIcons[row.Status], row.Row)
+	if row.Rule != nil && row.Rule.BankName != "" {
+		line += fmt.Sprintf(" %s, %s, %.1f%%, %.0f ₽",
+			html.EscapeString(row.Rule.BankName), html.EscapeString(row.Rule.Category),
+			row.Rule.CashbackPercent, row.Rule.MaxAmount)
+	}
+	if len(row.Errors) > 0 {
+		line += " — " + html.EscapeString(strings.Join(row.Errors, "; "))
+	}
+	return line + "\n"
+}
+
+// formatImportResult форматирует итог импорта.
+func formatImportResult(report *models.ImportReport) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("✅ Импортировано правил: %d", report.Created))
+	if report.DuplicateRows > 0 {
+		sb.WriteString(fmt.Sprintf("\n♻️ Пропущено дубликатов: %d", report.DuplicateRows))
+	}
+	if report.ErrorRows > 0 {
+		sb.WriteString(fmt.Sprintf("\n❌ Пропущено строк с ошибками: %d", report.ErrorRows))
+	}
+	return sb.String()
+}
+
+// formatImportHelp описывает ожидаемый формат таблицы.
+func formatImportHelp() string {
+	return "📄 Ожидается CSV или XLSX с заголовком, например:\n" +
+		"<code>Банк;Категория;Процент;Лимит;Дата\n" +
+		"Тинькофф;Такси;5;3000;31.12.2024</code>\n\n" +
+		"Колонки \"Лимит\" и \"Дата\" необязательны."
+}
+
+// importButtons — кнопки подтверждения импорта.
+func importButtons() [][]inlineButton {
+	return [][]inlineButton{
+		{
+			{Text: BtnImport, Action: CallbackImportConfirm, Payload: string(choiceYes)},
+			{Text: BtnCancel, Action: CallbackImportConfirm, Payload: string(choiceCancel)},
+		},
+	}
+}
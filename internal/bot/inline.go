@@ -0,0 +1,117 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// handleInlineQuery обрабатывает inline-запросы вида "@cashbackbot аптеки".
+// В ответ отправляются статьи с лучшими кэшбэками группы пользователя,
+// которые можно отправить в любой чат.
+func (b *Bot) handleInlineQuery(query *tgbotapi.InlineQuery) {
+	category := normalizeString(query.Query)
+	log.Printf("🔎 Inline-запрос от @%s: \"%s\"", query.From.UserName, category)
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		CacheTime:     InlineCacheTime,
+		IsPersonal:    true,
+		Results:       []interface{}{},
+	}
+
+	groupName := b.getUserGroup(query.From.ID)
+	if groupName == "" {
+		answer.SwitchPMText = "Вы не состоите в группе — открыть бота"
+		answer.SwitchPMParameter = "inline"
+		b.answerInline(answer)
+		return
+	}
+
+	if category == "" {
+		answer.SwitchPMText = "Введите категорию, например: Аптеки"
+		answer.SwitchPMParameter = "inline"
+		b.answerInline(answer)
+		return
+	}
+
+	rules, match := b.findInlineRules(groupName, category)
+	if len(rules) == 0 {
+		now := time.Now()
+		monthYear := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
+		article := tgbotapi.NewInlineQueryResultArticle(
+			"notfound",
+			fmt.Sprintf("❌ Кэшбэк для \"%s\" не найден", category),
+			formatNotFoundMessage(category, monthYear),
+		)
+		article.Description = fmt.Sprintf("Группа \"%s\"", groupName)
+		answer.Results = append(answer.Results, article)
+		b.answerInline(answer)
+		return
+	}
+
+	answer.Results = buildInlineResults(rules, match)
+	b.answerInline(answer)
+}
+
+// findInlineRules ищет активные кэшбэки по категории, а если их нет —
+// на родительских категориях вплоть до "Все покупки".
+func (b *Bot) findInlineRules(groupName, category string) ([]models.CashbackRule, models.CategoryMatch) {
+	now := time.Now()
+	monthYear := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
+
+	rules, err := b.getAllCashbacksByCategory(groupName, category, monthYear)
+	if err == nil && len(rules) > 0 {
+		return rules, requestedLevel(category)
+	}
+
+	return b.findParentCashbacks(groupName, category, monthYear)
+}
+
+// buildInlineResults формирует статьи для ответа на inline-запрос.
+// Первая статья содержит сводку по всем вариантам, остальные — по одному правилу.
+func buildInlineResults(rules []models.CashbackRule, match models.CategoryMatch) []interface{} {
+	results := make([]interface{}, 0, InlineResultsLimit+1)
+
+	summary := tgbotapi.NewInlineQueryResultArticle(
+		"all",
+		fmt.Sprintf("🏆 Все варианты для \"%s\" (%d)", match.RequestedCategory, len(rules)),
+		formatAllCashbackResults(rules, match),
+	)
+	summary.Description = formatInlineDescription(&rules[0])
+	results = append(results, summary)
+
+	for i, rule := range rules {
+		if i >= InlineResultsLimit {
+			break
+		}
+
+		rule := rule
+		article := tgbotapi.NewInlineQueryResultArticle(
+			"rule:"+strconv.FormatInt(rule.ID, 10),
+			fmt.Sprintf("%s %s — %s", EmojiBank, rule.BankName, formatRewardPercent(&rule)),
+			formatBestCashback(&rule, match),
+		)
+		article.Description = fmt.Sprintf("📁 %s · 👤 %s · до %.0f₽",
+			rule.Category, rule.UserDisplayName, rule.MaxAmount)
+		results = append(results, article)
+	}
+
+	return results
+}
+
+// formatInlineDescription форматирует краткое описание лучшего правила.
+func formatInlineDescription(rule *models.CashbackRule) string {
+	return fmt.Sprintf("Лучший: %s %s (%s)", rule.BankName, formatRewardPercent(rule), rule.UserDisplayName)
+}
+
+// answerInline отправляет ответ на inline-запрос.
+func (b *Bot) answerInline(answer tgbotapi.InlineConfig) {
+	if _, err := b.api.Request(answer); err != nil {
+		log.Printf("❌ Ошибка ответа на inline-запрос: %v", err)
+	}
+}
@@ -12,8 +12,15 @@ type APIClientInterface interface {
 	UpdateCashback(id int64, req *models.UpdateCashbackRequest) (*models.CashbackRule, error)
 	DeleteCashback(id int64) error
 	ListCashback(groupName string, limit, offset int) (*models.ListCashbackResponse, error)
-	GetBestCashback(groupName, category, monthYear string) (*models.CashbackRule, error)
+	GetBestCashback(groupName, category, monthYear, userID string) (*models.BestCashbackResponse, error)
+	GetMerchantBestCashback(groupName, merchant, monthYear string, weekday int) (*models.MerchantBestResponse, error)
 	ListAllCategories(groupName, monthYear string) ([]string, error)
+	GetCategoryPath(category string) ([]string, error)
+	GetGroupStats(groupName, from, to string) (*models.GroupStats, error)
+	GetGroupHistory(req *models.HistoryRequest) ([]models.CashbackRule, error)
+	GetBankTrend(req *models.HistoryRequest) (*models.BankTrend, error)
+	GetGroupCoverage(groupName, monthYear string, minPercent float64) (*models.CoverageReport, error)
+	AdviseCategories(groupName string, req *models.CategoryAdviceRequest) (*models.CategoryAdviceResponse, error)
 
 	// Группы
 	GetUserGroup(userID string) (string, error)
@@ -22,6 +29,35 @@ type APIClientInterface interface {
 	GroupExists(groupName string) bool
 	GetAllGroups() ([]string, error)
 	GetGroupMembers(groupName string) ([]string, error)
+
+	// Ежемесячные сводки
+	GetDigestSettings(groupName string) (*models.DigestSettings, error)
+	SetDigestSettings(groupName string, req *models.DigestSettingsRequest) (*models.DigestSettings, error)
+	GetGroupDigest(groupName, monthYear string) (*models.GroupDigest, error)
+	ClaimGroupDigest(groupName, monthYear string) (*models.GroupDigest, error)
+	ListDueDigests() ([]models.DigestDelivery, error)
+
+	// Просьбы оплатить и долги
+	CreatePaymentRequest(groupName string, req *models.CreatePaymentRequest) (*models.PaymentRequest, error)
+	AnswerPaymentRequest(id int64, userID string, accept bool) (*models.PaymentRequest, error)
+	GetGroupBalances(groupName string) (*models.GroupBalances, error)
+	SettleDebt(groupName, userID string, debt *models.Debt) (*models.SettleDebtResponse, error)
+
+	// Лимиты банков и журнал трат
+	ListBankCaps(userID string) ([]models.BankCapUsage, error)
+	SetBankCap(userID, bankName string, req *models.BankCapRequest) (*models.BankCap, error)
+	DeleteBankCap(userID, bankName string) error
+	RecordSpend(req *models.CreateSpendRequest) (*models.SpendResponse, error)
+
+	// Активация категорий в приложении банка
+	ActivateCashback(id int64, userID string) (*models.CashbackRule, error)
+	ListDueActivations() ([]models.CashbackRule, error)
+	ClaimActivationReminder(id int64) (*models.CashbackRule, error)
+
+	// Групповые чаты
+	GetChatGroup(chatID int64) (string, error)
+	BindChat(chatID int64, groupName, userID string) error
+	UnbindChat(chatID int64) error
 }
 
 // Проверка, что APIClient реализует интерфейс.
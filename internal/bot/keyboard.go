@@ -6,35 +6,22 @@ import (
 
 // Константы для кнопок.
 const (
-	BtnYesCorrect    = "✅ Да, исправить"
-	BtnNoKeepAsIs    = "❌ Нет, оставить как есть"
-	BtnManualEdit    = "✏️ Изменить вручную"
-	BtnCancel        = "🚫 Отмена"
-	BtnYesDelete     = "✅ Да, удалить"
-	BtnCancelShort   = "❌ Отмена"
-	BtnNavPrev       = "◀️"
-	BtnNavNext       = "▶️"
-)
-
-// Предустановленные наборы кнопок.
-var (
-	// ButtonsConfirm — кнопки подтверждения исправления с ручным вводом.
-	ButtonsConfirm = [][]string{
-		{BtnYesCorrect, BtnNoKeepAsIs},
-		{BtnManualEdit},
-		{BtnCancel},
-	}
-
-	// ButtonsConfirmSimple — простые кнопки да/нет с ручным вводом.
-	ButtonsConfirmSimple = [][]string{
-		{BtnYesCorrect, BtnNoKeepAsIs},
-		{BtnManualEdit},
-	}
-
-	// ButtonsDelete — кнопки подтверждения удаления.
-	ButtonsDelete = [][]string{
-		{BtnYesDelete, BtnCancelShort},
-	}
+	BtnYesCorrect     = "✅ Да, исправить"
+	BtnNoKeepAsIs     = "❌ Нет, оставить как есть"
+	BtnManualEdit     = "✏️ Изменить вручную"
+	BtnCancel         = "🚫 Отмена"
+	BtnYesDelete      = "✅ Да, удалить"
+	BtnCancelShort    = "❌ Отмена"
+	BtnSaveAll        = "✅ Сохранить все"
+	BtnImport         = "📥 Импортировать"
+	BtnReplace        = "🔄 Заменить"
+	BtnKeepBoth       = "➕ Оставить оба"
+	BtnNavPrev        = "◀️"
+	BtnNavNext        = "▶️"
+	BtnAskToPay       = "💸 Попросить оплатить"
+	BtnAcceptPayment  = "✅ Оплачу"
+	BtnDeclinePayment = "❌ Не могу"
+	BtnActivated      = "✅ Активировано"
 )
 
 // Все доступные команды для пагинации.
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// tryBestByMerchant ищет лучший кэшбэк по названию магазина.
+// Возвращает false, если магазина нет в справочнике, — тогда запрос
+// обрабатывается как категория.
+func (b *Bot) tryBestByMerchant(message *tgbotapi.Message, query, groupName, monthYear string) bool {
+	resp, err := b.client.GetMerchantBestCashback(groupName, query, monthYear, isoWeekday(time.Now()))
+	if err != nil {
+		log.Printf("🏪 '%s' не найден среди магазинов: %v", query, err)
+		return false
+	}
+
+	if resp.Rule == nil {
+		log.Printf("🏪 Для магазина '%s' кэшбэк не найден ни на одном уровне", resp.Merchant.Name)
+		b.sendText(message.Chat.ID, formatNotFoundMessage(resp.Merchant.Category, monthYear))
+		return true
+	}
+
+	log.Printf("🏪 Магазин '%s': найден кэшбэк %s (уровень %s)", resp.Merchant.Name, resp.Rule.BankName, resp.MatchedBy)
+	b.sendText(message.Chat.ID, formatMerchantBestCashback(resp))
+	return true
+}
+
+// formatMerchantBestCashback форматирует лучший кэшбэк в магазине
+// с пояснением, на каком уровне он найден.
+func formatMerchantBestCashback(resp *models.MerchantBestResponse) string {
+	rule := resp.Rule
+	merchant := resp.Merchant
+
+	var header string
+	switch resp.MatchedBy {
+	case models.MatchMerchant:
+		header = fmt.Sprintf("🏪 Спецпредложение в магазине \"%s\":\n\n", merchant.Name)
+	case models.MatchCategory:
+		header = fmt.Sprintf("🏪 Магазин \"%s\" — категория \"%s\".\n"+
+			"🏆 Лучший кэшбэк:\n\n", merchant.Name, rule.Category)
+	default:
+		header = fmt.Sprintf("💡 Для магазина \"%s\" (категория \"%s\") кэшбэк не найден.\n"+
+			"Показываю кэшбэк на \"Все покупки\":\n\n", merchant.Name, merchant.Category)
+	}
+
+	return header + fmt.Sprintf(
+		"🏦 Банк: %s\n"+
+			"📁 Категория: %s\n"+
+			"📅 Действует до: %s\n"+
+			"💰 Кэшбэк: %s\n"+
+			"💵 Макс. сумма: %.0f₽\n"+
+			"👤 Карта: %s",
+		rule.BankName,
+		rule.Category,
+		rule.MonthYear.Format("02.01.2006"),
+		formatRewardPercent(rule),
+		rule.MaxAmount,
+		rule.UserDisplayName,
+	) + formatCardLine(rule, "") + formatConditionsLine(rule.Conditions, "") + formatBankCapLine(rule.BankCap, "")
+}
+
+// formatMerchantLine возвращает строку с магазином правила
+// или пустую строку для обычного правила на категорию.
+func formatMerchantLine(rule *models.CashbackRule, indent string) string {
+	if rule.Merchant == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n%s🏪 Магазин: %s", indent, rule.Merchant)
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatMerchantBestCashbackHeaders(t *testing.T) {
+	merchant := models.Merchant{Name: "Пятёрочка", Category: "Супермаркеты"}
+	rule := &models.CashbackRule{
+		BankName: "Сбер", Category: "Супермаркеты", Merchant: "Пятёрочка",
+		CashbackPercent: 7, MaxAmount: 1000, MonthYear: time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		matchedBy string
+		want      string
+	}{
+		{models.MatchMerchant, "Спецпредложение в магазине \"Пятёрочка\""},
+		{models.MatchCategory, "категория \"Супермаркеты\""},
+		{models.MatchAllPurchases, "Показываю кэшбэк на \"Все покупки\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.matchedBy, func(t *testing.T) {
+			text := formatMerchantBestCashback(&models.MerchantBestResponse{
+				Merchant: merchant, Rule: rule, MatchedBy: tt.matchedBy,
+			})
+			if !strings.Contains(text, tt.want) || !strings.Contains(text, "🏦 Банк: Сбер") {
+				t.Errorf("formatMerchantBestCashback() = %q, ожидалось %q", text, tt.want)
+			}
+		})
+	}
+
+	exhausted := *rule
+	exhausted.BankCap = &models.BankCapUsage{BankCap: models.BankCap{Amount: 500}, Earned: 500}
+	text := formatMerchantBestCashback(&models.MerchantBestResponse{Merchant: merchant, Rule: &exhausted, MatchedBy: models.MatchMerchant})
+	if !strings.Contains(text, "Общий лимит банка исчерпан") {
+		t.Errorf("formatMerchantBestCashback() с исчерпанным лимитом = %q", text)
+	}
+}
+
+func TestFormatSavedCashbackShowsMerchant(t *testing.T) {
+	rule := &models.CashbackRule{BankName: "Сбер", Category: "Супермаркеты", Merchant: "Пятёрочка", CashbackPercent: 7}
+	if text := formatSavedCashback(rule); !strings.Contains(text, "🏪 Магазин: Пятёрочка") {
+		t.Errorf("formatSavedCashback() не содержит магазин: %q", text)
+	}
+
+	rule.Merchant = ""
+	if text := formatSavedCashback(rule); strings.Contains(text, "Магазин") {
+		t.Errorf("formatSavedCashback() показывает магазин для правила на категорию: %q", text)
+	}
+}
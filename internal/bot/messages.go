@@ -11,18 +11,15 @@ import (
 
 // sendText отправляет текстовое сообщение с клавиатурой по умолчанию.
 func (b *Bot) sendText(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+
 	// Получаем текущую страницу пользователя
 	page := 0
-	if state, exists := b.userStates[chatID]; exists {
+	if state, exists := b.getState(chatID); exists {
 		page = state.KeyboardPage
 	}
-	
-	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = "HTML"
-
-	kb := tgbotapi.NewReplyKeyboard(buildKeyboardWithPage(nil, page)...)
-	kb.ResizeKeyboard = true
-	msg.ReplyMarkup = kb
+	setMainKeyboard(&msg, page)
 
 	if _, err := b.api.Send(msg); err != nil {
 		log.Printf("❌ Ошибка отправки сообщения: %v", err)
@@ -34,32 +31,28 @@ func (b *Bot) sendTextPlain(chatID int64, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
 	// НЕ используем ParseMode для совместимости с таблицами
 
-	kb := tgbotapi.NewReplyKeyboard(buildKeyboard(nil)...)
-	kb.ResizeKeyboard = true
-	msg.ReplyMarkup = kb
+	setMainKeyboard(&msg, 0)
 
 	if _, err := b.api.Send(msg); err != nil {
 		log.Printf("❌ Ошибка отправки сообщения: %v", err)
 	}
 }
 
-// sendWithButtons отправляет сообщение с указанными кнопками.
-func (b *Bot) sendWithButtons(chatID int64, text string, buttons [][]string) {
-	msg := tgbotapi.NewMessage(chatID, text)
+// setMainKeyboard прикрепляет к сообщению клавиатуру команд на странице page.
+// В групповых чатах клавиатура мешала бы всем участникам, поэтому там её нет.
+func setMainKeyboard(msg *tgbotapi.MessageConfig, page int) {
+	if isGroupChatID(msg.ChatID) {
+		return
+	}
 
-	keyboard := buildKeyboard(buttons)
-	kb := tgbotapi.NewReplyKeyboard(keyboard...)
+	kb := tgbotapi.NewReplyKeyboard(buildKeyboardWithPage(nil, page)...)
 	kb.ResizeKeyboard = true
 	msg.ReplyMarkup = kb
-
-	if _, err := b.api.Send(msg); err != nil {
-		log.Printf("❌ Ошибка отправки сообщения: %v", err)
-	}
 }
 
 // FormatParsedData форматирует распознанные данные для отображения.
 func FormatParsedData(data *ParsedData) string {
-	return fmt.Sprintf(
+	text := fmt.Sprintf(
 		"📋 Распознанные данные:\n\n"+
 			"🏦 Банк: %s\n"+
 			"📁 Категория: %s\n"+
@@ -72,6 +65,22 @@ func FormatParsedData(data *ParsedData) string {
 		data.CashbackPercent,
 		data.MaxAmount,
 	)
+
+	if data.RewardProgram != "" {
+		text += fmt.Sprintf("\n🎁 Программа: %s", rewardProgramLabel(data.RewardProgram))
+	}
+	if data.CardLast4 != "" {
+		text += fmt.Sprintf("\n💳 Карта: ***%s", data.CardLast4)
+	}
+	if data.Activation != nil {
+		text += "\n🔑 Нужна активация в приложении банка"
+		if data.Activation.Deadline != "" {
+			text += " до " + data.Activation.Deadline
+		}
+	}
+	text += formatConditionsLine(data.Conditions, "")
+
+	return text
 }
 
 // formatCashbackRule форматирует правило кэшбэка для отображения.
@@ -81,17 +90,17 @@ func formatCashbackRule(rule *models.CashbackRule) string {
 			"🏦 Банк: %s\n"+
 			"📁 Категория: %s\n"+
 			"📅 Действует до: %s\n"+
-			"💰 Кэшбэк: %.1f%%\n"+
+			"💰 Кэшбэк: %s\n"+
 			"💵 Макс. сумма: %.0f₽\n"+
 			"👤 Карта: %s",
 		rule.ID,
 		rule.BankName,
 		rule.Category,
 		rule.MonthYear.Format("02.01.2006"),
-		rule.CashbackPercent,
+		formatRewardPercent(rule),
 		rule.MaxAmount,
 		rule.UserDisplayName,
-	)
+	) + formatCardLine(rule, "") + formatMerchantLine(rule, "") + formatConditionsLine(rule.Conditions, "")
 }
 
 // formatSavedCashback форматирует сохранённый кэшбэк.
@@ -102,36 +111,37 @@ func formatSavedCashback(rule *models.CashbackRule) string {
 			"🏦 Банк: %s\n"+
 			"📁 Категория: %s\n"+
 			"📅 Действует до: %s\n"+
-			"💰 Кэшбэк: %.1f%%\n"+
+			"💰 Кэшбэк: %s\n"+
 			"💵 Макс. сумма: %.0f₽\n"+
 			"👤 Карта: %s",
 		rule.ID,
 		rule.BankName,
 		rule.Category,
 		rule.MonthYear.Format("02.01.2006"),
-		rule.CashbackPercent,
+		formatRewardPercent(rule),
 		rule.MaxAmount,
 		rule.UserDisplayName,
-	)
+	) + formatCardLine(rule, "") + formatMerchantLine(rule, "") + formatConditionsLine(rule.Conditions, "") +
+		formatActivationLine(rule, "")
 }
 
-// formatBestCashback форматирует лучший кэшбэк с учетом fallback.
-func formatBestCashback(rule *models.CashbackRule, requestedCategory string, isFallback bool) string {
+// formatBestCashback форматирует лучший кэшбэк и объясняет,
+// на каком уровне дерева категорий он найден.
+func formatBestCashback(rule *models.CashbackRule, match models.CategoryMatch) string {
 	var text string
 	
-	if isFallback {
+	if match.IsFallback() {
 		text = fmt.Sprintf(
-			"💡 Кэшбэк для категории \"%s\" не найден.\n"+
-				"Показываю кэшбэк на \"Все покупки\":\n\n"+
+			"%s:\n\n"+
 				"🏦 Банк: %s\n"+
 				"📅 Действует до: %s\n"+
-				"💰 Кэшбэк: %.1f%%\n"+
+				"💰 Кэшбэк: %s\n"+
 				"💵 Макс. сумма: %.0f₽\n"+
 				"👤 Карта: %s",
-			requestedCategory,
+			formatFallbackHeader(match),
 			rule.BankName,
 			rule.MonthYear.Format("02.01.2006"),
-			rule.CashbackPercent,
+			formatRewardPercent(rule),
 			rule.MaxAmount,
 			rule.UserDisplayName,
 		)
@@ -140,32 +150,38 @@ func formatBestCashback(rule *models.CashbackRule, requestedCategory string, isF
 		"🏆 Лучший кэшбэк для \"%s\":\n\n"+
 			"🏦 Банк: %s\n"+
 				"📅 Действует до: %s\n"+
-			"💰 Кэшбэк: %.1f%%\n"+
+			"💰 Кэшбэк: %s\n"+
 			"💵 Макс. сумма: %.0f₽\n"+
 			"👤 Карта: %s",
 		rule.Category,
 		rule.BankName,
 			rule.MonthYear.Format("02.01.2006"),
-		rule.CashbackPercent,
+		formatRewardPercent(rule),
 		rule.MaxAmount,
 		rule.UserDisplayName,
 	)
 	}
+
+	text += formatCardLine(rule, "")
+	text += formatMerchantLine(rule, "")
+	text += formatConditionsLine(rule.Conditions, "")
+	text += formatBankCapLine(rule.BankCap, "")
+	text += formatActivationLine(rule, "")
 	
 	return text
 }
 
 // formatAllCashbackResults форматирует все найденные кэшбэки по категории.
-func formatAllCashbackResults(rules []models.CashbackRule, requestedCategory string, isFallback bool) string {
+// match объясняет, на каком уровне дерева категорий они найдены.
+func formatAllCashbackResults(rules []models.CashbackRule, match models.CategoryMatch) string {
 	if len(rules) == 0 {
 		return "❌ Кэшбэк не найден"
 	}
 	
 	var text string
 	
-	if isFallback {
-		text = fmt.Sprintf("💡 Кэшбэк для категории \"%s\" не найден.\n"+
-			"Показываю кэшбэк на \"Все покупки\" (%d вариант", requestedCategory, len(rules))
+	if match.IsFallback() {
+		text = fmt.Sprintf("%s (%d вариант", formatFallbackHeader(match), len(rules))
 		if len(rules) == 1 {
 			text += "):\n\n"
 		} else if len(rules) < 5 {
@@ -174,7 +190,7 @@ func formatAllCashbackResults(rules []models.CashbackRule, requestedCategory str
 			text += "ов):\n\n"
 		}
 	} else {
-		text = fmt.Sprintf("🏆 Все кэшбэки для \"%s\" (%d вариант", requestedCategory, len(rules))
+		text = fmt.Sprintf("🏆 Все кэшбэки для \"%s\" (%d вариант", match.RequestedCategory, len(rules))
 		if len(rules) == 1 {
 			text += "):\n\n"
 		} else if len(rules) < 5 {
@@ -199,17 +215,19 @@ func formatAllCashbackResults(rules []models.CashbackRule, requestedCategory str
 		text += fmt.Sprintf(
 			"%s🏦 %s\n"+
 				"   📁 %s\n"+
-				"   💰 %.1f%% до %.0f₽\n"+
+				"   💰 %s до %.0f₽%s\n"+
 				"   📅 До %s\n"+
-				"   👤 %s\n"+
+				"   👤 %s%s\n"+
 				"   🆔 ID: %d\n\n",
 			medal,
 			rule.BankName,
 			rule.Category,
-			rule.CashbackPercent,
+			formatRewardPercent(&rule),
 			rule.MaxAmount,
+			formatConditionsLine(rule.Conditions, "   "),
 			rule.MonthYear.Format("02.01.2006"),
 			rule.UserDisplayName,
+			formatCardLine(&rule, "   ")+formatActivationLine(&rule, "   "),
 			rule.ID,
 		)
 	}
@@ -227,11 +245,11 @@ func formatCashbackList(rules []models.CashbackRule, total int) string {
 
 	for i, rule := range rules {
 		text += fmt.Sprintf(
-			"%d. %s - %s\n   %.1f%% до %.0f₽ (до %s)\n   👤 Карта: %s\n   ID: %d\n\n",
+			"%d. %s - %s\n   %s до %.0f₽ (до %s)\n   👤 Карта: %s\n   ID: %d\n\n",
 			i+1,
 			rule.BankName,
 			rule.Category,
-			rule.CashbackPercent,
+			formatRewardPercent(&rule),
 			rule.MaxAmount,
 			rule.MonthYear.Format("02.01.2006"),
 			rule.UserDisplayName,
@@ -263,13 +281,13 @@ func formatCashbackListTable(rules []models.CashbackRule, total int, showAll boo
 		text += fmt.Sprintf(
 			"%d. 🏦 %s\n"+
 			"   📁 %s\n"+
-			"   💰 %.1f%% до %.0f₽\n"+
+			"   💰 %s до %.0f₽\n"+
 			"   📅 До %s\n"+
 			"   👤 %s (ID: %d)\n\n",
 			i+1,
 			rule.BankName,
 			rule.Category,
-			rule.CashbackPercent,
+			formatRewardPercent(&rule),
 			rule.MaxAmount,
 			rule.MonthYear.Format("02.01.2006"),
 			rule.UserDisplayName,
@@ -287,6 +305,37 @@ func formatCashbackListTable(rules []models.CashbackRule, total int, showAll boo
 	return text
 }
 
+// formatCashbackListPage форматирует страницу списка кэшбэков с сквозной нумерацией.
+func formatCashbackListPage(rules []models.CashbackRule, total, page, totalPages int) string {
+	if len(rules) == 0 {
+		return "📝 Пока нет кешбека в группе.\n\nДобавьте первым!"
+	}
+
+	text := fmt.Sprintf("📋 Кешбеки группы — страница %d из %d (всего %d):\n\n", page+1, totalPages, total)
+
+	for i, rule := range rules {
+		text += fmt.Sprintf(
+			"%d. 🏦 %s\n"+
+				"   📁 %s\n"+
+				"   💰 %s до %.0f₽\n"+
+				"   📅 До %s\n"+
+				"   👤 %s (ID: %d)\n\n",
+			page*ListPageSize+i+1,
+			rule.BankName,
+			rule.Category,
+			formatRewardPercent(&rule),
+			rule.MaxAmount,
+			rule.MonthYear.Format("02.01.2006"),
+			rule.UserDisplayName,
+			rule.ID,
+		)
+	}
+
+	text += "💡 /list all - показать все, /list 1-10 - выбрать по номерам"
+
+	return text
+}
+
 // truncateString обрезает строку до указанной длины
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -302,13 +351,13 @@ func formatBankInfo(bankName string, rules []models.CashbackRule) string {
 	for i, rule := range rules {
 		text += fmt.Sprintf(
 			"%d. 📁 %s\n"+
-				"   💰 %.1f%% до %.0f₽\n"+
+				"   💰 %s до %.0f₽\n"+
 				"   📅 До %s\n"+
 				"   👤 %s\n"+
 				"   🆔 ID: %d\n\n",
 			i+1,
 			rule.Category,
-			rule.CashbackPercent,
+			formatRewardPercent(&rule),
 			rule.MaxAmount,
 			rule.MonthYear.Format("02.01.2006"),
 			rule.UserDisplayName,
@@ -376,15 +425,16 @@ func formatUserInfo(rules []models.CashbackRule, groupName string) string {
 		text += fmt.Sprintf(
 			"%d. 🏦 %s%s\n"+
 				"   📁 %s\n"+
-				"   💰 %.1f%% до %.0f₽\n"+
+				"   💰 %s до %.0f₽%s\n"+
 				"   📅 До %s\n"+
 				"   🆔 ID: %d\n\n",
 			i+1,
 			rule.BankName,
 			statusIcon,
 			rule.Category,
-			rule.CashbackPercent,
+			formatRewardPercent(&rule),
 			rule.MaxAmount,
+			formatCardLine(&rule, "   "),
 			rule.MonthYear.Format("02.01.2006"),
 			rule.ID,
 		)
@@ -439,6 +489,29 @@ func formatUpdatePrompt(rule *models.CashbackRule) string {
 	)
 }
 
+// formatUpdateCopyLine форматирует правило строкой для /update в формате
+// "Банк, Категория, Процент[ программа], Сумма, Дата[, Условия]".
+func formatUpdateCopyLine(rule *models.CashbackRule) string {
+	percent := fmt.Sprintf("%.1f", rule.CashbackPercent)
+	if keyword := rewardProgramKeyword(rule.RewardProgram); keyword != "" {
+		percent += " " + keyword
+	}
+
+	line := fmt.Sprintf("%s, %s, %s, %.0f, %s",
+		rule.BankName,
+		rule.Category,
+		percent,
+		rule.MaxAmount,
+		rule.MonthYear.Format("02.01.2006"),
+	)
+
+	if !rule.Conditions.IsEmpty() {
+		line += ", " + formatConditions(rule.Conditions)
+	}
+
+	return line
+}
+
 // formatDeletePrompt форматирует запрос на удаление.
 func formatDeletePrompt(rule *models.CashbackRule) string {
 	return fmt.Sprintf(
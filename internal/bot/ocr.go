@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// OCREngine распознаёт текст на изображении.
+type OCREngine interface {
+	// Recognize возвращает распознанный текст изображения (PNG, JPEG).
+	Recognize(ctx context.Context, image []byte) (string, error)
+}
+
+// Проверка реализации интерфейса.
+var _ OCREngine = (*TesseractEngine)(nil)
+
+// TesseractEngine распознаёт текст локально установленным Tesseract OCR.
+type TesseractEngine struct {
+	binary    string
+	languages string
+}
+
+// NewTesseractEngine создаёт движок на основе исполняемого файла tesseract.
+// Возвращает ошибку, если исполняемый файл не найден.
+func NewTesseractEngine(binary, languages string) (*TesseractEngine, error) {
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("tesseract не найден (%s): %w", binary, err)
+	}
+	return &TesseractEngine{binary: path, languages: languages}, nil
+}
+
+// Recognize сохраняет изображение во временный файл и запускает tesseract.
+func (e *TesseractEngine) Recognize(ctx context.Context, image []byte) (string, error) {
+	file, err := os.CreateTemp("", "cashback-ocr-*")
+	if err != nil {
+		return "", fmt.Errorf("создание временного файла: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(image); err != nil {
+		file.Close()
+		return "", fmt.Errorf("запись изображения: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return "", fmt.Errorf("запись изображения: %w", err)
+	}
+
+	// --psm 4: одна колонка текста переменного размера — типичная вёрстка списка категорий
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.binary, file.Name(), "stdout", "-l", e.languages, "--psm", "4")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
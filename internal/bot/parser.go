@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
 )
 
 // ParsedData содержит распарсенные данные от пользователя
@@ -16,6 +18,10 @@ type ParsedData struct {
 	MonthYear       string
 	CashbackPercent float64
 	MaxAmount       float64
+	RewardProgram   string // код программы вознаграждения; пусто — рубли
+	Conditions      models.RuleConditions
+	CardLast4       string                    // последние цифры карты ("***1234"); пусто — карта банка по умолчанию
+	Activation      *models.ActivationRequest // требование активации в приложении банка; nil — не нужна
 }
 
 // ParseMessage пытается извлечь данные из сообщения пользователя
@@ -32,7 +38,7 @@ func ParseMessage(text string) (*ParsedData, error) {
 	return parseFreeText(text)
 }
 
-// parseCommaSeparated парсит данные в формате: "Банк, Категория, Процент, Сумма[, Месяц]"
+// parseCommaSeparated парсит данные в формате: "Банк, Категория, Процент, Сумма[, Месяц[, Условия]]"
 // Месяц опционален - если не указан, используется текущий
 func parseCommaSeparated(text string) (*ParsedData, error) {
 	parts := strings.Split(text, ",")
@@ -50,8 +56,10 @@ func parseCommaSeparated(text string) (*ParsedData, error) {
 	// 2. Категория (автоматическая нормализация)
 	data.Category = normalizeString(parts[1])
 	
-	// 3. Процент
-	percentStr := strings.TrimSpace(parts[2])
+	// 3. Процент (может содержать программу: "10% спасибо")
+	rewardProgram, percentStr := detectRewardProgram(parts[2])
+	data.RewardProgram = rewardProgram
+	percentStr = strings.TrimSpace(percentStr)
 	percentStr = strings.ReplaceAll(percentStr, "%", "")
 	percentStr = strings.TrimSpace(percentStr)
 	if percent, err := strconv.ParseFloat(percentStr, 64); err == nil {
@@ -71,6 +79,22 @@ func parseCommaSeparated(text string) (*ParsedData, error) {
 		return nil, fmt.Errorf("неверный формат суммы: %s", parts[3])
 	}
 	
+	// Карта ("***1234") может стоять вместо даты или среди условий
+	for i := 4; i < len(parts); i++ {
+		if last4, rest := extractCardLast4(parts[i]); last4 != "" {
+			data.CardLast4, parts[i] = last4, strings.TrimSpace(rest)
+			break
+		}
+	}
+
+	// Активация ("активировать до 05.12") тоже может стоять вместо даты или среди условий
+	for i := 4; i < len(parts); i++ {
+		if activation, rest := extractActivation(parts[i], time.Now()); activation != nil {
+			data.Activation, parts[i] = activation, strings.TrimSpace(rest)
+			break
+		}
+	}
+
 	// 5. Дата окончания (опциональна)
 	if len(parts) >= 5 && strings.TrimSpace(parts[4]) != "" {
 		dateStr := strings.TrimSpace(parts[4])
@@ -85,6 +109,15 @@ func parseCommaSeparated(text string) (*ParsedData, error) {
 		lastDay := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, time.UTC)
 		data.MonthYear = lastDay.Format("02.01.2006")
 	}
+
+	// 6. Условия (опциональны): "от 1000₽, выходные, СБП"
+	if len(parts) >= 6 {
+		conditions, err := parseConditions(strings.Join(parts[5:], " "))
+		if err != nil {
+			return nil, err
+		}
+		data.Conditions = conditions
+	}
 	
 	return data, nil
 }
@@ -115,6 +148,9 @@ func parseFreeText(text string) (*ParsedData, error) {
 		}
 	}
 
+	// Программа вознаграждения (спасибо, альфа-баллы, плюс, мили)
+	data.RewardProgram, _ = detectRewardProgram(text)
+
 	// Максимальная сумма (3000р, 3000 рублей, 3000 руб, и т.д.)
 	amountPattern := regexp.MustCompile(`(\d+\.?\d*)\s*(р|руб|рубл|₽|рублей)`)
 	if match := amountPattern.FindStringSubmatch(text); len(match) > 1 {
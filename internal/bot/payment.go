@@ -0,0 +1,297 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// offerPaymentRequest предлагает попросить владельца лучшей карты оплатить
+// покупку, если лучший кэшбэк группы не у того, кто спрашивает.
+// Только в личном чате: сумму покупки бот спрашивает отдельным сообщением.
+func (b *Bot) offerPaymentRequest(message *tgbotapi.Message, best *models.BestCashbackResponse) {
+	if best == nil || best.ID == 0 || isGroupChat(message.Chat) ||
+		best.UserID == strconv.FormatInt(message.From.ID, 10) {
+		return
+	}
+
+	text := fmt.Sprintf("%s Карта с лучшим кэшбэком у %s. Можно попросить оплатить покупку и потом вернуть деньги.",
+		EmojiMoney, best.UserDisplayName)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID, text, [][]inlineButton{{
+		{Text: BtnAskToPay, Action: CallbackPaymentRequest, Payload: strconv.FormatInt(best.ID, 10)},
+	}})
+}
+
+// startPaymentRequest запрашивает сумму покупки после нажатия кнопки под /best.
+func (b *Bot) startPaymentRequest(message *tgbotapi.Message, ruleID int64) {
+	b.setState(message.From.ID, StateAwaitingPaymentAmount, nil, nil, ruleID)
+	b.sendText(message.Chat.ID, "💵 Введите сумму покупки в рублях, например: 1500\n\nДля отмены: /cancel")
+}
+
+// handlePaymentAmountInput создаёт просьбу оплатить и отправляет её владельцу карты.
+func (b *Bot) handlePaymentAmountInput(message *tgbotapi.Message, state *UserState) {
+	amount, err := parsePaymentAmount(message.Text)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ "+err.Error()+"\n\nВведите сумму, например: 1500, или /cancel")
+		return
+	}
+	b.clearState(message.From.ID)
+
+	groupName := b.getUserGroup(message.From.ID)
+	if groupName == "" {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	payment, err := b.client.CreatePaymentRequest(groupName, &models.CreatePaymentRequest{
+		RuleID:        state.RuleID,
+		RequesterID:   strconv.FormatInt(message.From.ID, 10),
+		RequesterName: getUserDisplayName(message.From),
+		Amount:        amount,
+	})
+	if err != nil {
+		log.Printf("❌ Ошибка создания просьбы оплатить: %v", err)
+		b.sendText(message.Chat.ID, "❌ Не удалось создать просьбу оплатить: "+err.Error())
+		return
+	}
+
+	payerID, err := strconv.ParseInt(payment.PayerID, 10, 64)
+	if err == nil {
+		err = b.sendWithButtonsTo(payerID, formatPaymentRequest(payment), paymentAnswerButtons(payment.ID))
+	}
+	if err != nil {
+		log.Printf("⚠️ Не удалось отправить просьбу оплатить %d владельцу карты: %v", payment.ID, err)
+		b.sendText(message.Chat.ID, fmt.Sprintf(
+			"⚠️ Просьба сохранена, но написать %s не получилось: видимо, владелец карты ещё не запускал бота. Попросите открыть чат с ботом.",
+			payment.PayerName))
+		return
+	}
+
+	b.sendText(message.Chat.ID, fmt.Sprintf("📨 Просьба оплатить %s отправлена: %s, %s на \"%s\". Сообщу, когда ответит.",
+		formatRubles(payment.Amount), payment.PayerName, payment.BankName, payment.Category))
+}
+
+// applyPaymentAnswer передаёт ответ владельца карты и сообщает о нём просившему.
+func (b *Bot) applyPaymentAnswer(callback *tgbotapi.CallbackQuery, id int64, accept bool) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+
+	payment, err := b.client.AnswerPaymentRequest(id, strconv.FormatInt(callback.From.ID, 10), accept)
+	if err != nil {
+		if errors.Is(err, ErrPaymentAnswered) {
+			b.answerCallback(callback.ID, "На эту просьбу уже ответили")
+			b.editMessage(chatID, messageID, callback.Message.Text)
+			return
+		}
+		log.Printf("❌ Ошибка ответа на просьбу оплатить %d: %v", id, err)
+		b.answerCallback(callback.ID, "❌ Не удалось сохранить ответ")
+		return
+	}
+
+	label := BtnDeclinePayment
+	if accept {
+		label = BtnAcceptPayment
+	}
+	b.answerCallback(callback.ID, "")
+	b.editMessage(chatID, messageID, callback.Message.Text+"\n\n➡️ "+label)
+
+	requesterID, err := strconv.ParseInt(payment.RequesterID, 10, 64)
+	if err != nil {
+		return
+	}
+	if accept {
+		b.sendText(requesterID, fmt.Sprintf("✅ %s оплатит покупку на %s. Не забудьте вернуть деньги — долг виден в /balance",
+			payment.PayerName, formatRubles(payment.Amount)))
+		return
+	}
+	b.sendText(requesterID, fmt.Sprintf("❌ %s не может оплатить покупку на %s", payment.PayerName, formatRubles(payment.Amount)))
+}
+
+// handleBalance показывает долги участников группы.
+func (b *Bot) handleBalance(message *tgbotapi.Message) {
+	groupName := b.getUserGroup(message.From.ID)
+	if groupName == "" {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	balances, err := b.client.GetGroupBalances(groupName)
+	if err != nil {
+		log.Printf("❌ Ошибка получения долгов группы %s: %v", groupName, err)
+		b.sendText(message.Chat.ID, "❌ Не удалось получить долги группы")
+		return
+	}
+
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	b.sendWithInlineButtons(message.Chat.ID, message.From.ID,
+		formatBalances(balances, userIDStr), settleButtons(balances, userIDStr))
+}
+
+// applySettleDebt закрывает долг после нажатия кнопки в /balance и обновляет сообщение.
+func (b *Bot) applySettleDebt(callback *tgbotapi.CallbackQuery, debt *models.Debt) {
+	chatID := callback.Message.Chat.ID
+	messageID := callback.Message.MessageID
+	userIDStr := strconv.FormatInt(callback.From.ID, 10)
+
+	groupName := b.getUserGroup(callback.From.ID)
+	if groupName == "" {
+		b.answerCallback(callback.ID, MsgButtonExpired)
+		return
+	}
+
+	result, err := b.client.SettleDebt(groupName, userIDStr, debt)
+	switch {
+	case errors.Is(err, ErrDebtChanged):
+		b.answerCallback(callback.ID, "Долг изменился — проверьте сумму и нажмите снова")
+	case err != nil:
+		log.Printf("⚠️ Не удалось закрыть долг %s перед %s: %v", debt.DebtorID, userIDStr, err)
+		b.answerCallback(callback.ID, "Этого долга уже нет")
+	default:
+		b.answerCallback(callback.ID, fmt.Sprintf("✅ Долг %s закрыт", formatRubles(result.Amount)))
+	}
+
+	balances, err := b.client.GetGroupBalances(groupName)
+	if err != nil {
+		b.editMessage(chatID, messageID, callback.Message.Text)
+		return
+	}
+	b.editMessageWithInlineButtons(chatID, messageID, callback.From.ID,
+		formatBalances(balances, userIDStr), settleButtons(balances, userIDStr))
+}
+
+// sendWithButtonsTo отправляет сообщение с inline кнопками и возвращает ошибку
+// отправки: написать первым пользователю, не запускавшему бота, Telegram не даёт.
+func (b *Bot) sendWithButtonsTo(userID int64, text string, rows [][]inlineButton) error {
+	markup, err := b.buildInlineKeyboard(userID, rows)
+	if err != nil {
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(userID, text)
+	msg.ReplyMarkup = markup
+	_, err = b.api.Send(msg)
+	return err
+}
+
+// parsePaymentAmount разбирает сумму покупки ("1500", "1 500,50", "1500₽", "1500 руб").
+func parsePaymentAmount(text string) (float64, error) {
+	value := strings.ToLower(strings.TrimSpace(text))
+	for _, suffix := range []string{"₽", "рублей", "руб.", "руб", "р.", "р"} {
+		value = strings.TrimSuffix(value, suffix)
+	}
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	value = strings.ReplaceAll(value, ",", ".")
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("не удалось распознать сумму: %s", strings.TrimSpace(text))
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("сумма должна быть больше нуля")
+	}
+	return amount, nil
+}
+
+// formatPaymentRequest форматирует просьбу оплатить для владельца карты.
+func formatPaymentRequest(payment *models.PaymentRequest) string {
+	return fmt.Sprintf("%s %s просит оплатить покупку картой %s: %s на \"%s\".\n\n"+
+		"Деньги вам вернут, долг будет виден в /balance.",
+		EmojiMoney, payment.RequesterName, payment.BankName, formatRubles(payment.Amount), payment.Category)
+}
+
+// formatBalances форматирует долги группы; долги пользователя идут первыми.
+func formatBalances(balances *models.GroupBalances, userID string) string {
+	if len(balances.Debts) == 0 {
+		return fmt.Sprintf("%s В группе \"%s\" никто никому не должен", EmojiHandshake, balances.GroupName)
+	}
+
+	var mine, others []string
+	credit := false
+	for _, debt := range balances.Debts {
+		switch userID {
+		case debt.DebtorID:
+			mine = append(mine, fmt.Sprintf("🔴 Вы → %s: %s", debt.CreditorName, formatRubles(debt.Amount)))
+		case debt.CreditorID:
+			mine = append(mine, fmt.Sprintf("🟢 %s → вы: %s", debt.DebtorName, formatRubles(debt.Amount)))
+			credit = true
+		default:
+			others = append(others, fmt.Sprintf("• %s → %s: %s", debt.DebtorName, debt.CreditorName, formatRubles(debt.Amount)))
+		}
+	}
+
+	text := fmt.Sprintf("%s Долги в группе \"%s\" (кто → кому)\n", EmojiMoney, balances.GroupName)
+	if len(mine) > 0 {
+		text += "\n" + strings.Join(mine, "\n") + "\n"
+	}
+	if len(others) > 0 {
+		text += "\n👥 Остальные:\n" + strings.Join(others, "\n") + "\n"
+	}
+	if credit {
+		text += "\nКогда вам вернут деньги, нажмите кнопку ниже."
+	}
+	return strings.TrimSuffix(text, "\n")
+}
+
+// settleButtons — кнопки подтверждения возврата для долгов перед пользователем.
+func settleButtons(balances *models.GroupBalances, userID string) [][]inlineButton {
+	var rows [][]inlineButton
+	for _, debt := range balances.Debts {
+		if debt.CreditorID != userID {
+			continue
+		}
+		rows = append(rows, []inlineButton{{
+			Text:    fmt.Sprintf("✅ %s от %s получены", formatRubles(debt.Amount), debt.DebtorName),
+			Action:  CallbackSettleDebt,
+			Payload: settlePayload(debt),
+		}})
+	}
+	return rows
+}
+
+// settlePayload кодирует в данных кнопки показанный долг: должника,
+// последнюю оплату и сумму в копейках. Так кнопка закрывает только те
+// оплаты, которые видел пользователь.
+func settlePayload(debt models.Debt) string {
+	return fmt.Sprintf("%s_%d_%d", debt.DebtorID, debt.LastPaymentID, int64(math.Round(debt.Amount*100)))
+}
+
+// parseSettlePayload раскодирует данные кнопки подтверждения возврата.
+func parseSettlePayload(payload string) (*models.Debt, error) {
+	parts := strings.Split(payload, "_")
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, fmt.Errorf("%w: неверные данные долга", ErrCallbackInvalid)
+	}
+	lastID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: неверная оплата долга", ErrCallbackInvalid)
+	}
+	kopecks, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: неверная сумма долга", ErrCallbackInvalid)
+	}
+	return &models.Debt{DebtorID: parts[0], LastPaymentID: lastID, Amount: float64(kopecks) / 100}, nil
+}
+
+// paymentAnswerButtons — кнопки ответа владельца карты на просьбу оплатить.
+func paymentAnswerButtons(id int64) [][]inlineButton {
+	payload := strconv.FormatInt(id, 10)
+	return [][]inlineButton{{
+		{Text: BtnAcceptPayment, Action: CallbackPaymentAnswer, Payload: string(choiceYes) + payload},
+		{Text: BtnDeclinePayment, Action: CallbackPaymentAnswer, Payload: string(choiceNo) + payload},
+	}}
+}
+
+// formatRubles форматирует сумму в рублях без копеек, если их нет.
+func formatRubles(amount float64) string {
+	if amount == math.Trunc(amount) {
+		return fmt.Sprintf("%.0f₽", amount)
+	}
+	return fmt.Sprintf("%.2f₽", amount)
+}
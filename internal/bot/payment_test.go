@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestParsePaymentAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"1500", 1500, false},
+		{"1 500,50", 1500.5, false},
+		{"1500₽", 1500, false},
+		{"1500 руб.", 1500, false},
+		{"2000 р", 2000, false},
+		{"0", 0, true},
+		{"-100", 0, true},
+		{"полторы тысячи", 0, true},
+		{"NaN", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePaymentAmount(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePaymentAmount(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePaymentAmount(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatBalances(t *testing.T) {
+	balances := &models.GroupBalances{
+		GroupName: "Семья",
+		Debts: []models.Debt{
+			{DebtorID: "3", DebtorName: "Олег", CreditorID: "2", CreditorName: "Мария", Amount: 2500, LastPaymentID: 12},
+			{DebtorID: "1", DebtorName: "Иван", CreditorID: "2", CreditorName: "Мария", Amount: 700.5, LastPaymentID: 7},
+		},
+	}
+
+	text := formatBalances(balances, "1")
+	if !strings.Contains(text, "🔴 Вы → Мария: 700.50₽") || !strings.Contains(text, "• Олег → Мария: 2500₽") {
+		t.Errorf("formatBalances() должнику = %q", text)
+	}
+	if strings.Contains(text, "нажмите кнопку") {
+		t.Errorf("formatBalances() должнику не нужна подсказка про кнопку: %q", text)
+	}
+
+	text = formatBalances(balances, "2")
+	if !strings.Contains(text, "🟢 Олег → вы: 2500₽") || !strings.Contains(text, "нажмите кнопку") {
+		t.Errorf("formatBalances() кредитору = %q", text)
+	}
+
+	rows := settleButtons(balances, "2")
+	if len(rows) != 2 || rows[0][0].Payload != "3_12_250000" || rows[0][0].Action != CallbackSettleDebt {
+		t.Errorf("settleButtons() = %+v", rows)
+	}
+	debt, err := parseSettlePayload(rows[1][0].Payload)
+	if err != nil || debt.DebtorID != "1" || debt.LastPaymentID != 7 || debt.Amount != 700.5 {
+		t.Errorf("parseSettlePayload(%q) = %+v, %v", rows[1][0].Payload, debt, err)
+	}
+	if _, err := parseSettlePayload("3"); err == nil {
+		t.Error("parseSettlePayload() старой кнопки: ожидалась ошибка")
+	}
+	if rows := settleButtons(balances, "1"); len(rows) != 0 {
+		t.Errorf("settleButtons() должнику = %+v", rows)
+	}
+
+	empty := formatBalances(&models.GroupBalances{GroupName: "Семья"}, "1")
+	if !strings.Contains(empty, "никто никому не должен") {
+		t.Errorf("formatBalances() без долгов = %q", empty)
+	}
+}
+
+func TestPaymentButtonsFitCallbackLimit(t *testing.T) {
+	codec := NewCallbackCodec("test-token")
+
+	for _, row := range paymentAnswerButtons(9223372036854775807) {
+		for _, btn := range row {
+			if _, err := codec.Encode(btn.Action, btn.Payload, 9223372036854775807); err != nil {
+				t.Errorf("Encode(%s, %s) error = %v", btn.Action, btn.Payload, err)
+			}
+		}
+	}
+}
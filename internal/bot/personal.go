@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// personalBestSummary получает лучший кэшбэк группы и лучший по картам
+// пользователя и форматирует оба ответа. При ошибке возвращает пустую строку
+// и nil: список кэшбэков пользователь всё равно увидит.
+func (b *Bot) personalBestSummary(userID int64, groupName, category, monthYear string) (string, *models.BestCashbackResponse) {
+	userIDStr := strconv.FormatInt(userID, 10)
+	best, err := b.client.GetBestCashback(groupName, category, monthYear, userIDStr)
+	if err != nil {
+		log.Printf("⚠️ Не удалось получить личный лучший кэшбэк для '%s': %v", category, err)
+		return "", nil
+	}
+	return formatPersonalBest(best, userIDStr), best
+}
+
+// formatPersonalBest форматирует два ответа: лучший кэшбэк группы с владельцем
+// и лучший кэшбэк по картам того, кто спрашивает.
+func formatPersonalBest(best *models.BestCashbackResponse, userID string) string {
+	if best.UserID == userID {
+		return "\n\n🙋 Лучший кэшбэк группы — ваш: " + formatBestChoice(&best.CashbackRule, best.Match) +
+			formatBankCapLine(best.BankCap, "") + formatActivationLine(&best.CashbackRule, "")
+	}
+
+	text := fmt.Sprintf("\n\n👥 Лучший в группе: %s — 👤 %s",
+		formatBestChoice(&best.CashbackRule, best.Match), best.UserDisplayName)
+	text += formatBankCapLine(best.BankCap, "")
+	text += formatActivationLine(&best.CashbackRule, "")
+
+	if best.Personal == nil {
+		return text + fmt.Sprintf("\n🙋 Своего кэшбэка на \"%s\" у вас нет", best.Match.RequestedCategory)
+	}
+	return text + "\n🙋 Ваш лучший: " + formatBestChoice(&best.Personal.CashbackRule, best.Personal.Match) +
+		formatBankCapLine(best.Personal.BankCap, "") + formatActivationLine(&best.Personal.CashbackRule, "")
+}
+
+// formatBestChoice кратко описывает правило: банк, процент, категорию,
+// если она выше по дереву, и карту.
+func formatBestChoice(rule *models.CashbackRule, match models.CategoryMatch) string {
+	text := fmt.Sprintf("%s %s", rule.BankName, formatRewardPercent(rule))
+	if match.IsFallback() || (match.RequestedCategory != "" && !strings.EqualFold(rule.Category, match.RequestedCategory)) {
+		text += fmt.Sprintf(" на \"%s\"", rule.Category)
+	}
+	if rule.Card != nil {
+		text += fmt.Sprintf(" (%s)", formatCardLabel(rule.Card))
+	}
+	return text
+}
@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatPersonalBest(t *testing.T) {
+	match := requestedLevel("Такси")
+	group := models.CashbackRule{
+		UserID: "1", UserDisplayName: "Анна", BankName: "Сбер", Category: "Такси", CashbackPercent: 10,
+	}
+	own := models.CashbackRule{
+		UserID: "2", BankName: "Тинькофф", Category: "Все покупки", CashbackPercent: 2,
+		Card: &models.Card{BankName: "Тинькофф", Last4: "1234", PaymentSystem: models.PaymentSystemMir},
+	}
+	ownMatch := models.CategoryMatch{RequestedCategory: "Такси", Category: "Все покупки", Level: 1, Path: []string{"Такси", "Все покупки"}}
+
+	best := &models.BestCashbackResponse{CashbackRule: group, Match: match}
+	if text := formatPersonalBest(best, "1"); !strings.Contains(text, "Лучший кэшбэк группы — ваш: Сбер 10.0%") {
+		t.Errorf("formatPersonalBest() свой лучший = %q", text)
+	}
+
+	if text := formatPersonalBest(best, "2"); !strings.Contains(text, "👥 Лучший в группе: Сбер 10.0% — 👤 Анна") ||
+		!strings.Contains(text, "Своего кэшбэка на \"Такси\" у вас нет") {
+		t.Errorf("formatPersonalBest() без своего = %q", text)
+	}
+
+	best.Personal = &models.PersonalBestCashback{CashbackRule: own, Match: ownMatch}
+	if text := formatPersonalBest(best, "2"); !strings.Contains(text,
+		"🙋 Ваш лучший: Тинькофф 2.0% на \"Все покупки\" (Карта Мир Тинькофф ***1234)") {
+		t.Errorf("formatPersonalBest() со своим = %q", text)
+	}
+
+	best.Personal.BankCap = &models.BankCapUsage{BankCap: models.BankCap{Amount: 1000}, Earned: 1000}
+	if text := formatPersonalBest(best, "2"); !strings.Contains(text,
+		"(Карта Мир Тинькофф ***1234)\n⛔ Общий лимит банка исчерпан") {
+		t.Errorf("formatPersonalBest() со своим при исчерпанном лимите = %q", text)
+	}
+}
@@ -0,0 +1,189 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// isImageMessage проверяет, что сообщение содержит фото или изображение, отправленное файлом.
+func isImageMessage(message *tgbotapi.Message) bool {
+	if len(message.Photo) > 0 {
+		return true
+	}
+	return message.Document != nil && strings.HasPrefix(message.Document.MimeType, "image/")
+}
+
+// handlePhoto распознаёт скриншот банковского приложения и предлагает
+// сохранить найденные категории через многострочное добавление.
+// Название банка берётся из подписи к фото или из распознанного текста.
+func (b *Bot) handlePhoto(message *tgbotapi.Message) {
+	userID := message.From.ID
+
+	if b.ocr == nil {
+		b.sendText(message.Chat.ID, "⚠️ Распознавание скриншотов не настроено.\n\n"+
+			"Добавьте кэшбэк текстом: /add")
+		return
+	}
+
+	fileID := message.Document.FileID
+	if len(message.Photo) > 0 {
+		// Последний размер — самое большое изображение
+		fileID = message.Photo[len(message.Photo)-1].FileID
+	}
+
+	b.sendText(message.Chat.ID, "🔍 Распознаю скриншот...")
+
+	image, err := b.downloadFile(fileID, OCRMaxImageSize)
+	if err != nil {
+		log.Printf("❌ Ошибка загрузки скриншота от @%s: %v", message.From.UserName, err)
+		b.sendText(message.Chat.ID, "❌ Не удалось загрузить изображение")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), OCRTimeout)
+	defer cancel()
+
+	text, err := b.ocr.Recognize(ctx, image)
+	if err != nil {
+		log.Printf("❌ Ошибка распознавания скриншота от @%s: %v", message.From.UserName, err)
+		b.sendText(message.Chat.ID, "❌ Не удалось распознать текст на изображении")
+		return
+	}
+
+	rows := ParseScreenshotText(text)
+	log.Printf("🖼 Скриншот от @%s: распознано строк кэшбэка: %d", message.From.UserName, len(rows))
+
+	if len(rows) == 0 {
+		b.sendText(message.Chat.ID, "❌ На скриншоте не найдено категорий с процентами кэшбэка.\n\n"+
+			"Попробуйте обрезать скриншот до списка категорий или добавьте кэшбэк текстом: /add")
+		return
+	}
+
+	bankName := DetectBank(message.Caption)
+	if bankName == "" {
+		bankName = DetectBank(text)
+	}
+	if bankName == "" {
+		b.sendText(message.Chat.ID, "🏦 Не удалось определить банк.\n\n"+
+			"Отправьте скриншот ещё раз и укажите банк в подписи, например: Тинькофф")
+		return
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		lines = append(lines, FormatScreenshotRow(bankName, row))
+	}
+
+	b.saveState(userID, &UserState{State: StateAwaitingOCRConfirm, Lines: lines})
+	b.sendWithInlineButtons(message.Chat.ID, userID, formatOCRPreview(rows, lines), ocrButtons())
+}
+
+// handleOCRConfirmation обрабатывает текстовый ответ на предпросмотр скриншота.
+func (b *Bot) handleOCRConfirmation(message *tgbotapi.Message, state *UserState) {
+	b.applyOCRConfirmation(message, state, parseAnswerChoice(message.Text))
+}
+
+// applyOCRConfirmation применяет выбор пользователя для распознанных строк.
+func (b *Bot) applyOCRConfirmation(message *tgbotapi.Message, state *UserState, choice answerChoice) {
+	userID := message.From.ID
+
+	switch choice {
+	case choiceYes:
+		b.clearState(userID)
+		b.handleMultilineCashback(message, state.Lines)
+
+	case choiceManual:
+		b.setState(userID, StateAwaitingAddData, nil, nil, 0)
+		b.sendText(message.Chat.ID, "✏️ Скопируйте строки, исправьте и отправьте одним сообщением:\n\n"+
+			"<code>"+html.EscapeString(strings.Join(state.Lines, "\n"))+"</code>")
+
+	case choiceNo, choiceCancel:
+		b.clearState(userID)
+		b.sendText(message.Chat.ID, "🚫 Добавление отменено")
+
+	default:
+		b.sendText(message.Chat.ID, "❓ Ответьте \"да\", чтобы сохранить, \"изменить\" или \"отмена\"")
+	}
+}
+
+// formatOCRPreview форматирует предпросмотр распознанных строк.
+func formatOCRPreview(rows []ScreenshotRow, lines []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🖼 Распознано категорий: %d\n\n", len(rows)))
+
+	for i, line := range lines {
+		sb.WriteString(fmt.Sprintf("%d. %s", i+1, line))
+		if rows[i].MaxAmount == 0 {
+			sb.WriteString(" ⚠️ лимит не распознан")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n❓ Сохранить все?")
+	return sb.String()
+}
+
+// ocrButtons — кнопки подтверждения распознанного скриншота.
+func ocrButtons() [][]inlineButton {
+	return [][]inlineButton{
+		{{Text: BtnSaveAll, Action: CallbackOCRConfirm, Payload: string(choiceYes)}},
+		{
+			{Text: BtnManualEdit, Action: CallbackOCRConfirm, Payload: string(choiceManual)},
+			{Text: BtnCancel, Action: CallbackOCRConfirm, Payload: string(choiceCancel)},
+		},
+	}
+}
+
+// downloadFile скачивает файл из Telegram, ограничивая размер.
+func (b *Bot) downloadFile(fileID string, maxSize int64) ([]byte, error) {
+	fileURL, err := b.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("получение ссылки на файл: %w", stripURL(err))
+	}
+
+	return fetchFile(fileURL, maxSize)
+}
+
+// fetchFile скачивает файл по прямой ссылке Telegram, ограничивая размер.
+// Ссылка содержит токен бота, поэтому в ошибки она не попадает.
+func fetchFile(fileURL string, maxSize int64) ([]byte, error) {
+	client := &http.Client{Timeout: HTTPClientTimeout}
+	resp, err := client.Get(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("загрузка файла: %w", stripURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("загрузка файла: статус %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("чтение файла: %w", stripURL(err))
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("файл больше %d байт", maxSize)
+	}
+
+	return data, nil
+}
+
+// stripURL убирает из ошибки HTTP-запроса адрес: в адресах Telegram API
+// есть токен бота, и он не должен попасть в логи.
+func stripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,24 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFetchFileHidesBotToken(t *testing.T) {
+	const token = "123456:SECRET-TOKEN"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	fileURL := server.URL + "/file/bot" + token + "/photos/file_1.jpg"
+	server.Close()
+
+	_, err := fetchFile(fileURL, OCRMaxImageSize)
+	if err == nil {
+		t.Fatal("fetchFile() ожидалась ошибка для остановленного сервера")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("fetchFile() ошибка содержит токен бота: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// rewardProgramKeywords сопоставляет слова в сообщении с кодами программ
+// вознаграждения (reward_programs.code). Порядок важен: первое совпадение.
+var rewardProgramKeywords = []struct {
+	keyword string
+	code    string
+}{
+	{"спасибо", "sber_spasibo"},
+	{"альфа-балл", "alfa_points"},
+	{"альфабалл", "alfa_points"},
+	{"плюс", "yandex_plus"},
+	{"браво", "tinkoff_bravo"},
+	{"аэрофлот", "aeroflot_miles"},
+}
+
+// rewardProgramLabels — названия программ для показа до сохранения правила
+// (после сохранения название приходит от API в RewardProgramName).
+var rewardProgramLabels = map[string]string{
+	"sber_spasibo":   "СберСпасибо",
+	"alfa_points":    "Альфа-баллы",
+	"yandex_plus":    "Баллы Плюса",
+	"tinkoff_bravo":  "Мили Т-Банка",
+	"aeroflot_miles": "Мили Аэрофлот Бонус",
+}
+
+// rewardProgramLabel возвращает название программы по коду.
+func rewardProgramLabel(code string) string {
+	if label, ok := rewardProgramLabels[code]; ok {
+		return label
+	}
+	return code
+}
+
+// rewardProgramKeyword возвращает слово, по которому распознаётся программа,
+// или пустую строку для рублей и неизвестных программ.
+func rewardProgramKeyword(code string) string {
+	for _, kw := range rewardProgramKeywords {
+		if kw.code == code {
+			return kw.keyword
+		}
+	}
+	return ""
+}
+
+// detectRewardProgram ищет в тексте упоминание программы вознаграждения.
+// Возвращает код программы и текст без найденного слова; пустой код — рубли.
+func detectRewardProgram(text string) (string, string) {
+	lower := strings.ToLower(text)
+	for _, kw := range rewardProgramKeywords {
+		idx := strings.Index(lower, kw.keyword)
+		if idx < 0 {
+			continue
+		}
+
+		// Удаляем слово целиком вместе с окончанием ("спасибками", "баллами")
+		end := idx + len(kw.keyword)
+		for end < len(lower) && lower[end] != ' ' && lower[end] != ',' {
+			end++
+		}
+		return kw.code, strings.TrimSpace(text[:idx] + text[end:])
+	}
+	return "", text
+}
+
+// isRublesReward сообщает, начисляется ли кэшбэк правила в рублях.
+func isRublesReward(rule *models.CashbackRule) bool {
+	return rule.RewardProgram == "" || rule.RewardProgram == models.DefaultRewardProgram
+}
+
+// formatRewardPercent форматирует процент кэшбэка с учётом программы:
+// "5.0%" для рублей или "10.0% СберСпасибо (≈5.0% ₽)" для баллов и миль.
+func formatRewardPercent(rule *models.CashbackRule) string {
+	if isRublesReward(rule) {
+		return fmt.Sprintf("%.1f%%", rule.CashbackPercent)
+	}
+	return fmt.Sprintf("%.1f%% %s (≈%.1f%% ₽)",
+		rule.CashbackPercent, rule.RewardProgramName, rule.EffectivePercent)
+}
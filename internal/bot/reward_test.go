@@ -0,0 +1,37 @@
+package bot
+
+import "testing"
+
+func TestDetectRewardProgram(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantCode string
+		wantRest string
+	}{
+		{"5%", "", "5%"},
+		{"10% спасибо", "sber_spasibo", "10%"},
+		{"7% Альфа-баллами", "alfa_points", "7%"},
+		{"3 % плюсами", "yandex_plus", "3 %"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			code, rest := detectRewardProgram(tt.input)
+			if code != tt.wantCode || rest != tt.wantRest {
+				t.Errorf("detectRewardProgram(%q) = (%q, %q), ожидалось (%q, %q)",
+					tt.input, code, rest, tt.wantCode, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestParseMessageRewardProgram(t *testing.T) {
+	data, err := ParseMessage("Сбер, Такси, 10% спасибо, 3000")
+	if err != nil {
+		t.Fatalf("ParseMessage() error = %v", err)
+	}
+	if data.RewardProgram != "sber_spasibo" || data.CashbackPercent != 10 {
+		t.Errorf("ParseMessage() = программа %q, процент %.1f; ожидалось sber_spasibo, 10",
+			data.RewardProgram, data.CashbackPercent)
+	}
+}
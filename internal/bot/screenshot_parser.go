@@ -0,0 +1,207 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ScreenshotRow — строка категории кэшбэка, распознанная на скриншоте.
+type ScreenshotRow struct {
+	Category  string
+	Percent   float64
+	MaxAmount float64
+}
+
+// Паттерны для разбора текста скриншотов банковских приложений.
+var (
+	// screenshotPercentPattern — процент кэшбэка: "5%", "1,5 %".
+	screenshotPercentPattern = regexp.MustCompile(`(\d{1,2}(?:[.,]\d{1,2})?)\s*%`)
+
+	// screenshotAmountPattern — сумма в рублях: "3000 ₽", "3 000 р", "5000руб".
+	// OCR часто распознаёт знак рубля как "Р" или латинскую "P".
+	screenshotAmountPattern = regexp.MustCompile(`(\d{1,3}(?:[ \x{00A0}]\d{3})+|\d+)\s*(₽|руб|[рРP])`)
+
+	// screenshotNoiseWords — слова, которые не относятся к названию категории.
+	screenshotNoiseWords = map[string]bool{
+		"кэшбэк": true, "кешбэк": true, "кэшбек": true, "кешбек": true, "cashback": true,
+		"до": true, "лимит": true, "в": true, "месяц": true, "мес": true, "на": true,
+	}
+)
+
+// ParseScreenshotText извлекает строки "категория — процент — лимит" из текста,
+// распознанного на скриншоте банковского приложения.
+//
+// Поддерживаются варианты вёрстки, когда категория и процент находятся в одной
+// строке ("Такси 5%"), и когда категория, процент и лимит идут отдельными строками.
+// Строка с лимитом без процента ("Лимит 3000 ₽ в месяц") считается общим лимитом
+// для категорий, у которых собственный лимит не указан.
+func ParseScreenshotText(text string) []ScreenshotRow {
+	lines := screenshotLines(text)
+
+	var commonLimit float64
+	for _, line := range lines {
+		if screenshotPercentPattern.MatchString(line) {
+			continue
+		}
+		if amount, _ := findScreenshotAmount(line); amount > 0 && isLimitLine(line) {
+			commonLimit = amount
+			break
+		}
+	}
+
+	var rows []ScreenshotRow
+	seen := make(map[string]bool)
+
+	for i, line := range lines {
+		match := screenshotPercentPattern.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+
+		percent, err := strconv.ParseFloat(strings.Replace(line[match[2]:match[3]], ",", ".", 1), 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			continue
+		}
+
+		rest := line[:match[0]] + " " + line[match[1]:]
+		amount, amountText := findScreenshotAmount(rest)
+		if amountText != "" {
+			rest = strings.Replace(rest, amountText, " ", 1)
+		}
+		category := cleanScreenshotCategory(rest)
+
+		// Категория строкой выше или ниже процента
+		if category == "" && i > 0 && isCategoryLine(lines[i-1]) {
+			category = cleanScreenshotCategory(lines[i-1])
+		}
+		if category == "" && i+1 < len(lines) && isCategoryLine(lines[i+1]) {
+			category = cleanScreenshotCategory(lines[i+1])
+		}
+		if category == "" {
+			continue
+		}
+
+		// Лимит строкой ниже процента
+		if amount == 0 && i+1 < len(lines) && !screenshotPercentPattern.MatchString(lines[i+1]) {
+			amount, _ = findScreenshotAmount(lines[i+1])
+		}
+		if amount == 0 {
+			amount = commonLimit
+		}
+
+		key := strings.ToLower(category)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		rows = append(rows, ScreenshotRow{
+			Category:  category,
+			Percent:   percent,
+			MaxAmount: amount,
+		})
+	}
+
+	return rows
+}
+
+// DetectBank ищет в тексте название известного банка.
+// При нескольких совпадениях выбирается самое длинное ("Сбербанк", а не "Сбер").
+func DetectBank(text string) string {
+	textLower := strings.ToLower(text)
+
+	var found string
+	for _, bank := range KnownBanks {
+		if strings.Contains(textLower, strings.ToLower(bank)) && len(bank) > len(found) {
+			found = bank
+		}
+	}
+	return found
+}
+
+// FormatScreenshotRow форматирует строку скриншота в формат ввода /add:
+// "Банк, Категория, Процент, Сумма".
+func FormatScreenshotRow(bankName string, row ScreenshotRow) string {
+	return fmt.Sprintf("%s, %s, %s%%, %.0f",
+		bankName, row.Category, strconv.FormatFloat(row.Percent, 'f', -1, 64), row.MaxAmount)
+}
+
+// screenshotLines разбивает текст на непустые нормализованные строки.
+func screenshotLines(text string) []string {
+	text = strings.ReplaceAll(text, "\u00a0", " ")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = normalizeString(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// findScreenshotAmount находит сумму в строке.
+// Возвращает сумму и исходный фрагмент текста с ней.
+func findScreenshotAmount(line string) (float64, string) {
+	for _, match := range screenshotAmountPattern.FindAllStringSubmatchIndex(line, -1) {
+		// Буква после "р" означает начало слова ("3 Рестораны"), а не знак рубля
+		if next := line[match[1]:]; next != "" {
+			if r := []rune(next)[0]; unicode.IsLetter(r) {
+				continue
+			}
+		}
+
+		digits := strings.NewReplacer(" ", "", "\u00a0", "").Replace(line[match[2]:match[3]])
+		amount, err := strconv.ParseFloat(digits, 64)
+		if err != nil || amount <= 0 {
+			continue
+		}
+		return amount, line[match[0]:match[1]]
+	}
+	return 0, ""
+}
+
+// isLimitLine проверяет, что строка описывает лимит кэшбэка.
+func isLimitLine(line string) bool {
+	lower := strings.ToLower(line)
+	return strings.Contains(lower, "лимит") || strings.HasPrefix(lower, "до ") || strings.Contains(lower, " до ")
+}
+
+// isCategoryLine проверяет, что строка может быть названием категории:
+// без процента и суммы, с буквами.
+func isCategoryLine(line string) bool {
+	if screenshotPercentPattern.MatchString(line) {
+		return false
+	}
+	if amount, _ := findScreenshotAmount(line); amount > 0 {
+		return false
+	}
+	return cleanScreenshotCategory(line) != ""
+}
+
+// cleanScreenshotCategory убирает из строки служебные слова, цифры и символы.
+func cleanScreenshotCategory(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || r == '-' || r == ' ' {
+			return r
+		}
+		return ' '
+	}, text)
+
+	var words []string
+	for _, word := range strings.Fields(text) {
+		word = strings.Trim(word, "-")
+		if word == "" || screenshotNoiseWords[strings.ToLower(word)] {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	// Одиночные буквы — обычно мусор распознавания иконок
+	if len(words) == 1 && len([]rune(words[0])) < 2 {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
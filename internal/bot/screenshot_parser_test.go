@@ -0,0 +1,78 @@
+package bot
+
+import "testing"
+
+func TestParseScreenshotTextSameLine(t *testing.T) {
+	text := `Кэшбэк в ноябре
+Такси 5%
+Аптеки 3 % до 1 000 ₽
+Рестораны 10%
+Лимит 3 000 ₽ в месяц`
+
+	rows := ParseScreenshotText(text)
+
+	expected := []ScreenshotRow{
+		{Category: "Такси", Percent: 5, MaxAmount: 3000},
+		{Category: "Аптеки", Percent: 3, MaxAmount: 1000},
+		{Category: "Рестораны", Percent: 10, MaxAmount: 3000},
+	}
+	assertScreenshotRows(t, rows, expected)
+}
+
+func TestParseScreenshotTextSeparateLines(t *testing.T) {
+	text := `Супермаркеты
+1,5%
+до 5000 р
+
+Фастфуд
+7%
+до 2000Р`
+
+	rows := ParseScreenshotText(text)
+
+	expected := []ScreenshotRow{
+		{Category: "Супермаркеты", Percent: 1.5, MaxAmount: 5000},
+		{Category: "Фастфуд", Percent: 7, MaxAmount: 2000},
+	}
+	assertScreenshotRows(t, rows, expected)
+}
+
+func TestParseScreenshotTextIgnoresNoise(t *testing.T) {
+	rows := ParseScreenshotText("12:45\nВаши категории\n• 5\n")
+	if len(rows) != 0 {
+		t.Errorf("Expected no rows, got %+v", rows)
+	}
+}
+
+func TestDetectBankPrefersLongestMatch(t *testing.T) {
+	if bank := DetectBank("СберБанк Онлайн"); bank != "Сбербанк" {
+		t.Errorf("Expected Сбербанк, got %q", bank)
+	}
+	if bank := DetectBank("что-то непонятное"); bank != "" {
+		t.Errorf("Expected no bank, got %q", bank)
+	}
+}
+
+func TestFormatScreenshotRowParsesBack(t *testing.T) {
+	line := FormatScreenshotRow("Тинькофф", ScreenshotRow{Category: "Такси", Percent: 1.5, MaxAmount: 3000})
+
+	data, err := ParseMessage(line)
+	if err != nil {
+		t.Fatalf("ParseMessage(%q) failed: %v", line, err)
+	}
+	if data.BankName != "Тинькофф" || data.Category != "Такси" || data.CashbackPercent != 1.5 || data.MaxAmount != 3000 {
+		t.Errorf("Unexpected parsed data: %+v", data)
+	}
+}
+
+func assertScreenshotRows(t *testing.T, got, want []ScreenshotRow) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d rows, got %d: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Row %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
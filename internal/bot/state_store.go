@@ -0,0 +1,193 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrStateNotFound возвращается, если у пользователя нет активного состояния.
+var ErrStateNotFound = errors.New("состояние не найдено")
+
+// StateStore определяет хранилище состояний диалогов с пользователями.
+// Реализации должны быть безопасны для конкурентного использования.
+type StateStore interface {
+	// Get возвращает состояние пользователя или ErrStateNotFound.
+	Get(ctx context.Context, userID int64) (*UserState, error)
+	// Set сохраняет состояние пользователя и продлевает срок его жизни.
+	Set(ctx context.Context, userID int64, state *UserState) error
+	// Delete удаляет состояние пользователя.
+	Delete(ctx context.Context, userID int64) error
+	// DeleteExpired удаляет брошенные состояния с истёкшим сроком жизни.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// Проверка реализации интерфейса.
+var (
+	_ StateStore = (*MemoryStateStore)(nil)
+	_ StateStore = (*PostgresStateStore)(nil)
+)
+
+// --- In-memory хранилище ---
+
+// memoryStateEntry хранит состояние и момент его истечения.
+type memoryStateEntry struct {
+	state     UserState
+	expiresAt time.Time
+}
+
+// MemoryStateStore хранит состояния в памяти процесса.
+// Состояния теряются при перезапуске и не разделяются между репликами.
+type MemoryStateStore struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[int64]memoryStateEntry
+}
+
+// NewMemoryStateStore создаёт in-memory хранилище с указанным временем жизни состояний.
+func NewMemoryStateStore(ttl time.Duration) *MemoryStateStore {
+	return &MemoryStateStore{
+		ttl:     ttl,
+		entries: make(map[int64]memoryStateEntry),
+	}
+}
+
+// Get возвращает копию состояния пользователя.
+func (s *MemoryStateStore) Get(_ context.Context, userID int64) (*UserState, error) {
+	s.mu.RLock()
+	entry, exists := s.entries[userID]
+	s.mu.RUnlock()
+
+	if !exists || time.Now().After(entry.expiresAt) {
+		return nil, ErrStateNotFound
+	}
+
+	state := entry.state
+	return &state, nil
+}
+
+// Set сохраняет копию состояния пользователя.
+func (s *MemoryStateStore) Set(_ context.Context, userID int64, state *UserState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[userID] = memoryStateEntry{
+		state:     *state,
+		expiresAt: time.Now().Add(s.ttl),
+	}
+	return nil
+}
+
+// Delete удаляет состояние пользователя.
+func (s *MemoryStateStore) Delete(_ context.Context, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, userID)
+	return nil
+}
+
+// DeleteExpired удаляет истёкшие состояния.
+func (s *MemoryStateStore) DeleteExpired(_ context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var deleted int64
+	for userID, entry := range s.entries {
+		if now.After(entry.expiresAt) {
+			delete(s.entries, userID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// --- PostgreSQL хранилище ---
+
+// SQL запросы для работы с состояниями бота.
+const (
+	// queryGetBotState — получение неистёкшего состояния.
+	queryGetBotState = `
+		SELECT state FROM bot_states
+		WHERE user_id = $1 AND expires_at > NOW()`
+
+	// querySetBotState — сохранение состояния с продлением срока жизни.
+	querySetBotState = `
+		INSERT INTO bot_states (user_id, state, expires_at, updated_at)
+		VALUES ($1, $2, NOW() + $3 * INTERVAL '1 second', NOW())
+		ON CONFLICT (user_id)
+		DO UPDATE SET state = $2, expires_at = NOW() + $3 * INTERVAL '1 second', updated_at = NOW()`
+
+	// queryDeleteBotState — удаление состояния.
+	queryDeleteBotState = `DELETE FROM bot_states WHERE user_id = $1`
+
+	// queryDeleteExpiredBotStates — удаление истёкших состояний.
+	queryDeleteExpiredBotStates = `DELETE FROM bot_states WHERE expires_at <= NOW()`
+)
+
+// PostgresStateStore хранит состояния в таблице bot_states.
+// Переживает перезапуск бота и позволяет запускать несколько реплик.
+type PostgresStateStore struct {
+	pool *pgxpool.Pool
+	ttl  time.Duration
+}
+
+// NewPostgresStateStore создаёт хранилище состояний в PostgreSQL.
+func NewPostgresStateStore(pool *pgxpool.Pool, ttl time.Duration) *PostgresStateStore {
+	return &PostgresStateStore{pool: pool, ttl: ttl}
+}
+
+// Get возвращает состояние пользователя.
+func (s *PostgresStateStore) Get(ctx context.Context, userID int64) (*UserState, error) {
+	var raw []byte
+	err := s.pool.QueryRow(ctx, queryGetBotState, userID).Scan(&raw)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStateNotFound
+		}
+		return nil, fmt.Errorf("получение состояния %d: %w", userID, err)
+	}
+
+	var state UserState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("десериализация состояния %d: %w", userID, err)
+	}
+	return &state, nil
+}
+
+// Set сохраняет состояние пользователя.
+func (s *PostgresStateStore) Set(ctx context.Context, userID int64, state *UserState) error {
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("сериализация состояния %d: %w", userID, err)
+	}
+
+	if _, err := s.pool.Exec(ctx, querySetBotState, userID, raw, s.ttl.Seconds()); err != nil {
+		return fmt.Errorf("сохранение состояния %d: %w", userID, err)
+	}
+	return nil
+}
+
+// Delete удаляет состояние пользователя.
+func (s *PostgresStateStore) Delete(ctx context.Context, userID int64) error {
+	if _, err := s.pool.Exec(ctx, queryDeleteBotState, userID); err != nil {
+		return fmt.Errorf("удаление состояния %d: %w", userID, err)
+	}
+	return nil
+}
+
+// DeleteExpired удаляет истёкшие состояния.
+func (s *PostgresStateStore) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := s.pool.Exec(ctx, queryDeleteExpiredBotStates)
+	if err != nil {
+		return 0, fmt.Errorf("удаление истёкших состояний: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
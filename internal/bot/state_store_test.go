@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryStateStoreRoundTrip(t *testing.T) {
+	store := NewMemoryStateStore(time.Minute)
+	ctx := context.Background()
+
+	if _, err := store.Get(ctx, 1); !errors.Is(err, ErrStateNotFound) {
+		t.Fatalf("Expected ErrStateNotFound, got %v", err)
+	}
+
+	state := &UserState{State: StateAwaitingDeleteConfirm, RuleID: 42}
+	if err := store.Set(ctx, 1, state); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	// Изменение исходного значения не должно затрагивать хранилище
+	state.RuleID = 7
+
+	got, err := store.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if got.State != StateAwaitingDeleteConfirm || got.RuleID != 42 {
+		t.Errorf("Unexpected state: %+v", got)
+	}
+
+	if err := store.Delete(ctx, 1); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get(ctx, 1); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("Expected ErrStateNotFound after delete, got %v", err)
+	}
+}
+
+func TestMemoryStateStoreExpiry(t *testing.T) {
+	store := NewMemoryStateStore(time.Millisecond)
+	ctx := context.Background()
+
+	if err := store.Set(ctx, 1, &UserState{State: StateAwaitingAddData}); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if _, err := store.Get(ctx, 1); !errors.Is(err, ErrStateNotFound) {
+		t.Errorf("Expected expired state to be missing, got %v", err)
+	}
+
+	deleted, err := store.DeleteExpired(ctx)
+	if err != nil {
+		t.Fatalf("DeleteExpired failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted state, got %d", deleted)
+	}
+}
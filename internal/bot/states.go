@@ -10,13 +10,17 @@ import (
 	"github.com/rymax1e/open-cashback-advisor/internal/models"
 )
 
-// handleConfirmation обрабатывает подтверждение исправлений.
+// handleConfirmation обрабатывает подтверждение исправлений, введённое текстом.
 func (b *Bot) handleConfirmation(message *tgbotapi.Message, state *UserState) {
-	text := strings.ToLower(strings.TrimSpace(message.Text))
+	b.applyConfirmation(message, state, parseAnswerChoice(message.Text))
+}
+
+// applyConfirmation применяет выбранный вариант подтверждения исправлений.
+func (b *Bot) applyConfirmation(message *tgbotapi.Message, state *UserState, choice answerChoice) {
 	userID := message.From.ID
 
-	switch {
-	case isYesAnswer(text):
+	switch choice {
+	case choiceYes:
 		// Применяем исправления
 		data := state.Data
 		if len(state.Suggestion.Suggestions.BankName) > 0 {
@@ -27,11 +31,11 @@ func (b *Bot) handleConfirmation(message *tgbotapi.Message, state *UserState) {
 		}
 		b.saveCashback(message.Chat.ID, message.From, data, false)
 
-	case isNoAnswer(text):
+	case choiceNo:
 		// Сохраняем как есть
-		b.saveCashback(message.Chat.ID, message.From, state.Data, true)
+		b.saveCashback(message.Chat.ID, message.From, state.Data, false)
 
-	case isManualEditAnswer(text):
+	case choiceManual:
 		// Переход в режим ручного ввода
 		b.setState(userID, StateAwaitingManualInput, state.Data, state.Suggestion, 0)
 		b.sendText(message.Chat.ID, "✏️ Отправьте данные в формате:\n"+
@@ -39,7 +43,7 @@ func (b *Bot) handleConfirmation(message *tgbotapi.Message, state *UserState) {
 			"Или /cancel для отмены.")
 		return
 
-	case isCancelAnswer(text):
+	case choiceCancel:
 		b.sendText(message.Chat.ID, "🚫 Операция отменена")
 
 	default:
@@ -50,9 +54,13 @@ func (b *Bot) handleConfirmation(message *tgbotapi.Message, state *UserState) {
 	b.clearState(userID)
 }
 
-// handleBankCorrection обрабатывает подтверждение исправления банка.
+// handleBankCorrection обрабатывает подтверждение исправления банка, введённое текстом.
 func (b *Bot) handleBankCorrection(message *tgbotapi.Message, state *UserState) {
-	text := strings.ToLower(strings.TrimSpace(message.Text))
+	b.applyBankCorrection(message, state, parseAnswerChoice(message.Text))
+}
+
+// applyBankCorrection применяет выбранный вариант исправления банка.
+func (b *Bot) applyBankCorrection(message *tgbotapi.Message, state *UserState, choice answerChoice) {
 	userID := message.From.ID
 
 	// Проверяем, это запрос из /bankinfo или из /add
@@ -68,8 +76,8 @@ func (b *Bot) handleBankCorrection(message *tgbotapi.Message, state *UserState)
 		groupName := state.Data.Category // Временно сохранили название группы в поле Category
 		bankName := state.Data.BankName
 		
-		switch {
-		case isYesAnswer(text):
+		switch choice {
+		case choiceYes:
 			log.Printf("✅ Пользователь подтвердил исправление банка для /bankinfo: %s", bankName)
 			b.clearState(userID)
 			
@@ -82,7 +90,7 @@ func (b *Bot) handleBankCorrection(message *tgbotapi.Message, state *UserState)
 			
 			b.sendText(message.Chat.ID, formatBankInfo(bankName, rules))
 			
-		case isManualEditAnswer(text):
+		case choiceManual:
 			log.Printf("✏️ Пользователь выбрал ручной ввод для /bankinfo")
 			b.setState(userID, StateAwaitingBankInfoName, nil, nil, 0)
 			b.sendText(message.Chat.ID, "🏦 Введите название банка.\n\nИли /cancel для отмены.")
@@ -96,12 +104,12 @@ func (b *Bot) handleBankCorrection(message *tgbotapi.Message, state *UserState)
 	}
 
 	// Обработка для добавления кешбека (старая логика)
-	switch {
-	case isYesAnswer(text):
+	switch choice {
+	case choiceYes:
 		log.Printf("✅ Пользователь подтвердил исправление банка: %s", state.Data.BankName)
 		b.continueWithValidation(message, state.Data)
 		
-	case isManualEditAnswer(text):
+	case choiceManual:
 		// Переход в режим ручного ввода
 		b.setState(userID, StateAwaitingManualInput, state.Data, nil, 0)
 		b.sendText(message.Chat.ID, "✏️ Отправьте данные в формате:\n"+
@@ -118,17 +126,21 @@ func (b *Bot) handleBankCorrection(message *tgbotapi.Message, state *UserState)
 
 // handleCategoryCorrection обрабатывает подтверждение исправления категории при поиске.
 func (b *Bot) handleCategoryCorrection(message *tgbotapi.Message, state *UserState) {
-	text := strings.ToLower(strings.TrimSpace(message.Text))
+	b.applyCategoryCorrection(message, state, parseAnswerChoice(message.Text))
+}
+
+// applyCategoryCorrection применяет выбранный вариант исправления категории.
+func (b *Bot) applyCategoryCorrection(message *tgbotapi.Message, state *UserState, choice answerChoice) {
 	userID := message.From.ID
 
-	switch {
-	case isYesAnswer(text):
+	switch choice {
+	case choiceYes:
 		correctedCategory := state.Data.Category
 		log.Printf("✅ Пользователь подтвердил исправление категории: %s", correctedCategory)
 		b.clearState(userID)
 		b.handleBestQueryWithCorrection(message, correctedCategory, true)
 		
-	case isManualEditAnswer(text):
+	case choiceManual:
 		// Переход в режим ручного ввода для поиска
 		b.clearState(userID)
 		b.sendText(message.Chat.ID, "✏️ Введите название категории для поиска:\n\n"+
@@ -171,6 +183,9 @@ func (b *Bot) handleUpdateData(message *tgbotapi.Message, state *UserState) {
 		MonthYear:       data.MonthYear,
 		CashbackPercent: data.CashbackPercent,
 		MaxAmount:       data.MaxAmount,
+		RewardProgram:   data.RewardProgram,
+		Conditions:      &data.Conditions,
+		Activation:      data.Activation,
 	}
 
 	_, err = b.client.UpdateCashback(state.RuleID, req)
@@ -208,22 +223,25 @@ func (b *Bot) handleUpdateData(message *tgbotapi.Message, state *UserState) {
 	b.clearState(message.From.ID)
 }
 
-// handleDeleteConfirmation обрабатывает подтверждение удаления.
+// handleDeleteConfirmation обрабатывает подтверждение удаления, введённое текстом.
 func (b *Bot) handleDeleteConfirmation(message *tgbotapi.Message, state *UserState) {
 	text := strings.ToLower(strings.TrimSpace(message.Text))
+	b.sendText(message.Chat.ID, b.applyDeleteConfirmation(message, state, isDeleteConfirm(text)))
+}
 
-	if isDeleteConfirm(text) {
-		err := b.client.DeleteCashback(state.RuleID)
-		if err != nil {
-			b.sendText(message.Chat.ID, fmt.Sprintf("❌ Ошибка удаления: %s", err))
-		} else {
-			b.sendText(message.Chat.ID, fmt.Sprintf("✅ %% кешбек ID %d успешно удалён!", state.RuleID))
-		}
-	} else {
-		b.sendText(message.Chat.ID, "❌ Удаление отменено.")
+// applyDeleteConfirmation удаляет правило при подтверждении и возвращает текст результата.
+func (b *Bot) applyDeleteConfirmation(message *tgbotapi.Message, state *UserState, confirmed bool) string {
+	defer b.clearState(message.From.ID)
+
+	if !confirmed {
+		return "❌ Удаление отменено."
 	}
 
-	b.clearState(message.From.ID)
+	if err := b.client.DeleteCashback(state.RuleID); err != nil {
+		return fmt.Sprintf("❌ Ошибка удаления: %s", err)
+	}
+
+	return fmt.Sprintf("✅ %% кешбек ID %d успешно удалён!", state.RuleID)
 }
 
 // isYesAnswer проверяет, является ли ответ положительным.
@@ -278,7 +296,7 @@ func (b *Bot) handleManualInput(message *tgbotapi.Message, state *UserState) {
 		data.BankName, data.Category, data.CashbackPercent, data.MaxAmount)
 
 	// Сохраняем без дополнительной валидации
-	b.saveCashback(message.Chat.ID, message.From, data, true)
+	b.saveCashback(message.Chat.ID, message.From, data, false)
 	b.clearState(message.From.ID)
 }
 
@@ -411,13 +429,7 @@ func (b *Bot) handleUpdateIDInput(message *tgbotapi.Message) {
 	b.sendText(message.Chat.ID, formatUpdatePrompt(rule))
 	
 	// Отправляем второе сообщение только со строкой для копирования
-	copyLine := fmt.Sprintf("%s, %s, %.1f, %.0f, %s",
-		rule.BankName,
-		rule.Category,
-		rule.CashbackPercent,
-		rule.MaxAmount,
-		rule.MonthYear.Format("02.01.2006"),
-	)
+	copyLine := formatUpdateCopyLine(rule)
 	b.sendTextPlain(message.Chat.ID, copyLine)
 	
 	b.setState(userID, StateAwaitingUpdateData, nil, nil, id)
@@ -470,7 +482,7 @@ func (b *Bot) handleDeleteIDInput(message *tgbotapi.Message) {
 	)
 	
 	b.setState(userID, StateAwaitingDeleteConfirm, nil, nil, id)
-	b.sendWithButtons(message.Chat.ID, text, ButtonsDelete)
+	b.sendWithInlineButtons(message.Chat.ID, userID, text, deleteButtons(id))
 }
 
 // handleJoinGroupNameInput обрабатывает ввод названия группы для команды /joingroup.
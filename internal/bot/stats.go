@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// statsTopSize — сколько участников, банков и категорий показывать в /stats.
+const statsTopSize = 5
+
+// handleStats обрабатывает команду /stats [число месяцев].
+func (b *Bot) handleStats(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName, err := b.client.GetUserGroup(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	var from string
+	now := time.Now()
+	if args := strings.TrimSpace(message.CommandArguments()); args != "" {
+		months, err := strconv.Atoi(args)
+		if err != nil || months < 1 || months > 36 {
+			b.sendText(message.Chat.ID, "❌ Укажите число месяцев от 1 до 36. Например: /stats 12")
+			return
+		}
+		start := now.AddDate(0, 1-months, 0)
+		from = fmt.Sprintf("%d-%02d", start.Year(), start.Month())
+	}
+	to := fmt.Sprintf("%d-%02d", now.Year(), now.Month())
+
+	stats, err := b.client.GetGroupStats(groupName, from, to)
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось получить статистику: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatGroupStats(stats))
+}
+
+// formatGroupStats форматирует аналитику группы.
+func formatGroupStats(stats *models.GroupStats) string {
+	text := fmt.Sprintf("📊 Статистика группы <b>%s</b> за %s — %s\n\n",
+		stats.GroupName, stats.From.Format("01.2006"), stats.To.Format("01.2006"))
+
+	if stats.Total.Rules == 0 {
+		return text + "📝 За этот период кэшбэков нет."
+	}
+
+	text += fmt.Sprintf("💳 Кэшбэков: %d, в среднем %.1f%%, максимум %.1f%%\n"+
+		"💰 Потолок кэшбэка: до %.0f₽\n",
+		stats.Total.Rules, stats.Total.AvgPercent, stats.Total.MaxPercent, stats.Total.EstimatedCashback)
+	if stats.Total.EarnedCashback > 0 {
+		text += fmt.Sprintf("🧾 Заработано по покупкам: %s\n", formatRubles(stats.Total.EarnedCashback))
+	}
+
+	text += "\n📈 По месяцам:\n"
+	for i, m := range stats.Months {
+		text += fmt.Sprintf("• %s — %d, %.1f%%, до %.0f₽", m.Month.Format("01.2006"), m.Rules, m.AvgPercent, m.EstimatedCashback)
+		if i > 0 {
+			text += fmt.Sprintf(" (%s)", formatSignedRubles(m.EstimatedChange))
+		}
+		if m.EarnedCashback > 0 {
+			text += ", заработано " + formatRubles(m.EarnedCashback)
+		}
+		text += "\n"
+	}
+
+	text += formatStatsTop("👥 Участники", stats.ByMember)
+	text += formatStatsTop("🏦 Банки", stats.ByBank)
+	text += formatStatsTop("📁 Категории", stats.ByCategory)
+
+	text += "\n📶 Проценты кэшбэка:\n"
+	for _, r := range stats.Distribution {
+		if r.Rules == 0 {
+			continue
+		}
+		text += fmt.Sprintf("• %s — %d\n", formatPercentRange(r), r.Rules)
+	}
+
+	text += "\nℹ️ «Потолок кэшбэка» — сумма лимитов кэшбэков в рублях, «заработано» — кэшбэк по покупкам из /spent и оплатам по просьбам"
+	return text
+}
+
+// formatStatsTop форматирует первые statsTopSize строк сводки.
+func formatStatsTop(title string, buckets []models.StatsBucket) string {
+	if len(buckets) == 0 {
+		return ""
+	}
+
+	text := fmt.Sprintf("\n%s:\n", title)
+	for i, bucket := range buckets {
+		if i == statsTopSize {
+			text += fmt.Sprintf("… и ещё %d\n", len(buckets)-statsTopSize)
+			break
+		}
+		name := bucket.Key
+		if bucket.Label != "" {
+			name = bucket.Label
+		}
+		text += fmt.Sprintf("• %s — %d, в среднем %.1f%%, до %.0f₽", name, bucket.Rules, bucket.AvgPercent, bucket.EstimatedCashback)
+		if bucket.EarnedCashback > 0 {
+			text += ", заработано " + formatRubles(bucket.EarnedCashback)
+		}
+		text += "\n"
+	}
+	return text
+}
+
+// formatPercentRange форматирует диапазон процентов: "3–5%", "от 10%".
+func formatPercentRange(r models.PercentRange) string {
+	switch {
+	case r.From == 0:
+		return fmt.Sprintf("до %g%%", r.To)
+	case r.To == 0:
+		return fmt.Sprintf("от %g%%", r.From)
+	default:
+		return fmt.Sprintf("%g–%g%%", r.From, r.To)
+	}
+}
+
+// formatSignedRubles форматирует изменение суммы со знаком: "+500₽", "−200₽".
+func formatSignedRubles(value float64) string {
+	switch {
+	case value > 0:
+		return fmt.Sprintf("+%.0f₽", value)
+	case value < 0:
+		return fmt.Sprintf("−%.0f₽", -value)
+	default:
+		return "без изменений"
+	}
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestFormatGroupStats(t *testing.T) {
+	stats := &models.GroupStats{
+		GroupName: "Семья",
+		From:      time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		Total:     models.StatsBucket{Rules: 3, AvgPercent: 6, MaxPercent: 10, EstimatedCashback: 4000, EarnedCashback: 250},
+		Months: []models.MonthStats{
+			{Month: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC), Rules: 2, AvgPercent: 4, EstimatedCashback: 1000},
+			{Month: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC), Rules: 1, AvgPercent: 10, EstimatedCashback: 3000, EstimatedChange: 2000, EarnedCashback: 250},
+		},
+		ByMember: []models.StatsBucket{{Key: "1", Label: "Иван", Rules: 3, AvgPercent: 6, EstimatedCashback: 4000}},
+		Distribution: []models.PercentRange{
+			{To: 1}, {From: 3, To: 5, Rules: 2}, {From: 10, Rules: 1},
+		},
+	}
+
+	text := formatGroupStats(stats)
+	for _, want := range []string{
+		"11.2024 — 12.2024", "до 4000₽", "12.2024 — 1, 10.0%, до 3000₽ (+2000₽)",
+		"Иван — 3", "3–5% — 2", "от 10% — 1",
+		"Потолок кэшбэка: до 4000₽", "Заработано по покупкам: 250₽", "(+2000₽), заработано 250₽",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatGroupStats() не содержит %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "до 1% —") {
+		t.Errorf("Пустые диапазоны процентов не показываются:\n%s", text)
+	}
+}
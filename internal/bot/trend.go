@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// handleTrend обрабатывает команду /trend Банк Категория.
+func (b *Bot) handleTrend(message *tgbotapi.Message) {
+	userIDStr := strconv.FormatInt(message.From.ID, 10)
+	groupName, err := b.client.GetUserGroup(userIDStr)
+	if err != nil {
+		b.sendText(message.Chat.ID, "❌ Вы должны быть в группе. Используйте /creategroup или /joingroup")
+		return
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	if args == "" {
+		b.sendText(message.Chat.ID, "❌ Укажите банк и категорию. Например: /trend Тинькофф Такси")
+		return
+	}
+
+	// Банки из истории группы нужны, чтобы отделить название банка из нескольких слов от категории
+	history, err := b.client.GetGroupHistory(&models.HistoryRequest{GroupName: groupName})
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось получить историю кэшбэков: %v", err))
+		return
+	}
+	banks := make([]string, 0, len(history))
+	for _, rule := range history {
+		banks = append(banks, rule.BankName)
+	}
+
+	bankName, category := splitTrendArgs(args, banks)
+	if category == "" {
+		b.sendText(message.Chat.ID, "❌ Укажите категорию после банка. Например: /trend Тинькофф Такси")
+		return
+	}
+
+	trend, err := b.client.GetBankTrend(&models.HistoryRequest{
+		GroupName: groupName,
+		BankName:  bankName,
+		Category:  category,
+	})
+	if err != nil {
+		b.sendText(message.Chat.ID, fmt.Sprintf("❌ Не удалось получить динамику: %v", err))
+		return
+	}
+
+	b.sendText(message.Chat.ID, formatBankTrend(trend))
+}
+
+// splitTrendArgs делит аргументы /trend на банк и категорию. Через запятую
+// ("Альфа Банк, Такси") граница задана явно; иначе банком считается самое
+// длинное известное название в начале строки, а если такого нет — первое слово.
+func splitTrendArgs(args string, banks []string) (string, string) {
+	if bankName, category, ok := strings.Cut(args, ","); ok {
+		return strings.TrimSpace(bankName), strings.TrimSpace(category)
+	}
+
+	words := strings.Fields(args)
+	bankWords := 1
+	for _, bank := range banks {
+		n := len(strings.Fields(bank))
+		if n <= bankWords || n >= len(words) {
+			continue
+		}
+		if canonicalText(strings.Join(words[:n], " ")) == canonicalText(bank) {
+			bankWords = n
+		}
+	}
+
+	return strings.Join(words[:bankWords], " "), strings.Join(words[bankWords:], " ")
+}
+
+// canonicalText приводит текст к виду для сравнения без учёта регистра и "ё".
+func canonicalText(text string) string {
+	return strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+}
+
+// formatBankTrend форматирует динамику процента и лимита банка на категорию.
+func formatBankTrend(trend *models.BankTrend) string {
+	text := fmt.Sprintf("📈 %s — %s за %s — %s\n\n",
+		trend.BankName, trend.Category, trend.From.Format("01.2006"), trend.To.Format("01.2006"))
+
+	if trend.OfferedMonths == 0 {
+		return text + "📝 За этот период у группы не было такого кэшбэка."
+	}
+
+	started := false
+	for _, p := range trend.Points {
+		if p.Rules == 0 {
+			// Месяцы до первого предложения не показываем
+			if started {
+				text += fmt.Sprintf("• %s — не было\n", p.Month.Format("01.2006"))
+			}
+			continue
+		}
+
+		text += fmt.Sprintf("• %s — %.1f%% до %.0f₽", p.Month.Format("01.2006"), p.CashbackPercent, p.MaxAmount)
+		if started && (p.PercentChange != 0 || p.MaxAmountChange != 0) {
+			text += fmt.Sprintf(" (%s, %s)", formatSignedPercent(p.PercentChange), formatSignedRubles(p.MaxAmountChange))
+		}
+		text += "\n"
+		started = true
+	}
+
+	text += fmt.Sprintf("\nБыл в %d из %d мес. Процент: %s, лимит: %s\n",
+		trend.OfferedMonths, len(trend.Points),
+		formatSignedPercent(trend.PercentChange), formatSignedRubles(trend.MaxAmountChange))
+
+	switch {
+	case trend.PercentChange > 0 || (trend.PercentChange == 0 && trend.MaxAmountChange > 0):
+		text += "📈 Условия улучшаются — карту стоит оставить"
+	case trend.PercentChange < 0 || trend.MaxAmountChange < 0:
+		text += "📉 Условия ухудшаются — подумайте, нужна ли эта карта"
+	default:
+		text += "➡️ Условия не меняются"
+	}
+
+	return text
+}
+
+// formatSignedPercent форматирует изменение процента со знаком: "+1.5%", "−2.0%".
+func formatSignedPercent(value float64) string {
+	switch {
+	case value > 0:
+		return fmt.Sprintf("+%.1f%%", value)
+	case value < 0:
+		return fmt.Sprintf("−%.1f%%", -value)
+	default:
+		return "без изменений"
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestSplitTrendArgs(t *testing.T) {
+	banks := []string{"Тинькофф", "Альфа Банк"}
+
+	tests := []struct {
+		args, bank, category string
+	}{
+		{"Тинькофф Такси", "Тинькофф", "Такси"},
+		{"альфа банк Рестораны и кафе", "альфа банк", "Рестораны и кафе"},
+		{"Альфа Банк, Такси", "Альфа Банк", "Такси"},
+		{"Сбер Аптеки", "Сбер", "Аптеки"},
+		{"Тинькофф", "Тинькофф", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.args, func(t *testing.T) {
+			bank, category := splitTrendArgs(tt.args, banks)
+			if bank != tt.bank || category != tt.category {
+				t.Errorf("splitTrendArgs() = %q, %q; ожидалось %q, %q", bank, category, tt.bank, tt.category)
+			}
+		})
+	}
+}
+
+func TestFormatBankTrend(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	trend := &models.BankTrend{
+		BankName: "Тинькофф", Category: "Такси", From: month(time.January), To: month(time.April),
+		Points: []models.TrendPoint{
+			{Month: month(time.January)},
+			{Month: month(time.February), Rules: 1, CashbackPercent: 5, MaxAmount: 3000},
+			{Month: month(time.March)},
+			{Month: month(time.April), Rules: 1, CashbackPercent: 3, MaxAmount: 2000, PercentChange: -2, MaxAmountChange: -1000},
+		},
+		OfferedMonths: 2, PercentChange: -2, MaxAmountChange: -1000,
+	}
+
+	text := formatBankTrend(trend)
+	for _, want := range []string{
+		"Тинькофф — Такси за 01.2024 — 04.2024",
+		"02.2024 — 5.0% до 3000₽\n",
+		"03.2024 — не было",
+		"04.2024 — 3.0% до 2000₽ (−2.0%, −1000₽)",
+		"Был в 2 из 4 мес.",
+		"Условия ухудшаются",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatBankTrend() не содержит %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "01.2024 — не было") {
+		t.Errorf("formatBankTrend() показывает месяцы до первого предложения:\n%s", text)
+	}
+}
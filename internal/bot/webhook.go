@@ -0,0 +1,130 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// HeaderWebhookSecret — заголовок, в котором Telegram передаёт секрет webhook.
+const HeaderWebhookSecret = "X-Telegram-Bot-Api-Secret-Token"
+
+// StartWebhook регистрирует webhook в Telegram и принимает обновления по HTTP
+// до отмены ctx. Обновления обрабатываются тем же пулом воркеров, что и при
+// long polling. TLS должен терминироваться перед ботом.
+func (b *Bot) StartWebhook(ctx context.Context, cfg WebhookConfig) error {
+	listener, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("не удалось открыть %s: %w", cfg.Listen, err)
+	}
+	return b.serveWebhook(ctx, listener, cfg)
+}
+
+// serveWebhook принимает обновления на переданном листенере.
+// После отмены ctx перестаёт принимать запросы и дожидается завершения обработчиков.
+func (b *Bot) serveWebhook(ctx context.Context, listener net.Listener, cfg WebhookConfig) error {
+	if err := b.setWebhook(cfg); err != nil {
+		listener.Close()
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	updates := make(chan tgbotapi.Update, WorkerQueueSize)
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.Path(), webhookHandler(ctx, cfg.Secret, updates))
+
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: WebhookReadTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serveErr <- fmt.Errorf("ошибка HTTP сервера webhook: %w", err)
+			cancel()
+		}
+		close(serveErr)
+	}()
+
+	go b.cleanupStates(ctx)
+	go b.runDigests(ctx)
+	go b.runActivationReminders(ctx)
+
+	log.Printf("🤖 Бот принимает webhook на %s%s (воркеров: %d)...", listener.Addr(), cfg.Path(), b.workers)
+
+	b.serve(ctx, updates)
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), WebhookShutdownTimeout)
+	defer shutdownCancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("⚠️ Ошибка остановки HTTP сервера webhook: %v", err)
+	}
+
+	log.Println("✅ Все обработчики завершены, бот остановлен")
+
+	return <-serveErr
+}
+
+// setWebhook регистрирует URL и секрет webhook в Telegram.
+// Параметр secret_token передаётся напрямую, так как WebhookConfig
+// библиотеки его не поддерживает.
+func (b *Bot) setWebhook(cfg WebhookConfig) error {
+	params := tgbotapi.Params{}
+	params["url"] = cfg.URL
+	params["secret_token"] = cfg.Secret
+	if err := params.AddInterface("allowed_updates", []string{"message", "callback_query", "inline_query"}); err != nil {
+		return fmt.Errorf("не удалось сформировать параметры webhook: %w", err)
+	}
+
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("не удалось зарегистрировать webhook: %w", err)
+	}
+
+	log.Printf("✅ Webhook зарегистрирован: %s", cfg.URL)
+	return nil
+}
+
+// webhookHandler принимает обновления от Telegram и передаёт их в канал.
+// Запросы без корректного секрета отклоняются. После отмены ctx
+// отвечает 503, чтобы Telegram повторил доставку позже.
+func webhookHandler(ctx context.Context, secret string, updates chan<- tgbotapi.Update) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := r.Header.Get(HeaderWebhookSecret)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			log.Printf("⚠️ Webhook запрос с неверным секретом от %s", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var update tgbotapi.Update
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, WebhookMaxBodySize)).Decode(&update); err != nil {
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case updates <- update:
+			w.WriteHeader(http.StatusOK)
+		case <-ctx.Done():
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		case <-r.Context().Done():
+		}
+	})
+}
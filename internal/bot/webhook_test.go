@@ -0,0 +1,200 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// fakeTelegram имитирует Telegram Bot API и запоминает вызванные методы.
+type fakeTelegram struct {
+	mu       sync.Mutex
+	requests map[string][]url.Values
+	sent     chan url.Values
+}
+
+func newFakeTelegram(t *testing.T) (*fakeTelegram, *httptest.Server) {
+	fake := &fakeTelegram{
+		requests: make(map[string][]url.Values),
+		sent:     make(chan url.Values, 10),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm failed: %v", err)
+		}
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+		fake.mu.Lock()
+		fake.requests[method] = append(fake.requests[method], r.PostForm)
+		fake.mu.Unlock()
+
+		var result interface{} = true
+		switch method {
+		case "getMe":
+			result = tgbotapi.User{ID: 1, IsBot: true, UserName: "cashback_test_bot"}
+		case "sendMessage":
+			fake.sent <- r.PostForm
+			result = tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: 1}}
+		}
+
+		raw, _ := json.Marshal(result)
+		json.NewEncoder(w).Encode(tgbotapi.APIResponse{Ok: true, Result: raw})
+	}))
+
+	return fake, server
+}
+
+func (f *fakeTelegram) calls(method string) []url.Values {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[method]
+}
+
+func newTestBot(t *testing.T, endpoint string) *Bot {
+	cfg := &Config{
+		TelegramToken: "123:test",
+		TelegramAPI:   endpoint + "/bot%s/%s",
+		Workers:       2,
+	}
+	b, err := NewBot(cfg, NewAPIClient("http://127.0.0.1:0"), NewMemoryStateStore(time.Minute), nil)
+	if err != nil {
+		t.Fatalf("NewBot failed: %v", err)
+	}
+	return b
+}
+
+func postUpdate(t *testing.T, target, secret string, update tgbotapi.Update) int {
+	body, _ := json.Marshal(update)
+	req, _ := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	req.Header.Set(HeaderWebhookSecret, secret)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestWebhookHandlerRejectsInvalidRequests(t *testing.T) {
+	updates := make(chan tgbotapi.Update, 1)
+	server := httptest.NewServer(webhookHandler(context.Background(), "secret", updates))
+	defer server.Close()
+
+	if code := postUpdate(t, server.URL, "wrong", tgbotapi.Update{UpdateID: 1}); code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for wrong secret, got %d", code)
+	}
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Expected 405 for GET, got %d", resp.StatusCode)
+	}
+
+	if code := postUpdate(t, server.URL, "secret", tgbotapi.Update{UpdateID: 2}); code != http.StatusOK {
+		t.Errorf("Expected 200 for valid update, got %d", code)
+	}
+	if update := <-updates; update.UpdateID != 2 {
+		t.Errorf("Expected update 2, got %d", update.UpdateID)
+	}
+}
+
+func TestWebhookEndToEnd(t *testing.T) {
+	fake, telegram := newFakeTelegram(t)
+	defer telegram.Close()
+
+	b := newTestBot(t, telegram.URL)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	cfg := WebhookConfig{URL: "https://bot.example.com/telegram/webhook", Secret: "s3cret"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- b.serveWebhook(ctx, listener, cfg) }()
+
+	update := tgbotapi.Update{
+		UpdateID: 10,
+		Message: &tgbotapi.Message{
+			MessageID: 5,
+			From:      &tgbotapi.User{ID: 42, UserName: "alice"},
+			Chat:      &tgbotapi.Chat{ID: 42, Type: "private"},
+			Text:      "/help",
+			Entities:  []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: 5}},
+		},
+	}
+	target := "http://" + listener.Addr().String() + cfg.Path()
+
+	var code int
+	for i := 0; i < 50; i++ {
+		code = postUpdate(t, target, cfg.Secret, update)
+		if code == http.StatusOK {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if code != http.StatusOK {
+		t.Fatalf("Expected 200 from webhook, got %d", code)
+	}
+
+	select {
+	case sent := <-fake.sent:
+		if sent.Get("chat_id") != "42" {
+			t.Errorf("Expected reply to chat 42, got %s", sent.Get("chat_id"))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Bot did not reply to /help")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serveWebhook returned error: %v", err)
+	}
+
+	calls := fake.calls("setWebhook")
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 setWebhook call, got %d", len(calls))
+	}
+	if calls[0].Get("url") != cfg.URL || calls[0].Get("secret_token") != cfg.Secret {
+		t.Errorf("Unexpected setWebhook params: %v", calls[0])
+	}
+}
+
+func TestWebhookConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     WebhookConfig
+		wantErr bool
+	}{
+		{"valid", WebhookConfig{URL: "https://bot.example.com/hook", Listen: ":8443", Secret: "abc_DEF-1"}, false},
+		{"http url", WebhookConfig{URL: "http://bot.example.com/hook", Listen: ":8443", Secret: "abc"}, true},
+		{"empty secret", WebhookConfig{URL: "https://bot.example.com/hook", Listen: ":8443"}, true},
+		{"bad secret", WebhookConfig{URL: "https://bot.example.com/hook", Listen: ":8443", Secret: "a b"}, true},
+		{"empty listen", WebhookConfig{URL: "https://bot.example.com/hook", Secret: "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 )
 
 // Константы переменных окружения.
@@ -16,6 +17,7 @@ const (
 	EnvDBSSLMode  = "DB_SSLMODE"
 	EnvServerHost = "SERVER_HOST"
 	EnvServerPort = "SERVER_PORT"
+	EnvDigestTZ   = "DIGEST_TZ"
 )
 
 // Значения по умолчанию.
@@ -28,12 +30,14 @@ const (
 	DefaultDBSSLMode  = "disable"
 	DefaultServerHost = "0.0.0.0"
 	DefaultServerPort = "8080"
+	DefaultDigestTZ   = "Europe/Moscow"
 )
 
 // Config представляет конфигурацию приложения.
 type Config struct {
 	Database DatabaseConfig
 	Server   ServerConfig
+	Digest   DigestConfig
 }
 
 // DatabaseConfig содержит настройки базы данных.
@@ -52,6 +56,11 @@ type ServerConfig struct {
 	Port string
 }
 
+// DigestConfig содержит настройки ежемесячной сводки.
+type DigestConfig struct {
+	TimeZone string // часовой пояс расписания сводок, например Europe/Moscow
+}
+
 // ConnectionString возвращает строку подключения к PostgreSQL.
 func (c *DatabaseConfig) ConnectionString() string {
 	return fmt.Sprintf(
@@ -65,6 +74,15 @@ func (c *ServerConfig) Address() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
+// Location возвращает часовой пояс расписания сводок.
+func (c *DigestConfig) Location() (*time.Location, error) {
+	loc, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("часовой пояс %s: %w", c.TimeZone, err)
+	}
+	return loc, nil
+}
+
 // Load загружает конфигурацию из переменных окружения.
 func Load() *Config {
 	return &Config{
@@ -80,6 +98,9 @@ func Load() *Config {
 			Host: getEnv(EnvServerHost, DefaultServerHost),
 			Port: getEnv(EnvServerPort, DefaultServerPort),
 		},
+		Digest: DigestConfig{
+			TimeZone: getEnv(EnvDigestTZ, DefaultDigestTZ),
+		},
 	}
 }
 
@@ -91,6 +112,9 @@ func (c *Config) Validate() error {
 	if c.Database.DBName == "" {
 		return fmt.Errorf("DB_NAME не может быть пустым")
 	}
+	if _, err := c.Digest.Location(); err != nil {
+		return fmt.Errorf("DIGEST_TZ: %w", err)
+	}
 	return nil
 }
 
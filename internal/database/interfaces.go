@@ -17,14 +17,21 @@ var (
 
 // RepositoryInterface определяет контракт для репозитория.
 type RepositoryInterface interface {
+	// Транзакции
+	WithTx(ctx context.Context, fn func(tx RepositoryInterface) error) error
+
 	// Кэшбэк
 	Create(ctx context.Context, rule *models.CashbackRule) error
 	GetByID(ctx context.Context, id int64) (*models.CashbackRule, error)
 	Update(ctx context.Context, id int64, updates map[string]interface{}) error
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, limit, offset int, groupName string) ([]models.CashbackRule, int, error)
+	ListAllByGroup(ctx context.Context, groupName string) ([]models.CashbackRule, error)
 	GetBestCashback(ctx context.Context, groupName, category string, monthYear time.Time) (*models.CashbackRule, error)
 	GetAllCashbackByCategory(ctx context.Context, groupName, category string, monthYear time.Time) ([]models.CashbackRule, error)
+	ListUserCashback(ctx context.Context, userID string, since time.Time) ([]models.CashbackRule, error)
+	ListGroupHistory(ctx context.Context, groupName string, from, to time.Time, bankName, category string) ([]models.CashbackRule, error)
+	CreateMany(ctx context.Context, rules []*models.CashbackRule) error
 
 	// Fuzzy поиск
 	FuzzySearchGroupName(ctx context.Context, value string, threshold float64, limit int) ([]models.FuzzySuggestion, error)
@@ -40,6 +47,72 @@ type RepositoryInterface interface {
 	GetGroupMembers(ctx context.Context, groupName string) ([]string, error)
 	GetAllGroups(ctx context.Context) ([]string, error)
 
+	// Программы вознаграждения
+	ListRewardPrograms(ctx context.Context) ([]models.RewardProgram, error)
+	GetRewardProgram(ctx context.Context, code string) (*models.RewardProgram, error)
+	UpsertRewardProgram(ctx context.Context, program *models.RewardProgram) error
+
+	// Справочник магазинов
+	GetAllCashbackByMerchant(ctx context.Context, groupName, merchant string, monthYear time.Time) ([]models.CashbackRule, error)
+	ListMerchants(ctx context.Context) ([]models.Merchant, error)
+	FindMerchant(ctx context.Context, name string) (*models.Merchant, error)
+	UpsertMerchant(ctx context.Context, merchant *models.Merchant) error
+
+	// Аналитика
+	GetStatsByMember(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error)
+	GetStatsByBank(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error)
+	GetStatsByCategory(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error)
+	GetMonthlyStats(ctx context.Context, groupName string, from, to time.Time) ([]models.MonthStats, error)
+	GetPercentDistribution(ctx context.Context, groupName string, from, to time.Time, bounds []float64) ([]int, error)
+
+	// Меню категорий банков
+	UpsertOfferMenu(ctx context.Context, menu *models.OfferMenu) error
+	ListOfferMenus(ctx context.Context, monthYear time.Time) ([]models.OfferMenu, error)
+
+	// Дерево категорий
+	GetCategoryPath(ctx context.Context, category string) ([]string, error)
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	UpsertCategory(ctx context.Context, category *models.Category) error
+
+	// Карты
+	CreateCard(ctx context.Context, card *models.Card) error
+	GetCard(ctx context.Context, id int64) (*models.Card, error)
+	ListUserCards(ctx context.Context, userID string) ([]models.Card, error)
+	DeleteCard(ctx context.Context, id int64) error
+
+	// Групповые чаты
+	BindChat(ctx context.Context, chatID int64, groupName, userID string) (*models.ChatBinding, error)
+	GetChatBinding(ctx context.Context, chatID int64) (*models.ChatBinding, error)
+	UnbindChat(ctx context.Context, chatID int64) error
+	ListGroupChats(ctx context.Context, groupName string) ([]int64, error)
+
+	// Расписание сводок
+	GetDigestSettings(ctx context.Context, groupName string) (*models.DigestSettings, error)
+	ListDigestSettings(ctx context.Context) ([]models.DigestSettings, error)
+	UpsertDigestSettings(ctx context.Context, settings *models.DigestSettings) error
+	ClaimDigest(ctx context.Context, groupName string, month time.Time) (bool, error)
+
+	// Просьбы оплатить покупку
+	CreatePaymentRequest(ctx context.Context, req *models.PaymentRequest) error
+	GetPaymentRequest(ctx context.Context, id int64) (*models.PaymentRequest, error)
+	ResolvePaymentRequest(ctx context.Context, req *models.PaymentRequest, status string) (bool, error)
+	ListAcceptedPayments(ctx context.Context, groupName string) ([]models.PaymentRequest, error)
+	SettlePayments(ctx context.Context, groupName, userA, userB string, lastPaymentID int64) (int, error)
+
+	// Общие лимиты банков и журнал покупок
+	ListBankCaps(ctx context.Context, userID string) ([]models.BankCap, error)
+	GetBankCaps(ctx context.Context, userID, bankName string) ([]models.BankCap, error)
+	UpsertBankCap(ctx context.Context, bankCap *models.BankCap) error
+	DeleteBankCaps(ctx context.Context, userID, bankName string) error
+	CreateSpend(ctx context.Context, spend *models.Spend) error
+	SumBankCashback(ctx context.Context, userID, bankName string, from, to time.Time) (float64, error)
+	SumRuleCashback(ctx context.Context, ruleID int64) (float64, error)
+	ListEarnedCashback(ctx context.Context, groupName string, from, to time.Time) ([]models.EarnedCashback, error)
+
+	// Напоминания об активации категорий
+	ListDueActivations(ctx context.Context, from, to time.Time) ([]models.CashbackRule, error)
+	ClaimActivationReminder(ctx context.Context, ruleID int64) (bool, error)
+
 	// Дополнительные методы
 	GetCashbackByBank(ctx context.Context, groupName, bankName string, monthYear time.Time) ([]models.CashbackRule, error)
 	GetActiveCategories(ctx context.Context, groupName string, monthYear time.Time) ([]string, error)
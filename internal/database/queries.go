@@ -1,22 +1,47 @@
 // Package database содержит SQL запросы и работу с базой данных.
 package database
 
+// Фрагменты запросов к правилам кэшбэка.
+const (
+	// cashbackRuleColumns — колонки правила в порядке scanCashbackRule(s).
+	// Эффективный процент — процент в рублях с учётом курса программы.
+	cashbackRuleColumns = `cr.id, cr.group_name, cr.category, cr.bank_name, cr.user_id, cr.user_display_name,
+			   cr.month_year, cr.cashback_percent, cr.max_amount, cr.created_at, cr.updated_at,
+			   cr.reward_program, COALESCE(rp.name, cr.reward_program),
+			   ROUND(cr.cashback_percent * COALESCE(rp.ruble_rate, 1), 2), cr.conditions,
+			   COALESCE(cr.merchant, ''),
+			   CASE WHEN c.id IS NULL THEN NULL ELSE jsonb_build_object(
+				   'id', c.id, 'user_id', c.user_id, 'bank_name', c.bank_name, 'nickname', c.nickname,
+				   'last4', c.last4, 'payment_system', c.payment_system, 'created_at', c.created_at) END,
+			   cr.activation_required, cr.activation_deadline, cr.activated_at`
+
+	// cashbackRuleSource — правила вместе с программой вознаграждения и картой.
+	cashbackRuleSource = `cashback_rules cr
+		LEFT JOIN reward_programs rp ON rp.code = cr.reward_program
+		LEFT JOIN cards c ON c.id = cr.card_id`
+
+	// cashbackRuleEffectivePercent — выражение для сортировки по рублёвой выгоде.
+	cashbackRuleEffectivePercent = `cr.cashback_percent * COALESCE(rp.ruble_rate, 1)`
+)
+
 // SQL запросы для работы с кэшбэком.
 const (
 	// QueryCreateCashback — создание нового правила.
 	QueryCreateCashback = `
 		INSERT INTO cashback_rules (
 			group_name, category, bank_name, user_id, user_display_name,
-			month_year, cashback_percent, max_amount
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING id, created_at, updated_at`
+			month_year, cashback_percent, max_amount, reward_program, conditions, merchant, card_id,
+			activation_required, activation_deadline
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, ''), $12, $13, $14)
+		RETURNING id, created_at, updated_at,
+			COALESCE((SELECT name FROM reward_programs WHERE code = reward_program), reward_program),
+			ROUND(cashback_percent * COALESCE((SELECT ruble_rate FROM reward_programs WHERE code = reward_program), 1), 2)`
 
 	// QueryGetCashbackByID — получение правила по ID.
 	QueryGetCashbackByID = `
-		SELECT id, group_name, category, bank_name, user_id, user_display_name,
-			   month_year, cashback_percent, max_amount, created_at, updated_at
-		FROM cashback_rules
-		WHERE id = $1`
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		WHERE cr.id = $1`
 
 	// QueryDeleteCashback — удаление правила.
 	QueryDeleteCashback = `DELETE FROM cashback_rules WHERE id = $1`
@@ -30,32 +55,66 @@ const (
 
 	// QueryListCashbackByGroup — список правил группы.
 	QueryListCashbackByGroup = `
-		SELECT cr.id, cr.group_name, cr.category, cr.bank_name, cr.user_id, cr.user_display_name,
-			   cr.month_year, cr.cashback_percent, cr.max_amount, cr.created_at, cr.updated_at
-		FROM cashback_rules cr
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
 		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
 		WHERE ug.group_name = $1
 		ORDER BY cr.created_at DESC 
 		LIMIT $2 OFFSET $3`
 
-	// QueryGetBestCashback — получение лучшего кэшбэка.
+	// QueryListAllCashbackByGroup — все правила группы без пагинации.
+	QueryListAllCashbackByGroup = `
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
+		WHERE ug.group_name = $1
+		ORDER BY cr.month_year, cr.bank_name, cr.category`
+
+	// QueryListGroupHistory — правила группы с месяцем окончания в [$2, $3),
+	// включая закончившиеся; пустые $4 и $5 — любой банк и любая категория.
+	QueryListGroupHistory = `
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
+		WHERE ug.group_name = $1 AND cr.month_year >= $2 AND cr.month_year < $3
+		  AND ($4 = '' OR LOWER(cr.bank_name) = LOWER($4))
+		  AND ($5 = '' OR LOWER(cr.category) = LOWER($5))
+		ORDER BY cr.month_year DESC, ` + cashbackRuleEffectivePercent + ` DESC`
+
+	// QueryGetBestCashback — получение лучшего кэшбэка на категорию
+	// (предложения отдельных магазинов не учитываются).
 	QueryGetBestCashback = `
-		SELECT cr.id, cr.group_name, cr.category, cr.bank_name, cr.user_id, cr.user_display_name,
-			   cr.month_year, cr.cashback_percent, cr.max_amount, cr.created_at, cr.updated_at
-		FROM cashback_rules cr
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
 		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
 		WHERE ug.group_name = $1 AND cr.category = $2 AND cr.month_year >= $3
-		ORDER BY cr.cashback_percent DESC, cr.max_amount DESC
+		  AND cr.merchant IS NULL
+		ORDER BY ` + cashbackRuleEffectivePercent + ` DESC, cr.max_amount DESC
 		LIMIT 1`
 
-	// QueryGetAllCashbackByCategory — получение всех кэшбэков по категории.
+	// QueryGetAllCashbackByCategory — получение всех кэшбэков на категорию.
 	QueryGetAllCashbackByCategory = `
-		SELECT cr.id, cr.group_name, cr.category, cr.bank_name, cr.user_id, cr.user_display_name,
-			   cr.month_year, cr.cashback_percent, cr.max_amount, cr.created_at, cr.updated_at
-		FROM cashback_rules cr
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
 		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
 		WHERE ug.group_name = $1 AND cr.category = $2 AND cr.month_year >= $3
-		ORDER BY cr.cashback_percent DESC, cr.max_amount DESC`
+		  AND cr.merchant IS NULL
+		ORDER BY ` + cashbackRuleEffectivePercent + ` DESC, cr.max_amount DESC`
+
+	// QueryGetAllCashbackByMerchant — предложения магазина.
+	QueryGetAllCashbackByMerchant = `
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
+		WHERE ug.group_name = $1 AND cr.merchant = $2 AND cr.month_year >= $3
+		ORDER BY ` + cashbackRuleEffectivePercent + ` DESC, cr.max_amount DESC`
+
+	// QueryListUserCashback — все правила пользователя с указанного месяца.
+	QueryListUserCashback = `
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		WHERE cr.user_id = $1 AND cr.month_year >= $2
+		ORDER BY cr.month_year, cr.bank_name, cr.category`
 
 	// QueryFuzzySearch — fuzzy поиск по полю (шаблон).
 	QueryFuzzySearchTemplate = `
@@ -95,12 +154,11 @@ const (
 
 	// QueryGetCashbackByBank — получение кэшбэков по банку в группе.
 	QueryGetCashbackByBank = `
-		SELECT cr.id, cr.group_name, cr.category, cr.bank_name, cr.user_id, cr.user_display_name,
-			   cr.month_year, cr.cashback_percent, cr.max_amount, cr.created_at, cr.updated_at
-		FROM cashback_rules cr
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
 		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
 		WHERE ug.group_name = $1 AND cr.bank_name = $2 AND cr.month_year >= $3
-		ORDER BY cr.cashback_percent DESC, cr.max_amount DESC`
+		ORDER BY ` + cashbackRuleEffectivePercent + ` DESC, cr.max_amount DESC`
 
 	// QueryGetActiveCategories — получение уникальных активных категорий.
 	QueryGetActiveCategories = `
@@ -135,3 +193,340 @@ const (
 	FieldUserDisplayName = "user_display_name"
 )
 
+
+// SQL запросы для работы с программами вознаграждения.
+const (
+	// QueryListRewardPrograms — все программы вознаграждения.
+	QueryListRewardPrograms = `
+		SELECT code, name, kind, ruble_rate, updated_at
+		FROM reward_programs
+		ORDER BY kind, name`
+
+	// QueryGetRewardProgram — программа по коду.
+	QueryGetRewardProgram = `
+		SELECT code, name, kind, ruble_rate, updated_at
+		FROM reward_programs WHERE code = $1`
+
+	// QueryUpsertRewardProgram — создание программы или обновление курса.
+	QueryUpsertRewardProgram = `
+		INSERT INTO reward_programs (code, name, kind, ruble_rate)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (code)
+		DO UPDATE SET name = $2, kind = $3, ruble_rate = $4, updated_at = CURRENT_TIMESTAMP
+		RETURNING code, name, kind, ruble_rate, updated_at`
+)
+
+// SQL запросы для работы со справочником магазинов.
+const (
+	// QueryListMerchants — все магазины.
+	QueryListMerchants = `
+		SELECT name, category, aliases, created_at
+		FROM merchants
+		ORDER BY category, name`
+
+	// QueryFindMerchant — поиск магазина по названию или другому написанию без учёта регистра.
+	QueryFindMerchant = `
+		SELECT name, category, aliases, created_at
+		FROM merchants
+		WHERE LOWER(name) = LOWER($1)
+		   OR EXISTS (SELECT 1 FROM unnest(aliases) a WHERE LOWER(a) = LOWER($1))
+		LIMIT 1`
+
+	// QueryUpsertMerchant — добавление магазина или изменение его категории.
+	QueryUpsertMerchant = `
+		INSERT INTO merchants (name, category, aliases)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name)
+		DO UPDATE SET category = $2, aliases = $3
+		RETURNING name, category, aliases, created_at`
+)
+
+// SQL запросы для работы с деревом категорий.
+const (
+	// QueryGetCategoryPath — путь от категории (без учёта регистра) до корня дерева.
+	// Глубина ограничена, чтобы ошибка в данных не зациклила запрос.
+	QueryGetCategoryPath = `
+		WITH RECURSIVE path AS (
+			SELECT name, parent, 0 AS depth
+			FROM categories
+			WHERE LOWER(name) = LOWER($1)
+			UNION ALL
+			SELECT c.name, c.parent, p.depth + 1
+			FROM categories c
+			INNER JOIN path p ON c.name = p.parent
+			WHERE p.depth < 20
+		)
+		SELECT name FROM path ORDER BY depth`
+
+	// QueryListCategories — все категории дерева.
+	QueryListCategories = `
+		SELECT name, COALESCE(parent, ''), created_at
+		FROM categories
+		ORDER BY COALESCE(parent, ''), name`
+
+	// QueryUpsertCategory — добавление категории или перенос к другому родителю.
+	QueryUpsertCategory = `
+		INSERT INTO categories (name, parent)
+		VALUES ($1, NULLIF($2, ''))
+		ON CONFLICT (name)
+		DO UPDATE SET parent = NULLIF($2, '')
+		RETURNING name, COALESCE(parent, ''), created_at`
+)
+
+// SQL запросы для работы с картами.
+const (
+	// QueryCreateCard — добавление карты.
+	QueryCreateCard = `
+		INSERT INTO cards (user_id, bank_name, nickname, last4, payment_system)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`
+
+	// QueryGetCard — карта по ID.
+	QueryGetCard = `
+		SELECT id, user_id, bank_name, nickname, last4, payment_system, created_at
+		FROM cards
+		WHERE id = $1`
+
+	// QueryListUserCards — карты пользователя.
+	QueryListUserCards = `
+		SELECT id, user_id, bank_name, nickname, last4, payment_system, created_at
+		FROM cards
+		WHERE user_id = $1
+		ORDER BY bank_name, id`
+
+	// QueryDeleteCard — удаление карты; правила карты остаются без неё.
+	QueryDeleteCard = `DELETE FROM cards WHERE id = $1`
+)
+
+// SQL запросы для работы с меню категорий банков.
+const (
+	// QueryUpsertOfferMenu — сохранение меню банка на месяц.
+	QueryUpsertOfferMenu = `
+		INSERT INTO offer_menus (bank_name, month_year, pick_count, options)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bank_name, month_year)
+		DO UPDATE SET pick_count = $3, options = $4
+		RETURNING created_at, updated_at`
+
+	// QueryListOfferMenus — меню всех банков на месяц.
+	QueryListOfferMenus = `
+		SELECT bank_name, month_year, pick_count, options, created_at, updated_at
+		FROM offer_menus
+		WHERE month_year = $1
+		ORDER BY bank_name`
+)
+
+// Фрагменты запросов аналитики: правила группы с месяцем окончания в [$2, $3).
+const (
+	// statsAggregates — число правил, средний и максимальный эффективный
+	// процент и оценка кэшбэка в рублях (сумма лимитов по курсу программы).
+	statsAggregates = `COUNT(*),
+			   COALESCE(ROUND(AVG(` + cashbackRuleEffectivePercent + `)::numeric, 2), 0),
+			   COALESCE(ROUND(MAX(` + cashbackRuleEffectivePercent + `)::numeric, 2), 0),
+			   COALESCE(ROUND(SUM(cr.max_amount * COALESCE(rp.ruble_rate, 1))::numeric, 2), 0)`
+
+	// statsSource — правила группы за период.
+	statsSource = `cashback_rules cr
+		LEFT JOIN reward_programs rp ON rp.code = cr.reward_program
+		INNER JOIN user_groups ug ON cr.user_id = ug.user_id
+		WHERE ug.group_name = $1 AND cr.month_year >= $2 AND cr.month_year < $3`
+)
+
+// SQL запросы аналитики.
+const (
+	// QueryStatsByFieldTemplate — сводка по участникам, банкам или категориям
+	// (шаблон: выражения ключа и подписи из StatsKey*).
+	QueryStatsByFieldTemplate = `
+		SELECT %s, ` + statsAggregates + `
+		FROM ` + statsSource + `
+		GROUP BY 1
+		ORDER BY 6 DESC, 3 DESC, 1`
+
+	// QueryMonthlyStats — сводка по месяцам.
+	QueryMonthlyStats = `
+		SELECT date_trunc('month', cr.month_year)::date, ` + statsAggregates + `
+		FROM ` + statsSource + `
+		GROUP BY 1
+		ORDER BY 1`
+
+	// QueryPercentDistribution — число правил по диапазонам эффективного процента;
+	// границы диапазонов — $4.
+	QueryPercentDistribution = `
+		SELECT width_bucket((` + cashbackRuleEffectivePercent + `)::float8, $4::float8[]), COUNT(*)
+		FROM ` + statsSource + `
+		GROUP BY 1
+		ORDER BY 1`
+)
+
+// Выражения ключа и подписи для сводок аналитики.
+const (
+	StatsKeyMember   = "cr.user_id, MAX(cr.user_display_name)"
+	StatsKeyBank     = "cr.bank_name, ''"
+	StatsKeyCategory = "cr.category, ''"
+)
+
+// SQL запросы для работы с групповыми чатами.
+const (
+	// QueryBindChat — привязка чата к группе.
+	QueryBindChat = `
+		INSERT INTO chat_bindings (chat_id, group_name, bound_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id)
+		DO UPDATE SET group_name = $2, bound_by = $3, created_at = CURRENT_TIMESTAMP
+		RETURNING chat_id, group_name, bound_by, created_at`
+
+	// QueryGetChatBinding — получение привязки чата.
+	QueryGetChatBinding = `
+		SELECT chat_id, group_name, bound_by, created_at
+		FROM chat_bindings WHERE chat_id = $1`
+
+	// QueryUnbindChat — удаление привязки чата.
+	QueryUnbindChat = `DELETE FROM chat_bindings WHERE chat_id = $1`
+
+	// QueryListGroupChats — чаты, привязанные к группе.
+	QueryListGroupChats = `SELECT chat_id FROM chat_bindings WHERE group_name = $1 ORDER BY created_at`
+)
+
+// SQL запросы для работы с расписанием сводок.
+const (
+	// QueryGetDigestSettings — расписание сводки группы.
+	QueryGetDigestSettings = `
+		SELECT group_name, day, hour, minute, enabled, last_sent_month
+		FROM digest_settings WHERE group_name = $1`
+
+	// QueryListDigestSettings — расписания сводок всех групп.
+	QueryListDigestSettings = `
+		SELECT group_name, day, hour, minute, enabled, last_sent_month
+		FROM digest_settings`
+
+	// QueryUpsertDigestSettings — сохранение расписания сводки группы.
+	QueryUpsertDigestSettings = `
+		INSERT INTO digest_settings (group_name, day, hour, minute, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (group_name)
+		DO UPDATE SET day = $2, hour = $3, minute = $4, enabled = $5
+		RETURNING last_sent_month`
+
+	// QueryClaimDigest — отметка об отправке сводки за месяц; не обновляет
+	// строку, если сводка за этот месяц уже отправлена.
+	QueryClaimDigest = `
+		INSERT INTO digest_settings (group_name, last_sent_month)
+		VALUES ($1, $2)
+		ON CONFLICT (group_name)
+		DO UPDATE SET last_sent_month = $2
+		WHERE digest_settings.last_sent_month IS NULL OR digest_settings.last_sent_month < $2`
+)
+
+// SQL запросы для работы с просьбами оплатить покупку.
+const (
+	// paymentRequestColumns — колонки просьбы оплатить в порядке сканирования.
+	paymentRequestColumns = `id, group_name, rule_id, requester_id, requester_name, payer_id, payer_name,
+		bank_name, category, amount, status, created_at, resolved_at, settled_at`
+
+	// QueryCreatePaymentRequest — добавление просьбы оплатить.
+	QueryCreatePaymentRequest = `
+		INSERT INTO payment_requests (group_name, rule_id, requester_id, requester_name, payer_id, payer_name,
+			bank_name, category, amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, status, created_at`
+
+	// QueryGetPaymentRequest — просьба оплатить по ID.
+	QueryGetPaymentRequest = `SELECT ` + paymentRequestColumns + ` FROM payment_requests WHERE id = $1`
+
+	// QueryResolvePaymentRequest — ответ на просьбу; меняет только ожидающую ответа.
+	QueryResolvePaymentRequest = `
+		UPDATE payment_requests SET status = $2, resolved_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'pending'
+		RETURNING resolved_at`
+
+	// QueryListAcceptedPayments — оплаты группы, по которым деньги ещё не вернули.
+	QueryListAcceptedPayments = `
+		SELECT ` + paymentRequestColumns + `
+		FROM payment_requests
+		WHERE group_name = $1 AND status = 'accepted'
+		ORDER BY created_at`
+
+	// QuerySettlePayments — закрытие оплат между двумя участниками в обе стороны,
+	// не позже оплаты $4.
+	QuerySettlePayments = `
+		UPDATE payment_requests SET status = 'settled', settled_at = CURRENT_TIMESTAMP
+		WHERE group_name = $1 AND status = 'accepted' AND id <= $4
+			AND ((requester_id = $2 AND payer_id = $3) OR (requester_id = $3 AND payer_id = $2))`
+)
+
+// SQL запросы для работы с общими лимитами банков и журналом покупок.
+const (
+	// QueryListBankCaps — лимиты банков пользователя.
+	QueryListBankCaps = `
+		SELECT user_id, bank_name, period, amount, updated_at
+		FROM bank_caps WHERE user_id = $1
+		ORDER BY bank_name, period`
+
+	// QueryGetBankCaps — лимиты одного банка пользователя без учёта регистра.
+	QueryGetBankCaps = `
+		SELECT user_id, bank_name, period, amount, updated_at
+		FROM bank_caps WHERE user_id = $1 AND LOWER(bank_name) = LOWER($2)
+		ORDER BY period`
+
+	// QueryUpsertBankCap — установка лимита банка за период.
+	QueryUpsertBankCap = `
+		INSERT INTO bank_caps (user_id, bank_name, period, amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, bank_name, period)
+		DO UPDATE SET amount = $4
+		RETURNING updated_at`
+
+	// QueryDeleteBankCaps — удаление всех лимитов банка пользователя.
+	QueryDeleteBankCaps = `DELETE FROM bank_caps WHERE user_id = $1 AND LOWER(bank_name) = LOWER($2)`
+
+	// QueryCreateSpend — запись покупки в журнал.
+	QueryCreateSpend = `
+		INSERT INTO spends (group_name, user_id, bank_name, category, rule_id, payment_request_id,
+			amount, cashback, spent_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+
+	// QuerySumBankCashback — кэшбэк, заработанный по картам банка за период.
+	QuerySumBankCashback = `
+		SELECT COALESCE(SUM(cashback), 0)
+		FROM spends
+		WHERE user_id = $1 AND LOWER(bank_name) = LOWER($2) AND spent_at >= $3 AND spent_at < $4`
+
+	// QuerySumRuleCashback — кэшбэк, уже заработанный по правилу.
+	QuerySumRuleCashback = `SELECT COALESCE(SUM(cashback), 0) FROM spends WHERE rule_id = $1`
+
+	// QueryListEarnedCashback — кэшбэк участников группы по журналу трат
+	// за период, по месяцам, банкам и категориям; имя участника берётся
+	// из его последнего правила.
+	QueryListEarnedCashback = `
+		SELECT date_trunc('month', s.spent_at)::date, s.user_id,
+			   COALESCE((SELECT cr.user_display_name FROM cashback_rules cr
+						 WHERE cr.user_id = s.user_id ORDER BY cr.updated_at DESC LIMIT 1), s.user_id),
+			   s.bank_name, s.category, COUNT(*), COALESCE(SUM(s.cashback), 0)
+		FROM spends s
+		WHERE s.group_name = $1 AND s.spent_at >= $2 AND s.spent_at < $3
+		GROUP BY 1, 2, 4, 5
+		ORDER BY 1, 7 DESC, 2, 4, 5`
+)
+
+// SQL запросы для напоминаний об активации категорий.
+const (
+	// QueryListDueActivations — неактивированные правила, о которых ещё не
+	// напоминали: со сроком активации в интервале [$1, $2] или без срока,
+	// если месяц правила уже начался, ещё не закончился и правило создано
+	// не сегодня ($1).
+	QueryListDueActivations = `
+		SELECT ` + cashbackRuleColumns + `
+		FROM ` + cashbackRuleSource + `
+		WHERE cr.activation_required AND cr.activated_at IS NULL AND cr.activation_reminded_at IS NULL
+		  AND (cr.activation_deadline BETWEEN $1 AND $2
+		       OR (cr.activation_deadline IS NULL AND cr.month_year >= $1
+		           AND GREATEST(date_trunc('month', cr.month_year)::date, cr.created_at::date + 1) <= $1))
+		ORDER BY cr.activation_deadline, cr.id`
+
+	// QueryClaimActivationReminder — отметка о напоминании; не обновляет
+	// строку, если напоминание уже отправлено.
+	QueryClaimActivationReminder = `
+		UPDATE cashback_rules SET activation_reminded_at = NOW()
+		WHERE id = $1 AND activation_reminded_at IS NULL`
+)
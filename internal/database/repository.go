@@ -6,12 +6,22 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rymax1e/open-cashback-advisor/internal/models"
 )
 
+// DBTX — общие методы пула соединений и транзакции.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
 // Repository представляет репозиторий для работы с правилами кэшбэка.
+// Репозиторий, созданный через WithTx, выполняет запросы внутри транзакции.
 type Repository struct {
 	db *Database
+	tx pgx.Tx
 }
 
 // NewRepository создаёт новый репозиторий.
@@ -19,15 +29,54 @@ func NewRepository(db *Database) *Repository {
 	return &Repository{db: db}
 }
 
+// conn возвращает транзакцию, если репозиторий работает внутри неё, иначе пул.
+func (r *Repository) conn() DBTX {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.db.Pool
+}
+
+// WithTx выполняет fn в транзакции: все вызовы репозитория tx атомарны.
+// Если fn возвращает ошибку, транзакция откатывается.
+// Вызов WithTx внутри транзакции создаёт точку сохранения (SAVEPOINT),
+// и ошибка откатывает только изменения вложенного fn.
+func (r *Repository) WithTx(ctx context.Context, fn func(tx RepositoryInterface) error) error {
+	var (
+		tx  pgx.Tx
+		err error
+	)
+	if r.tx != nil {
+		tx, err = r.tx.Begin(ctx)
+	} else {
+		tx, err = r.db.Pool.Begin(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("начало транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&Repository{db: r.db, tx: tx}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("фиксация транзакции: %w", err)
+	}
+	return nil
+}
+
 // --- Методы для работы с кэшбэком ---
 
 // Create создаёт новое правило кэшбэка.
 func (r *Repository) Create(ctx context.Context, rule *models.CashbackRule) error {
-	err := r.db.Pool.QueryRow(
+	err := r.conn().QueryRow(
 		ctx, QueryCreateCashback,
 		rule.GroupName, rule.Category, rule.BankName, rule.UserID,
 		rule.UserDisplayName, rule.MonthYear, rule.CashbackPercent, rule.MaxAmount,
-	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+		rule.RewardProgram, rule.Conditions, rule.Merchant, cardID(rule),
+		rule.Activation.Required, rule.Activation.Deadline,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt, &rule.RewardProgramName, &rule.EffectivePercent)
 
 	if err != nil {
 		return fmt.Errorf("создание правила: %w", err)
@@ -55,7 +104,7 @@ func (r *Repository) Update(ctx context.Context, id int64, updates map[string]in
 
 	query, args := r.buildUpdateQuery(id, updates)
 
-	result, err := r.db.Pool.Exec(ctx, query, args...)
+	result, err := r.conn().Exec(ctx, query, args...)
 	if err != nil {
 		return fmt.Errorf("обновление правила %d: %w", id, err)
 	}
@@ -69,7 +118,7 @@ func (r *Repository) Update(ctx context.Context, id int64, updates map[string]in
 
 // Delete удаляет правило кэшбэка.
 func (r *Repository) Delete(ctx context.Context, id int64) error {
-	result, err := r.db.Pool.Exec(ctx, QueryDeleteCashback, id)
+	result, err := r.conn().Exec(ctx, QueryDeleteCashback, id)
 	if err != nil {
 		return fmt.Errorf("удаление правила %d: %w", id, err)
 	}
@@ -85,13 +134,13 @@ func (r *Repository) Delete(ctx context.Context, id int64) error {
 func (r *Repository) List(ctx context.Context, limit, offset int, groupName string) ([]models.CashbackRule, int, error) {
 	// Получаем общее количество
 	var total int
-	err := r.db.Pool.QueryRow(ctx, QueryCountCashbackByGroup, groupName).Scan(&total)
+	err := r.conn().QueryRow(ctx, QueryCountCashbackByGroup, groupName).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("подсчёт правил: %w", err)
 	}
 
 	// Получаем правила
-	rows, err := r.db.Pool.Query(ctx, QueryListCashbackByGroup, groupName, limit, offset)
+	rows, err := r.conn().Query(ctx, QueryListCashbackByGroup, groupName, limit, offset)
 	if err != nil {
 		return nil, 0, fmt.Errorf("получение списка правил: %w", err)
 	}
@@ -119,7 +168,7 @@ func (r *Repository) GetBestCashback(ctx context.Context, groupName, category st
 
 // GetAllCashbackByCategory получает все правила по категории.
 func (r *Repository) GetAllCashbackByCategory(ctx context.Context, groupName, category string, monthYear time.Time) ([]models.CashbackRule, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetAllCashbackByCategory, groupName, category, monthYear)
+	rows, err := r.conn().Query(ctx, QueryGetAllCashbackByCategory, groupName, category, monthYear)
 	if err != nil {
 		return nil, fmt.Errorf("получение кэшбэков по категории: %w", err)
 	}
@@ -137,6 +186,53 @@ func (r *Repository) GetAllCashbackByCategory(ctx context.Context, groupName, ca
 	return rules, nil
 }
 
+// ListAllByGroup получает все правила группы без пагинации.
+func (r *Repository) ListAllByGroup(ctx context.Context, groupName string) ([]models.CashbackRule, error) {
+	rows, err := r.conn().Query(ctx, QueryListAllCashbackByGroup, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("получение правил группы %s: %w", groupName, err)
+	}
+	defer rows.Close()
+
+	return r.scanCashbackRules(rows)
+}
+
+// ListUserCashback получает все правила пользователя, действующие с указанного месяца.
+func (r *Repository) ListUserCashback(ctx context.Context, userID string, since time.Time) ([]models.CashbackRule, error) {
+	rows, err := r.conn().Query(ctx, QueryListUserCashback, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("получение правил пользователя %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	return r.scanCashbackRules(rows)
+}
+
+// ListGroupHistory получает правила группы, которые заканчиваются в [from, to),
+// включая закончившиеся. Пустые bankName и category не ограничивают выборку.
+func (r *Repository) ListGroupHistory(ctx context.Context, groupName string, from, to time.Time, bankName, category string) ([]models.CashbackRule, error) {
+	rows, err := r.conn().Query(ctx, QueryListGroupHistory, groupName, from, to, bankName, category)
+	if err != nil {
+		return nil, fmt.Errorf("получение истории правил группы %s: %w", groupName, err)
+	}
+	defer rows.Close()
+
+	return r.scanCashbackRules(rows)
+}
+
+// CreateMany создаёт несколько правил в одной транзакции.
+// Если хотя бы одно правило не создано, не создаётся ни одно.
+func (r *Repository) CreateMany(ctx context.Context, rules []*models.CashbackRule) error {
+	return r.WithTx(ctx, func(tx RepositoryInterface) error {
+		for i, rule := range rules {
+			if err := tx.Create(ctx, rule); err != nil {
+				return fmt.Errorf("правило %d: %w", i+1, err)
+			}
+		}
+		return nil
+	})
+}
+
 // --- Методы для fuzzy поиска ---
 
 // FuzzySearchGroupName выполняет fuzzy-поиск по названию группы.
@@ -163,7 +259,7 @@ func (r *Repository) FuzzySearchUserDisplayName(ctx context.Context, value strin
 func (r *Repository) fuzzySearch(ctx context.Context, field, value string, threshold float64, limit int) ([]models.FuzzySuggestion, error) {
 	query := fmt.Sprintf(QueryFuzzySearchTemplate, field, field, field)
 
-	rows, err := r.db.Pool.Query(ctx, query, value, threshold, limit)
+	rows, err := r.conn().Query(ctx, query, value, threshold, limit)
 	if err != nil {
 		return nil, fmt.Errorf("fuzzy-поиск по %s: %w", field, err)
 	}
@@ -189,7 +285,7 @@ func (r *Repository) fuzzySearch(ctx context.Context, field, value string, thres
 
 // SetUserGroup устанавливает группу пользователя.
 func (r *Repository) SetUserGroup(ctx context.Context, userID, groupName string) error {
-	_, err := r.db.Pool.Exec(ctx, QuerySetUserGroup, userID, groupName)
+	_, err := r.conn().Exec(ctx, QuerySetUserGroup, userID, groupName)
 	if err != nil {
 		return fmt.Errorf("установка группы пользователя: %w", err)
 	}
@@ -199,7 +295,7 @@ func (r *Repository) SetUserGroup(ctx context.Context, userID, groupName string)
 // GetUserGroup получает группу пользователя.
 func (r *Repository) GetUserGroup(ctx context.Context, userID string) (string, error) {
 	var groupName string
-	err := r.db.Pool.QueryRow(ctx, QueryGetUserGroup, userID).Scan(&groupName)
+	err := r.conn().QueryRow(ctx, QueryGetUserGroup, userID).Scan(&groupName)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return "", fmt.Errorf("пользователь %s: %w", userID, ErrNotFound)
@@ -211,7 +307,7 @@ func (r *Repository) GetUserGroup(ctx context.Context, userID string) (string, e
 
 // CreateGroup создаёт новую группу.
 func (r *Repository) CreateGroup(ctx context.Context, groupName, creatorID string) error {
-	_, err := r.db.Pool.Exec(ctx, QueryCreateGroup, groupName, creatorID)
+	_, err := r.conn().Exec(ctx, QueryCreateGroup, groupName, creatorID)
 	if err != nil {
 		return fmt.Errorf("создание группы: %w", err)
 	}
@@ -223,7 +319,7 @@ func (r *Repository) CreateGroup(ctx context.Context, groupName, creatorID strin
 // GroupExists проверяет существование группы.
 func (r *Repository) GroupExists(ctx context.Context, groupName string) (bool, error) {
 	var exists bool
-	err := r.db.Pool.QueryRow(ctx, QueryGroupExists, groupName).Scan(&exists)
+	err := r.conn().QueryRow(ctx, QueryGroupExists, groupName).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("проверка существования группы: %w", err)
 	}
@@ -232,7 +328,7 @@ func (r *Repository) GroupExists(ctx context.Context, groupName string) (bool, e
 
 // GetGroupMembers возвращает участников группы.
 func (r *Repository) GetGroupMembers(ctx context.Context, groupName string) ([]string, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetGroupMembers, groupName)
+	rows, err := r.conn().Query(ctx, QueryGetGroupMembers, groupName)
 	if err != nil {
 		return nil, fmt.Errorf("получение участников группы: %w", err)
 	}
@@ -252,7 +348,7 @@ func (r *Repository) GetGroupMembers(ctx context.Context, groupName string) ([]s
 
 // GetAllGroups возвращает список всех групп.
 func (r *Repository) GetAllGroups(ctx context.Context) ([]string, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetAllGroups)
+	rows, err := r.conn().Query(ctx, QueryGetAllGroups)
 	if err != nil {
 		return nil, fmt.Errorf("получение списка групп: %w", err)
 	}
@@ -272,7 +368,7 @@ func (r *Repository) GetAllGroups(ctx context.Context) ([]string, error) {
 
 // GetCashbackByBank получает все кэшбэки по банку в группе.
 func (r *Repository) GetCashbackByBank(ctx context.Context, groupName, bankName string, monthYear time.Time) ([]models.CashbackRule, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetCashbackByBank, groupName, bankName, monthYear)
+	rows, err := r.conn().Query(ctx, QueryGetCashbackByBank, groupName, bankName, monthYear)
 	if err != nil {
 		return nil, fmt.Errorf("получение кэшбэков по банку: %w", err)
 	}
@@ -292,7 +388,7 @@ func (r *Repository) GetCashbackByBank(ctx context.Context, groupName, bankName
 
 // GetActiveCategories возвращает список активных категорий в группе.
 func (r *Repository) GetActiveCategories(ctx context.Context, groupName string, monthYear time.Time) ([]string, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetActiveCategories, groupName, monthYear)
+	rows, err := r.conn().Query(ctx, QueryGetActiveCategories, groupName, monthYear)
 	if err != nil {
 		return nil, fmt.Errorf("получение активных категорий: %w", err)
 	}
@@ -312,7 +408,7 @@ func (r *Repository) GetActiveCategories(ctx context.Context, groupName string,
 
 // GetActiveBanks возвращает список активных банков в группе.
 func (r *Repository) GetActiveBanks(ctx context.Context, groupName string, monthYear time.Time) ([]string, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetActiveBanks, groupName, monthYear)
+	rows, err := r.conn().Query(ctx, QueryGetActiveBanks, groupName, monthYear)
 	if err != nil {
 		return nil, fmt.Errorf("получение активных банков: %w", err)
 	}
@@ -332,7 +428,7 @@ func (r *Repository) GetActiveBanks(ctx context.Context, groupName string, month
 
 // GetGroupUsers возвращает список пользователей группы.
 func (r *Repository) GetGroupUsers(ctx context.Context, groupName string) ([]models.UserInfo, error) {
-	rows, err := r.db.Pool.Query(ctx, QueryGetGroupUsers, groupName)
+	rows, err := r.conn().Query(ctx, QueryGetGroupUsers, groupName)
 	if err != nil {
 		return nil, fmt.Errorf("получение пользователей группы: %w", err)
 	}
@@ -355,10 +451,13 @@ func (r *Repository) GetGroupUsers(ctx context.Context, groupName string) ([]mod
 // scanCashbackRule сканирует одно правило из запроса.
 func (r *Repository) scanCashbackRule(ctx context.Context, query string, args ...interface{}) (*models.CashbackRule, error) {
 	var rule models.CashbackRule
-	err := r.db.Pool.QueryRow(ctx, query, args...).Scan(
+	err := r.conn().QueryRow(ctx, query, args...).Scan(
 		&rule.ID, &rule.GroupName, &rule.Category, &rule.BankName,
 		&rule.UserID, &rule.UserDisplayName, &rule.MonthYear,
 		&rule.CashbackPercent, &rule.MaxAmount, &rule.CreatedAt, &rule.UpdatedAt,
+		&rule.RewardProgram, &rule.RewardProgramName, &rule.EffectivePercent, &rule.Conditions,
+		&rule.Merchant, &rule.Card,
+		&rule.Activation.Required, &rule.Activation.Deadline, &rule.Activation.ActivatedAt,
 	)
 	if err != nil {
 		return nil, err
@@ -375,6 +474,9 @@ func (r *Repository) scanCashbackRules(rows pgx.Rows) ([]models.CashbackRule, er
 			&rule.ID, &rule.GroupName, &rule.Category, &rule.BankName,
 			&rule.UserID, &rule.UserDisplayName, &rule.MonthYear,
 			&rule.CashbackPercent, &rule.MaxAmount, &rule.CreatedAt, &rule.UpdatedAt,
+			&rule.RewardProgram, &rule.RewardProgramName, &rule.EffectivePercent, &rule.Conditions,
+			&rule.Merchant, &rule.Card,
+			&rule.Activation.Required, &rule.Activation.Deadline, &rule.Activation.ActivatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("чтение правила: %w", err)
@@ -389,6 +491,719 @@ func (r *Repository) scanCashbackRules(rows pgx.Rows) ([]models.CashbackRule, er
 	return rules, nil
 }
 
+// --- Программы вознаграждения ---
+
+// ListRewardPrograms возвращает все программы вознаграждения.
+func (r *Repository) ListRewardPrograms(ctx context.Context) ([]models.RewardProgram, error) {
+	rows, err := r.conn().Query(ctx, QueryListRewardPrograms)
+	if err != nil {
+		return nil, fmt.Errorf("получение программ вознаграждения: %w", err)
+	}
+	defer rows.Close()
+
+	var programs []models.RewardProgram
+	for rows.Next() {
+		var p models.RewardProgram
+		if err := rows.Scan(&p.Code, &p.Name, &p.Kind, &p.RubleRate, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("чтение программы вознаграждения: %w", err)
+		}
+		programs = append(programs, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return programs, nil
+}
+
+// GetRewardProgram возвращает программу вознаграждения по коду.
+func (r *Repository) GetRewardProgram(ctx context.Context, code string) (*models.RewardProgram, error) {
+	var p models.RewardProgram
+	err := r.conn().QueryRow(ctx, QueryGetRewardProgram, code).Scan(
+		&p.Code, &p.Name, &p.Kind, &p.RubleRate, &p.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("программа \"%s\": %w", code, ErrNotFound)
+		}
+		return nil, fmt.Errorf("получение программы вознаграждения: %w", err)
+	}
+	return &p, nil
+}
+
+// UpsertRewardProgram создаёт программу вознаграждения или обновляет её курс.
+func (r *Repository) UpsertRewardProgram(ctx context.Context, program *models.RewardProgram) error {
+	err := r.conn().QueryRow(
+		ctx, QueryUpsertRewardProgram,
+		program.Code, program.Name, program.Kind, program.RubleRate,
+	).Scan(&program.Code, &program.Name, &program.Kind, &program.RubleRate, &program.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("сохранение программы вознаграждения: %w", err)
+	}
+	return nil
+}
+
+// --- Справочник магазинов ---
+
+// GetAllCashbackByMerchant возвращает предложения магазина в группе.
+func (r *Repository) GetAllCashbackByMerchant(ctx context.Context, groupName, merchant string, monthYear time.Time) ([]models.CashbackRule, error) {
+	rows, err := r.conn().Query(ctx, QueryGetAllCashbackByMerchant, groupName, merchant, monthYear)
+	if err != nil {
+		return nil, fmt.Errorf("получение предложений магазина: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanCashbackRules(rows)
+}
+
+// ListMerchants возвращает справочник магазинов.
+func (r *Repository) ListMerchants(ctx context.Context) ([]models.Merchant, error) {
+	rows, err := r.conn().Query(ctx, QueryListMerchants)
+	if err != nil {
+		return nil, fmt.Errorf("получение магазинов: %w", err)
+	}
+	defer rows.Close()
+
+	var merchants []models.Merchant
+	for rows.Next() {
+		var m models.Merchant
+		if err := rows.Scan(&m.Name, &m.Category, &m.Aliases, &m.CreatedAt); err != nil {
+			return nil, fmt.Errorf("чтение магазина: %w", err)
+		}
+		merchants = append(merchants, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return merchants, nil
+}
+
+// FindMerchant ищет магазин по названию или другому написанию.
+func (r *Repository) FindMerchant(ctx context.Context, name string) (*models.Merchant, error) {
+	var m models.Merchant
+	err := r.conn().QueryRow(ctx, QueryFindMerchant, name).Scan(&m.Name, &m.Category, &m.Aliases, &m.CreatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("магазин \"%s\": %w", name, ErrNotFound)
+		}
+		return nil, fmt.Errorf("поиск магазина: %w", err)
+	}
+	return &m, nil
+}
+
+// UpsertMerchant добавляет магазин или меняет его категорию.
+func (r *Repository) UpsertMerchant(ctx context.Context, merchant *models.Merchant) error {
+	err := r.conn().QueryRow(
+		ctx, QueryUpsertMerchant,
+		merchant.Name, merchant.Category, merchant.Aliases,
+	).Scan(&merchant.Name, &merchant.Category, &merchant.Aliases, &merchant.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("сохранение магазина: %w", err)
+	}
+	return nil
+}
+
+// --- Аналитика ---
+
+// GetStatsByMember возвращает сводку по участникам группы за период [from, to).
+func (r *Repository) GetStatsByMember(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return r.statsBy(ctx, StatsKeyMember, groupName, from, to)
+}
+
+// GetStatsByBank возвращает сводку по банкам группы за период [from, to).
+func (r *Repository) GetStatsByBank(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return r.statsBy(ctx, StatsKeyBank, groupName, from, to)
+}
+
+// GetStatsByCategory возвращает сводку по категориям группы за период [from, to).
+func (r *Repository) GetStatsByCategory(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return r.statsBy(ctx, StatsKeyCategory, groupName, from, to)
+}
+
+// statsBy возвращает сводку правил группы, сгруппированных по ключу.
+func (r *Repository) statsBy(ctx context.Context, key, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	rows, err := r.conn().Query(ctx, fmt.Sprintf(QueryStatsByFieldTemplate, key), groupName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("получение сводки: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []models.StatsBucket
+	for rows.Next() {
+		var b models.StatsBucket
+		if err := rows.Scan(&b.Key, &b.Label, &b.Rules, &b.AvgPercent, &b.MaxPercent, &b.EstimatedCashback); err != nil {
+			return nil, fmt.Errorf("чтение сводки: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// GetMonthlyStats возвращает сводку по месяцам за период [from, to).
+// Месяцы без правил не возвращаются.
+func (r *Repository) GetMonthlyStats(ctx context.Context, groupName string, from, to time.Time) ([]models.MonthStats, error) {
+	rows, err := r.conn().Query(ctx, QueryMonthlyStats, groupName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("получение сводки по месяцам: %w", err)
+	}
+	defer rows.Close()
+
+	var months []models.MonthStats
+	for rows.Next() {
+		var m models.MonthStats
+		if err := rows.Scan(&m.Month, &m.Rules, &m.AvgPercent, &m.MaxPercent, &m.EstimatedCashback); err != nil {
+			return nil, fmt.Errorf("чтение сводки по месяцам: %w", err)
+		}
+		months = append(months, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return months, nil
+}
+
+// GetPercentDistribution возвращает число правил по диапазонам эффективного
+// процента за период [from, to): индекс i — правила между bounds[i-1] и bounds[i].
+func (r *Repository) GetPercentDistribution(ctx context.Context, groupName string, from, to time.Time, bounds []float64) ([]int, error) {
+	rows, err := r.conn().Query(ctx, QueryPercentDistribution, groupName, from, to, bounds)
+	if err != nil {
+		return nil, fmt.Errorf("получение распределения процентов: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]int, len(bounds)+1)
+	for rows.Next() {
+		var bucket, count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("чтение распределения процентов: %w", err)
+		}
+		if bucket >= 0 && bucket < len(counts) {
+			counts[bucket] = count
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return counts, nil
+}
+
+// --- Меню категорий банков ---
+
+// UpsertOfferMenu сохраняет меню банка на месяц, заменяя прежнее.
+func (r *Repository) UpsertOfferMenu(ctx context.Context, menu *models.OfferMenu) error {
+	err := r.conn().QueryRow(
+		ctx, QueryUpsertOfferMenu,
+		menu.BankName, menu.MonthYear, menu.PickCount, menu.Options,
+	).Scan(&menu.CreatedAt, &menu.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("сохранение меню категорий: %w", err)
+	}
+	return nil
+}
+
+// ListOfferMenus возвращает меню всех банков на месяц.
+func (r *Repository) ListOfferMenus(ctx context.Context, monthYear time.Time) ([]models.OfferMenu, error) {
+	rows, err := r.conn().Query(ctx, QueryListOfferMenus, monthYear)
+	if err != nil {
+		return nil, fmt.Errorf("получение меню категорий: %w", err)
+	}
+	defer rows.Close()
+
+	var menus []models.OfferMenu
+	for rows.Next() {
+		var m models.OfferMenu
+		if err := rows.Scan(&m.BankName, &m.MonthYear, &m.PickCount, &m.Options, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("чтение меню категорий: %w", err)
+		}
+		menus = append(menus, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return menus, nil
+}
+
+// --- Дерево категорий ---
+
+// GetCategoryPath возвращает путь от категории до корня дерева.
+// Для категории, которой нет в дереве, возвращает пустой список.
+func (r *Repository) GetCategoryPath(ctx context.Context, category string) ([]string, error) {
+	rows, err := r.conn().Query(ctx, QueryGetCategoryPath, category)
+	if err != nil {
+		return nil, fmt.Errorf("получение пути категории: %w", err)
+	}
+	defer rows.Close()
+
+	var path []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("чтение категории: %w", err)
+		}
+		path = append(path, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return path, nil
+}
+
+// ListCategories возвращает все категории дерева.
+func (r *Repository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	rows, err := r.conn().Query(ctx, QueryListCategories)
+	if err != nil {
+		return nil, fmt.Errorf("получение категорий: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.Name, &c.Parent, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("чтение категории: %w", err)
+		}
+		categories = append(categories, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return categories, nil
+}
+
+// UpsertCategory добавляет категорию или переносит её к другому родителю.
+func (r *Repository) UpsertCategory(ctx context.Context, category *models.Category) error {
+	err := r.conn().QueryRow(ctx, QueryUpsertCategory, category.Name, category.Parent).Scan(
+		&category.Name, &category.Parent, &category.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("сохранение категории: %w", err)
+	}
+	return nil
+}
+
+// --- Карты ---
+
+// CreateCard добавляет карту пользователя.
+func (r *Repository) CreateCard(ctx context.Context, card *models.Card) error {
+	err := r.conn().QueryRow(
+		ctx, QueryCreateCard,
+		card.UserID, card.BankName, card.Nickname, card.Last4, card.PaymentSystem,
+	).Scan(&card.ID, &card.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("добавление карты: %w", err)
+	}
+	return nil
+}
+
+// GetCard получает карту по ID.
+func (r *Repository) GetCard(ctx context.Context, id int64) (*models.Card, error) {
+	var c models.Card
+	err := r.conn().QueryRow(ctx, QueryGetCard, id).Scan(
+		&c.ID, &c.UserID, &c.BankName, &c.Nickname, &c.Last4, &c.PaymentSystem, &c.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("карта с ID %d: %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("получение карты %d: %w", id, err)
+	}
+	return &c, nil
+}
+
+// ListUserCards возвращает карты пользователя.
+func (r *Repository) ListUserCards(ctx context.Context, userID string) ([]models.Card, error) {
+	rows, err := r.conn().Query(ctx, QueryListUserCards, userID)
+	if err != nil {
+		return nil, fmt.Errorf("получение карт: %w", err)
+	}
+	defer rows.Close()
+
+	var cards []models.Card
+	for rows.Next() {
+		var c models.Card
+		if err := rows.Scan(&c.ID, &c.UserID, &c.BankName, &c.Nickname, &c.Last4, &c.PaymentSystem, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("чтение карты: %w", err)
+		}
+		cards = append(cards, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return cards, nil
+}
+
+// DeleteCard удаляет карту. Правила карты остаются без привязки.
+func (r *Repository) DeleteCard(ctx context.Context, id int64) error {
+	result, err := r.conn().Exec(ctx, QueryDeleteCard, id)
+	if err != nil {
+		return fmt.Errorf("удаление карты %d: %w", id, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("карта с ID %d: %w", id, ErrNotFound)
+	}
+	return nil
+}
+
+// cardID возвращает ID карты правила или nil, если карта не указана.
+func cardID(rule *models.CashbackRule) *int64 {
+	if rule.Card == nil {
+		return nil
+	}
+	return &rule.Card.ID
+}
+
+// --- Групповые чаты ---
+
+// BindChat привязывает групповой чат к группе.
+func (r *Repository) BindChat(ctx context.Context, chatID int64, groupName, userID string) (*models.ChatBinding, error) {
+	var binding models.ChatBinding
+	err := r.conn().QueryRow(ctx, QueryBindChat, chatID, groupName, userID).Scan(
+		&binding.ChatID, &binding.GroupName, &binding.BoundBy, &binding.CreatedAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("привязка чата: %w", err)
+	}
+	return &binding, nil
+}
+
+// GetChatBinding возвращает привязку чата к группе.
+func (r *Repository) GetChatBinding(ctx context.Context, chatID int64) (*models.ChatBinding, error) {
+	var binding models.ChatBinding
+	err := r.conn().QueryRow(ctx, QueryGetChatBinding, chatID).Scan(
+		&binding.ChatID, &binding.GroupName, &binding.BoundBy, &binding.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("чат %d: %w", chatID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("получение привязки чата: %w", err)
+	}
+	return &binding, nil
+}
+
+// UnbindChat удаляет привязку чата к группе.
+func (r *Repository) UnbindChat(ctx context.Context, chatID int64) error {
+	result, err := r.conn().Exec(ctx, QueryUnbindChat, chatID)
+	if err != nil {
+		return fmt.Errorf("удаление привязки чата: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("чат %d: %w", chatID, ErrNotFound)
+	}
+	return nil
+}
+
+// ListGroupChats возвращает ID чатов, привязанных к группе.
+func (r *Repository) ListGroupChats(ctx context.Context, groupName string) ([]int64, error) {
+	rows, err := r.conn().Query(ctx, QueryListGroupChats, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("получение чатов группы: %w", err)
+	}
+	defer rows.Close()
+
+	var chats []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("чтение чата: %w", err)
+		}
+		chats = append(chats, chatID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return chats, nil
+}
+
+// --- Расписание сводок ---
+
+// GetDigestSettings возвращает расписание сводки группы.
+func (r *Repository) GetDigestSettings(ctx context.Context, groupName string) (*models.DigestSettings, error) {
+	var s models.DigestSettings
+	err := r.conn().QueryRow(ctx, QueryGetDigestSettings, groupName).Scan(
+		&s.GroupName, &s.Day, &s.Hour, &s.Minute, &s.Enabled, &s.LastSentMonth,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("расписание сводки группы %s: %w", groupName, ErrNotFound)
+		}
+		return nil, fmt.Errorf("получение расписания сводки: %w", err)
+	}
+	return &s, nil
+}
+
+// ListDigestSettings возвращает расписания сводок всех групп, у которых они заданы.
+func (r *Repository) ListDigestSettings(ctx context.Context) ([]models.DigestSettings, error) {
+	rows, err := r.conn().Query(ctx, QueryListDigestSettings)
+	if err != nil {
+		return nil, fmt.Errorf("получение расписаний сводок: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.DigestSettings
+	for rows.Next() {
+		var s models.DigestSettings
+		if err := rows.Scan(&s.GroupName, &s.Day, &s.Hour, &s.Minute, &s.Enabled, &s.LastSentMonth); err != nil {
+			return nil, fmt.Errorf("чтение расписания сводки: %w", err)
+		}
+		list = append(list, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return list, nil
+}
+
+// UpsertDigestSettings сохраняет расписание сводки группы.
+func (r *Repository) UpsertDigestSettings(ctx context.Context, settings *models.DigestSettings) error {
+	err := r.conn().QueryRow(
+		ctx, QueryUpsertDigestSettings,
+		settings.GroupName, settings.Day, settings.Hour, settings.Minute, settings.Enabled,
+	).Scan(&settings.LastSentMonth)
+	if err != nil {
+		return fmt.Errorf("сохранение расписания сводки: %w", err)
+	}
+	return nil
+}
+
+// ClaimDigest отмечает сводку группы за месяц отправленной. Возвращает false,
+// если сводку за этот месяц уже отправили.
+func (r *Repository) ClaimDigest(ctx context.Context, groupName string, month time.Time) (bool, error) {
+	result, err := r.conn().Exec(ctx, QueryClaimDigest, groupName, month)
+	if err != nil {
+		return false, fmt.Errorf("отметка об отправке сводки: %w", err)
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// CreatePaymentRequest сохраняет просьбу оплатить покупку.
+func (r *Repository) CreatePaymentRequest(ctx context.Context, req *models.PaymentRequest) error {
+	err := r.conn().QueryRow(
+		ctx, QueryCreatePaymentRequest,
+		req.GroupName, req.RuleID, req.RequesterID, req.RequesterName, req.PayerID, req.PayerName,
+		req.BankName, req.Category, req.Amount,
+	).Scan(&req.ID, &req.Status, &req.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("добавление просьбы оплатить: %w", err)
+	}
+	return nil
+}
+
+// GetPaymentRequest получает просьбу оплатить по ID.
+func (r *Repository) GetPaymentRequest(ctx context.Context, id int64) (*models.PaymentRequest, error) {
+	req, err := scanPaymentRequest(r.conn().QueryRow(ctx, QueryGetPaymentRequest, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("просьба оплатить с ID %d: %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("получение просьбы оплатить %d: %w", id, err)
+	}
+	return req, nil
+}
+
+// ResolvePaymentRequest записывает ответ на просьбу оплатить. Возвращает false,
+// если на просьбу уже ответили.
+func (r *Repository) ResolvePaymentRequest(ctx context.Context, req *models.PaymentRequest, status string) (bool, error) {
+	var resolvedAt time.Time
+	err := r.conn().QueryRow(ctx, QueryResolvePaymentRequest, req.ID, status).Scan(&resolvedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("ответ на просьбу оплатить %d: %w", req.ID, err)
+	}
+
+	req.Status = status
+	req.ResolvedAt = &resolvedAt
+	return true, nil
+}
+
+// ListAcceptedPayments возвращает оплаты группы, по которым деньги ещё не вернули.
+func (r *Repository) ListAcceptedPayments(ctx context.Context, groupName string) ([]models.PaymentRequest, error) {
+	rows, err := r.conn().Query(ctx, QueryListAcceptedPayments, groupName)
+	if err != nil {
+		return nil, fmt.Errorf("получение оплат группы: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.PaymentRequest
+	for rows.Next() {
+		req, err := scanPaymentRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("чтение оплаты: %w", err)
+		}
+		list = append(list, *req)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return list, nil
+}
+
+// SettlePayments закрывает оплаты между двумя участниками группы в обе стороны
+// с ID не больше lastPaymentID и возвращает количество закрытых оплат.
+func (r *Repository) SettlePayments(ctx context.Context, groupName, userA, userB string, lastPaymentID int64) (int, error) {
+	result, err := r.conn().Exec(ctx, QuerySettlePayments, groupName, userA, userB, lastPaymentID)
+	if err != nil {
+		return 0, fmt.Errorf("закрытие оплат: %w", err)
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// ListBankCaps возвращает общие лимиты банков пользователя.
+func (r *Repository) ListBankCaps(ctx context.Context, userID string) ([]models.BankCap, error) {
+	return r.queryBankCaps(ctx, QueryListBankCaps, userID)
+}
+
+// GetBankCaps возвращает лимиты банка пользователя за все периоды.
+func (r *Repository) GetBankCaps(ctx context.Context, userID, bankName string) ([]models.BankCap, error) {
+	return r.queryBankCaps(ctx, QueryGetBankCaps, userID, bankName)
+}
+
+// queryBankCaps выполняет запрос лимитов банков.
+func (r *Repository) queryBankCaps(ctx context.Context, query string, args ...interface{}) ([]models.BankCap, error) {
+	rows, err := r.conn().Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("получение лимитов банков: %w", err)
+	}
+	defer rows.Close()
+
+	var caps []models.BankCap
+	for rows.Next() {
+		var c models.BankCap
+		if err := rows.Scan(&c.UserID, &c.BankName, &c.Period, &c.Amount, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("чтение лимита банка: %w", err)
+		}
+		caps = append(caps, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return caps, nil
+}
+
+// UpsertBankCap сохраняет лимит банка за период.
+func (r *Repository) UpsertBankCap(ctx context.Context, bankCap *models.BankCap) error {
+	err := r.conn().QueryRow(
+		ctx, QueryUpsertBankCap,
+		bankCap.UserID, bankCap.BankName, bankCap.Period, bankCap.Amount,
+	).Scan(&bankCap.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("сохранение лимита банка: %w", err)
+	}
+	return nil
+}
+
+// DeleteBankCaps удаляет все лимиты банка пользователя.
+func (r *Repository) DeleteBankCaps(ctx context.Context, userID, bankName string) error {
+	result, err := r.conn().Exec(ctx, QueryDeleteBankCaps, userID, bankName)
+	if err != nil {
+		return fmt.Errorf("удаление лимита банка: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("лимит банка %s: %w", bankName, ErrNotFound)
+	}
+	return nil
+}
+
+// CreateSpend записывает покупку в журнал.
+func (r *Repository) CreateSpend(ctx context.Context, spend *models.Spend) error {
+	err := r.conn().QueryRow(
+		ctx, QueryCreateSpend,
+		spend.GroupName, spend.UserID, spend.BankName, spend.Category, spend.RuleID, spend.PaymentRequestID,
+		spend.Amount, spend.Cashback, spend.SpentAt,
+	).Scan(&spend.ID)
+	if err != nil {
+		return fmt.Errorf("запись покупки: %w", err)
+	}
+	return nil
+}
+
+// SumBankCashback возвращает кэшбэк, заработанный по картам банка пользователя за [from, to).
+func (r *Repository) SumBankCashback(ctx context.Context, userID, bankName string, from, to time.Time) (float64, error) {
+	var sum float64
+	if err := r.conn().QueryRow(ctx, QuerySumBankCashback, userID, bankName, from, to).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("подсчёт кэшбэка банка: %w", err)
+	}
+	return sum, nil
+}
+
+// SumRuleCashback возвращает кэшбэк, уже заработанный по правилу.
+func (r *Repository) SumRuleCashback(ctx context.Context, ruleID int64) (float64, error) {
+	var sum float64
+	if err := r.conn().QueryRow(ctx, QuerySumRuleCashback, ruleID).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("подсчёт кэшбэка правила: %w", err)
+	}
+	return sum, nil
+}
+
+// ListEarnedCashback возвращает кэшбэк участников группы по журналу трат
+// за [from, to) по месяцам, банкам и категориям.
+func (r *Repository) ListEarnedCashback(ctx context.Context, groupName string, from, to time.Time) ([]models.EarnedCashback, error) {
+	rows, err := r.conn().Query(ctx, QueryListEarnedCashback, groupName, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("получение заработанного кэшбэка: %w", err)
+	}
+	defer rows.Close()
+
+	var earned []models.EarnedCashback
+	for rows.Next() {
+		var e models.EarnedCashback
+		if err := rows.Scan(&e.Month, &e.UserID, &e.UserDisplayName, &e.BankName, &e.Category, &e.Spends, &e.Cashback); err != nil {
+			return nil, fmt.Errorf("чтение заработанного кэшбэка: %w", err)
+		}
+		earned = append(earned, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("итерация результатов: %w", err)
+	}
+
+	return earned, nil
+}
+
+// scanPaymentRequest читает просьбу оплатить в порядке paymentRequestColumns.
+func scanPaymentRequest(row pgx.Row) (*models.PaymentRequest, error) {
+	var p models.PaymentRequest
+	err := row.Scan(
+		&p.ID, &p.GroupName, &p.RuleID, &p.RequesterID, &p.RequesterName, &p.PayerID, &p.PayerName,
+		&p.BankName, &p.Category, &p.Amount, &p.Status, &p.CreatedAt, &p.ResolvedAt, &p.SettledAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
 // buildUpdateQuery строит динамический UPDATE запрос.
 func (r *Repository) buildUpdateQuery(id int64, updates map[string]interface{}) (string, []interface{}) {
 	query := "UPDATE cashback_rules SET "
@@ -409,3 +1224,26 @@ func (r *Repository) buildUpdateQuery(id int64, updates map[string]interface{})
 
 	return query, args
 }
+
+// ListDueActivations возвращает неактивированные правила, о которых ещё не
+// напоминали: со сроком активации с from по to включительно или без срока,
+// если на дату from уже идёт месяц правила.
+func (r *Repository) ListDueActivations(ctx context.Context, from, to time.Time) ([]models.CashbackRule, error) {
+	rows, err := r.conn().Query(ctx, QueryListDueActivations, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("получение правил для напоминания об активации: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanCashbackRules(rows)
+}
+
+// ClaimActivationReminder отмечает напоминание об активации правила
+// отправленным. Возвращает false, если напоминание уже отправили.
+func (r *Repository) ClaimActivationReminder(ctx context.Context, ruleID int64) (bool, error) {
+	result, err := r.conn().Exec(ctx, QueryClaimActivationReminder, ruleID)
+	if err != nil {
+		return false, fmt.Errorf("отметка о напоминании об активации %d: %w", ruleID, err)
+	}
+	return result.RowsAffected() > 0, nil
+}
@@ -0,0 +1,131 @@
+// Package exporter выгружает правила кэшбэка в CSV, XLSX, JSON и iCalendar.
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// Format определяет формат выгрузки.
+type Format string
+
+// Поддерживаемые форматы.
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatJSON Format = "json"
+	FormatICS  Format = "ics"
+)
+
+// Formats — все форматы в порядке отображения.
+var Formats = []Format{FormatCSV, FormatXLSX, FormatJSON, FormatICS}
+
+// ErrUnknownFormat возвращается для неподдерживаемого формата.
+var ErrUnknownFormat = errors.New("неподдерживаемый формат выгрузки")
+
+// Header — заголовок табличной выгрузки. Совпадает с колонками,
+// которые распознаёт импорт, поэтому выгрузку можно загрузить обратно.
+var Header = []string{"Банк", "Категория", "Процент", "Лимит", "Дата", "Владелец"}
+
+// ParseFormat разбирает формат выгрузки без учёта регистра.
+func ParseFormat(value string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(value)))
+	for _, known := range Formats {
+		if format == known {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, value)
+}
+
+// ContentType возвращает MIME тип формата.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatICS:
+		return "text/calendar; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// FileName возвращает имя файла выгрузки.
+func (f Format) FileName(base string) string {
+	return base + "." + string(f)
+}
+
+// Write выгружает правила в указанном формате.
+func Write(w io.Writer, format Format, rules []models.CashbackRule) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, rules)
+	case FormatXLSX:
+		return WriteXLSX(w, rules)
+	case FormatJSON:
+		return WriteJSON(w, rules)
+	case FormatICS:
+		return WriteICS(w, rules)
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// WriteCSV выгружает правила в CSV с разделителем ";" и BOM,
+// чтобы Excel с русской локалью открывал файл без мастера импорта.
+func WriteCSV(w io.Writer, rules []models.CashbackRule) error {
+	if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	if err := writer.Write(Header); err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := writer.Write(ruleRecord(rule)); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteJSON выгружает правила JSON массивом.
+func WriteJSON(w io.Writer, rules []models.CashbackRule) error {
+	if rules == nil {
+		rules = []models.CashbackRule{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rules)
+}
+
+// ruleRecord форматирует правило строкой таблицы.
+func ruleRecord(rule models.CashbackRule) []string {
+	return []string{
+		rule.BankName,
+		rule.Category,
+		formatNumber(rule.CashbackPercent),
+		formatNumber(rule.MaxAmount),
+		rule.MonthYear.Format("02.01.2006"),
+		rule.UserDisplayName,
+	}
+}
+
+// formatNumber форматирует число без лишних нулей.
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,111 @@
+package exporter
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/importer"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func testRules() []models.CashbackRule {
+	return []models.CashbackRule{
+		{
+			ID: 1, BankName: "Тинькофф", Category: "Такси", UserDisplayName: "Иван",
+			MonthYear: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), CashbackPercent: 5.5, MaxAmount: 3000,
+		},
+		{
+			ID: 2, BankName: "Альфа", Category: "Кафе, рестораны", UserDisplayName: "Мария",
+			MonthYear: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), CashbackPercent: 10, MaxAmount: 1000,
+		},
+	}
+}
+
+func TestTableExportsRoundTripThroughImporter(t *testing.T) {
+	// Процент меньше 1 не должен превращаться в долю при обратной загрузке
+	rules := append(testRules(), models.CashbackRule{
+		ID: 3, BankName: "Сбер", Category: "Все покупки", UserDisplayName: "Иван",
+		MonthYear: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), CashbackPercent: 0.5,
+	})
+	want := [][]string{
+		Header,
+		{"Тинькофф", "Такси", "5.5", "3000", "31.12.2024", "Иван"},
+		{"Альфа", "Кафе, рестораны", "10", "1000", "31.01.2025", "Мария"},
+		{"Сбер", "Все покупки", "0.5", "0", "31.01.2025", "Иван"},
+	}
+
+	for _, format := range []Format{FormatCSV, FormatXLSX} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, rules); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			table, err := importer.ReadTable(format.FileName("cashback"), buf.Bytes())
+			if err != nil {
+				t.Fatalf("ReadTable failed: %v", err)
+			}
+			if !reflect.DeepEqual(table, want) {
+				t.Errorf("Expected %v, got %v", want, table)
+			}
+
+			if missing := importer.DetectMapping(table[0]).Missing(); len(missing) != 0 {
+				t.Errorf("Import does not recognize columns %v", missing)
+			}
+
+			for i, rule := range rules {
+				percent, err := importer.ParsePercent(table[i+1][2])
+				if err != nil || percent != rule.CashbackPercent {
+					t.Errorf("ParsePercent(%q) = %v, %v; want %v", table[i+1][2], percent, err, rule.CashbackPercent)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, testRules()); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	ics := buf.String()
+
+	for _, expected := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:cashback-1@open-cashback-advisor\r\n",
+		"DTSTART;VALUE=DATE:20241231\r\n",
+		"DTEND;VALUE=DATE:20250101\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, expected) {
+			t.Errorf("Expected %q in calendar", expected)
+		}
+	}
+
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("Expected 2 events, got %d", strings.Count(ics, "BEGIN:VEVENT"))
+	}
+
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, `Кафе\, рестораны`) {
+		t.Error("Expected escaped comma in summary")
+	}
+
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > icsMaxLineLength {
+			t.Errorf("Line longer than %d bytes: %q", icsMaxLineLength, line)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat(" XLSX "); err != nil || format != FormatXLSX {
+		t.Errorf("ParseFormat(XLSX) = %v, %v", format, err)
+	}
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,83 @@
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// icsMaxLineLength — максимальная длина строки iCalendar в байтах (RFC 5545, 3.1).
+const icsMaxLineLength = 75
+
+// icsEscaper экранирует спецсимволы текстовых значений iCalendar.
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// WriteICS выгружает правила в календарь: окончание действия каждого
+// правила — событие на весь день.
+func WriteICS(w io.Writer, rules []models.CashbackRule) error {
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//open-cashback-advisor//RU",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Кэшбэк",
+	}
+
+	for _, rule := range rules {
+		day := rule.MonthYear
+		summary := fmt.Sprintf("Заканчивается кэшбэк %s%% — %s, %s",
+			formatNumber(rule.CashbackPercent), rule.Category, rule.BankName)
+		description := fmt.Sprintf("Банк: %s\nКатегория: %s\nПроцент: %s%%\nЛимит: %s ₽\nВладелец: %s",
+			rule.BankName, rule.Category, formatNumber(rule.CashbackPercent),
+			formatNumber(rule.MaxAmount), rule.UserDisplayName)
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:cashback-%d@open-cashback-advisor", rule.ID),
+			"DTSTAMP:"+stamp,
+			"DTSTART;VALUE=DATE:"+day.Format("20060102"),
+			"DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+icsEscaper.Replace(summary),
+			"DESCRIPTION:"+icsEscaper.Replace(description),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldICSLine(line)+"\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// foldICSLine переносит длинную строку: продолжение начинается с пробела.
+// Перенос не разрывает многобайтовые символы UTF-8.
+func foldICSLine(line string) string {
+	if len(line) <= icsMaxLineLength {
+		return line
+	}
+
+	var sb strings.Builder
+	limit := icsMaxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// Строки продолжения начинаются с пробела, он входит в лимит
+		limit = icsMaxLineLength - 1
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
@@ -0,0 +1,104 @@
+package exporter
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// Служебные части книги XLSX, не зависящие от данных.
+const (
+	xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Кэшбэк" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+)
+
+// WriteXLSX выгружает правила в книгу XLSX с одним листом.
+// Строки записываются как inline строки, проценты и лимиты — числами.
+func WriteXLSX(w io.Writer, rules []models.CashbackRule) error {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", xlsxWorkbook},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/worksheets/sheet1.xml", buildSheet(rules)},
+	}
+
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("создание %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return fmt.Errorf("запись %s: %w", part.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// buildSheet формирует XML листа с заголовком и правилами.
+func buildSheet(rules []models.CashbackRule) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	writeRow(&sb, 1, Header, nil)
+	for i, rule := range rules {
+		// Процент и лимит — числовые колонки
+		writeRow(&sb, i+2, ruleRecord(rule), map[int]bool{2: true, 3: true})
+	}
+
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+// writeRow записывает строку листа. numeric — индексы числовых колонок.
+func writeRow(sb *strings.Builder, rowNum int, values []string, numeric map[int]bool) {
+	fmt.Fprintf(sb, `<row r="%d">`, rowNum)
+	for col, value := range values {
+		ref := fmt.Sprintf("%s%d", columnName(col), rowNum)
+		if numeric[col] {
+			fmt.Fprintf(sb, `<c r="%s"><v>%s</v></c>`, ref, value)
+			continue
+		}
+		fmt.Fprintf(sb, `<c r="%s" t="inlineStr"><is><t>`, ref)
+		xml.EscapeText(sb, []byte(value))
+		sb.WriteString(`</t></is></c>`)
+	}
+	sb.WriteString(`</row>`)
+}
+
+// columnName преобразует индекс колонки (0) в её имя ("A").
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/exporter"
 	"github.com/rymax1e/open-cashback-advisor/internal/models"
 	"github.com/rymax1e/open-cashback-advisor/internal/service"
 )
@@ -64,6 +71,16 @@ func (h *Handler) CreateCashback(w http.ResponseWriter, r *http.Request) {
 
 	rule, err := h.service.CreateCashback(r.Context(), &req)
 	if err != nil {
+		var duplicate *service.DuplicateRuleError
+		if errors.As(err, &duplicate) {
+			respondJSON(w, http.StatusConflict, models.ConflictResponse{
+				Error:     "Правило уже существует",
+				Details:   []string{err.Error()},
+				Existing:  duplicate.Existing,
+				Identical: duplicate.Identical,
+			})
+			return
+		}
 		respondError(w, http.StatusBadRequest, "Ошибка создания правила", err.Error())
 		return
 	}
@@ -71,6 +88,92 @@ func (h *Handler) CreateCashback(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusCreated, rule)
 }
 
+// BatchCashback обрабатывает POST /api/v1/cashback/batch
+func (h *Handler) BatchCashback(w http.ResponseWriter, r *http.Request) {
+	var req models.BatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	response, err := h.service.BatchCashback(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidBatch) {
+			respondError(w, http.StatusBadRequest, "Неверный пакетный запрос", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка пакетной операции", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// maxImportFileSize — максимальный размер файла импорта.
+const maxImportFileSize = 5 << 20
+
+// ImportCashback обрабатывает POST /api/v1/cashback/import
+//
+// Принимает multipart/form-data: file (CSV или XLSX), group_name, user_id,
+// user_display_name, dry_run (true — только предпросмотр) и необязательный
+// mapping — JSON объект "поле правила → заголовок колонки".
+func (h *Handler) ImportCashback(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize+1<<20)
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Файл не передан", err.Error())
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка чтения файла", err.Error())
+		return
+	}
+	if len(data) > maxImportFileSize {
+		respondError(w, http.StatusRequestEntityTooLarge, "Файл слишком большой")
+		return
+	}
+
+	req := models.ImportRequest{
+		GroupName:       r.FormValue("group_name"),
+		UserID:          r.FormValue("user_id"),
+		UserDisplayName: r.FormValue("user_display_name"),
+		FileName:        fileHeader.Filename,
+		Data:            data,
+	}
+	req.DryRun, _ = strconv.ParseBool(r.FormValue("dry_run"))
+
+	if mapping := r.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &req.Mapping); err != nil {
+			respondError(w, http.StatusBadRequest, "Неверный формат mapping", err.Error())
+			return
+		}
+	}
+
+	report, err := h.service.ImportCashback(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidImport) {
+			respondError(w, http.StatusBadRequest, "Ошибка импорта", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка импорта", err.Error())
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	respondJSON(w, status, report)
+}
+
 // GetCashback обрабатывает GET /api/v1/cashback/{id}
 func (h *Handler) GetCashback(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -178,19 +281,95 @@ func (h *Handler) GetBestCashback(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	purchase, err := parsePurchaseContext(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверные параметры покупки", err.Error())
+		return
+	}
+
 	req := &models.BestCashbackRequest{
 		GroupName: groupName,
 		Category:  category,
 		MonthYear: monthYear,
+		Purchase:  purchase,
+		UserID:    r.URL.Query().Get("user_id"),
 	}
 
-	rule, err := h.service.GetBestCashback(r.Context(), req)
+	best, err := h.service.GetBestCashback(r.Context(), req)
 	if err != nil {
 		respondError(w, http.StatusNotFound, "Правила не найдены", err.Error())
 		return
 	}
 
-	respondJSON(w, http.StatusOK, rule)
+	respondJSON(w, http.StatusOK, best)
+}
+
+// GetMerchantBestCashback обрабатывает GET /api/v1/cashback/best/merchant
+func (h *Handler) GetMerchantBestCashback(w http.ResponseWriter, r *http.Request) {
+	groupName := r.URL.Query().Get("group_name")
+	merchant := r.URL.Query().Get("merchant")
+	monthYear := r.URL.Query().Get("month_year")
+
+	if groupName == "" || merchant == "" || monthYear == "" {
+		respondError(w, http.StatusBadRequest, "Параметры group_name, merchant и month_year обязательны")
+		return
+	}
+
+	purchase, err := parsePurchaseContext(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверные параметры покупки", err.Error())
+		return
+	}
+
+	resp, err := h.service.GetMerchantBestCashback(r.Context(), &models.MerchantBestRequest{
+		GroupName: groupName,
+		Merchant:  merchant,
+		MonthYear: monthYear,
+		Purchase:  purchase,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrUnknownMerchant) {
+			respondError(w, http.StatusNotFound, "Магазин не найден", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка поиска кэшбэка", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// parsePurchaseContext читает параметры покупки из query:
+// amount, monthly_spend, weekday (1-7) и payment_method (card, sbp, qr).
+func parsePurchaseContext(r *http.Request) (models.PurchaseContext, error) {
+	query := r.URL.Query()
+	purchase := models.PurchaseContext{PaymentMethod: query.Get("payment_method")}
+
+	if value := query.Get("amount"); value != "" {
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return purchase, fmt.Errorf("amount: %w", err)
+		}
+		purchase.Amount = amount
+	}
+
+	if value := query.Get("monthly_spend"); value != "" {
+		spend, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return purchase, fmt.Errorf("monthly_spend: %w", err)
+		}
+		purchase.MonthlySpend = spend
+	}
+
+	if value := query.Get("weekday"); value != "" {
+		weekday, err := strconv.Atoi(value)
+		if err != nil {
+			return purchase, fmt.Errorf("weekday: %w", err)
+		}
+		purchase.Weekday = weekday
+	}
+
+	return purchase, nil
 }
 
 // Health обрабатывает GET /health
@@ -207,11 +386,17 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Route("/cashback", func(r chi.Router) {
 			r.Post("/suggest", h.Suggest)
 			r.Post("/", h.CreateCashback)
+			r.Post("/import", h.ImportCashback)
+			r.Post("/batch", h.BatchCashback)
 			r.Get("/", h.ListCashback)
 			r.Get("/best", h.GetBestCashback)
+			r.Get("/best/merchant", h.GetMerchantBestCashback)
+			r.Get("/activations/due", h.ListDueActivations)
 			r.Get("/{id}", h.GetCashback)
 			r.Put("/{id}", h.UpdateCashback)
 			r.Delete("/{id}", h.DeleteCashback)
+			r.Post("/{id}/activate", h.ActivateCashback)
+			r.Post("/{id}/activation/remind", h.ClaimActivationReminder)
 		})
 
 		// Группы
@@ -220,12 +405,82 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 			r.Get("/", h.GetAllGroups)
 			r.Get("/check", h.GetGroup)      // ?name=groupName
 			r.Get("/members", h.GetGroupMembers) // ?name=groupName
+			r.Get("/{name}/export", h.ExportGroupCashback)
+			r.Post("/{name}/advice", h.AdviseCategories)
+			r.Get("/{name}/coverage", h.GetGroupCoverage) // ?month_year=...&min_percent=...
+			r.Get("/{name}/stats", h.GetGroupStats)       // ?from=...&to=...
+			r.Get("/{name}/history", h.GetGroupHistory)   // ?bank=...&category=...&from=...&to=...
+			r.Get("/{name}/trend", h.GetBankTrend)        // ?bank=...&category=...&from=...&to=...
+			r.Get("/{name}/digest", h.GetGroupDigest)     // ?month_year=...
+			r.Post("/{name}/digest/claim", h.ClaimGroupDigest)
+			r.Get("/{name}/digest/settings", h.GetDigestSettings)
+			r.Put("/{name}/digest/settings", h.SetDigestSettings)
+			r.Post("/{name}/payment-requests", h.CreatePaymentRequest)
+			r.Get("/{name}/balances", h.GetGroupBalances)
+			r.Post("/{name}/balances/settle", h.SettleDebt)
+		})
+
+		// Просьбы оплатить покупку
+		r.Route("/payment-requests", func(r chi.Router) {
+			r.Post("/{id}/answer", h.AnswerPaymentRequest)
+		})
+
+		// Журнал трат
+		r.Route("/spends", func(r chi.Router) {
+			r.Post("/", h.RecordSpend)
+		})
+
+		// Ежемесячные сводки
+		r.Route("/digests", func(r chi.Router) {
+			r.Get("/due", h.ListDueDigests)
 		})
 
 		// Пользователи и группы
 		r.Route("/users/{userID}", func(r chi.Router) {
 			r.Get("/group", h.GetUserGroup)
 			r.Put("/group", h.SetUserGroup)
+			r.Get("/cards", h.ListUserCards)
+			r.Get("/bank-caps", h.ListBankCaps)
+			r.Put("/bank-caps/{bank}", h.SetBankCap)
+			r.Delete("/bank-caps/{bank}", h.DeleteBankCap)
+		})
+
+		// Карты пользователей
+		r.Route("/cards", func(r chi.Router) {
+			r.Post("/", h.CreateCard)
+			r.Delete("/{id}", h.DeleteCard) // ?user_id=...
+		})
+
+		// Меню предложений банков
+		r.Route("/offer-menus", func(r chi.Router) {
+			r.Get("/", h.ListOfferMenus) // ?month_year=...
+			r.Put("/{bank}", h.SetOfferMenu)
+		})
+
+		// Дерево категорий
+		r.Route("/categories", func(r chi.Router) {
+			r.Get("/", h.ListCategories)
+			r.Put("/{name}", h.SetCategory)
+			r.Get("/{name}/path", h.GetCategoryPath)
+		})
+
+		// Справочник магазинов
+		r.Route("/merchants", func(r chi.Router) {
+			r.Get("/", h.ListMerchants)
+			r.Put("/{name}", h.SetMerchant)
+		})
+
+		// Программы вознаграждения и курсы к рублю
+		r.Route("/reward-programs", func(r chi.Router) {
+			r.Get("/", h.ListRewardPrograms)
+			r.Put("/{code}", h.SetRewardProgram)
+		})
+
+		// Групповые Telegram чаты
+		r.Route("/chats/{chatID}", func(r chi.Router) {
+			r.Get("/group", h.GetChatBinding)
+			r.Put("/group", h.BindChat)
+			r.Delete("/group", h.UnbindChat)
 		})
 	})
 
@@ -320,6 +575,43 @@ func (h *Handler) GetGroupMembers(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ExportGroupCashback обрабатывает GET /api/v1/groups/{name}/export?format=csv|xlsx|json|ics
+func (h *Handler) ExportGroupCashback(w http.ResponseWriter, r *http.Request) {
+	groupName := chi.URLParam(r, "name")
+
+	formatValue := r.URL.Query().Get("format")
+	if formatValue == "" {
+		formatValue = string(exporter.FormatCSV)
+	}
+	format, err := exporter.ParseFormat(formatValue)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат выгрузки", err.Error())
+		return
+	}
+
+	rules, err := h.service.ExportCashback(r.Context(), groupName)
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка выгрузки", err.Error())
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.Write(&buf, format, rules); err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка выгрузки", err.Error())
+		return
+	}
+
+	fileName := format.FileName("cashback-" + groupName)
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
 // GetUserGroup получает группу пользователя
 func (h *Handler) GetUserGroup(w http.ResponseWriter, r *http.Request) {
 	userID := chi.URLParam(r, "userID")
@@ -365,3 +657,712 @@ func (h *Handler) SetUserGroup(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+
+// --- Обработчики для справочника магазинов ---
+
+// ListMerchants обрабатывает GET /api/v1/merchants
+func (h *Handler) ListMerchants(w http.ResponseWriter, r *http.Request) {
+	merchants, err := h.service.ListMerchants(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения магазинов", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, merchants)
+}
+
+// SetMerchant обрабатывает PUT /api/v1/merchants/{name}
+func (h *Handler) SetMerchant(w http.ResponseWriter, r *http.Request) {
+	var req models.MerchantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	merchant, err := h.service.SetMerchant(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения магазина", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, merchant)
+}
+
+// GetGroupCoverage обрабатывает GET /api/v1/groups/{name}/coverage
+func (h *Handler) GetGroupCoverage(w http.ResponseWriter, r *http.Request) {
+	req := &models.CoverageRequest{
+		GroupName: chi.URLParam(r, "name"),
+		MonthYear: r.URL.Query().Get("month_year"),
+	}
+	if value := r.URL.Query().Get("min_percent"); value != "" {
+		minPercent, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Неверный параметр min_percent", err.Error())
+			return
+		}
+		req.MinPercent = minPercent
+	}
+
+	report, err := h.service.GetGroupCoverage(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка проверки покрытия категорий", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetGroupStats обрабатывает GET /api/v1/groups/{name}/stats
+func (h *Handler) GetGroupStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.GetGroupStats(r.Context(), &models.StatsRequest{
+		GroupName: chi.URLParam(r, "name"),
+		From:      r.URL.Query().Get("from"),
+		To:        r.URL.Query().Get("to"),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения статистики", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, stats)
+}
+
+// historyRequest собирает запрос истории правил группы из пути и query параметров.
+func historyRequest(r *http.Request) *models.HistoryRequest {
+	query := r.URL.Query()
+	return &models.HistoryRequest{
+		GroupName: chi.URLParam(r, "name"),
+		BankName:  query.Get("bank"),
+		Category:  query.Get("category"),
+		From:      query.Get("from"),
+		To:        query.Get("to"),
+	}
+}
+
+// GetGroupHistory обрабатывает GET /api/v1/groups/{name}/history
+func (h *Handler) GetGroupHistory(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.GetGroupHistory(r.Context(), historyRequest(r))
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения истории", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// GetBankTrend обрабатывает GET /api/v1/groups/{name}/trend
+func (h *Handler) GetBankTrend(w http.ResponseWriter, r *http.Request) {
+	trend, err := h.service.GetBankTrend(r.Context(), historyRequest(r))
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения динамики", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, trend)
+}
+
+// --- Обработчики для ежемесячных сводок ---
+
+// GetDigestSettings обрабатывает GET /api/v1/groups/{name}/digest/settings
+func (h *Handler) GetDigestSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.service.GetDigestSettings(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения расписания сводки", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// SetDigestSettings обрабатывает PUT /api/v1/groups/{name}/digest/settings
+func (h *Handler) SetDigestSettings(w http.ResponseWriter, r *http.Request) {
+	var req models.DigestSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	settings, err := h.service.SetDigestSettings(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения расписания сводки", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, settings)
+}
+
+// GetGroupDigest обрабатывает GET /api/v1/groups/{name}/digest
+func (h *Handler) GetGroupDigest(w http.ResponseWriter, r *http.Request) {
+	digest, err := h.service.GetGroupDigest(r.Context(), chi.URLParam(r, "name"), r.URL.Query().Get("month_year"))
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения сводки", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, digest)
+}
+
+// ClaimGroupDigest обрабатывает POST /api/v1/groups/{name}/digest/claim
+func (h *Handler) ClaimGroupDigest(w http.ResponseWriter, r *http.Request) {
+	var req models.DigestClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	digest, err := h.service.ClaimGroupDigest(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotExists):
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+		case errors.Is(err, service.ErrDigestAlreadySent):
+			respondError(w, http.StatusConflict, "Сводка уже отправлена", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Ошибка получения сводки", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, digest)
+}
+
+// ListDueDigests обрабатывает GET /api/v1/digests/due
+func (h *Handler) ListDueDigests(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := h.service.ListDueDigests(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения сводок к отправке", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+// --- Обработчики для просьб оплатить и долгов ---
+
+// CreatePaymentRequest обрабатывает POST /api/v1/groups/{name}/payment-requests
+func (h *Handler) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	var req models.CreatePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	payment, err := h.service.CreatePaymentRequest(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotExists):
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+		case errors.Is(err, database.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Правило не найдено", err.Error())
+		case errors.Is(err, service.ErrNotGroupMember):
+			respondError(w, http.StatusForbidden, "Просить оплатить может только участник группы", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Ошибка создания просьбы оплатить", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, payment)
+}
+
+// AnswerPaymentRequest обрабатывает POST /api/v1/payment-requests/{id}/answer
+func (h *Handler) AnswerPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID")
+		return
+	}
+
+	var req models.PaymentAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	payment, err := h.service.AnswerPaymentRequest(r.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownPayment):
+			respondError(w, http.StatusNotFound, "Просьба оплатить не найдена", err.Error())
+		case errors.Is(err, service.ErrNotPayer):
+			respondError(w, http.StatusForbidden, "Ответить может только владелец карты", err.Error())
+		case errors.Is(err, service.ErrPaymentAnswered):
+			respondError(w, http.StatusConflict, "На просьбу уже ответили", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Ошибка ответа на просьбу оплатить", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, payment)
+}
+
+// GetGroupBalances обрабатывает GET /api/v1/groups/{name}/balances
+func (h *Handler) GetGroupBalances(w http.ResponseWriter, r *http.Request) {
+	balances, err := h.service.GetGroupBalances(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка получения долгов группы", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, balances)
+}
+
+// SettleDebt обрабатывает POST /api/v1/groups/{name}/balances/settle
+func (h *Handler) SettleDebt(w http.ResponseWriter, r *http.Request) {
+	var req models.SettleDebtRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	result, err := h.service.SettleDebt(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotExists):
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+		case errors.Is(err, service.ErrNoDebt):
+			respondError(w, http.StatusNotFound, "Долг не найден", err.Error())
+		case errors.Is(err, service.ErrDebtChanged):
+			respondError(w, http.StatusConflict, "Долг изменился", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Ошибка закрытия долга", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// --- Обработчики для лимитов банков и журнала трат ---
+
+// ListBankCaps обрабатывает GET /api/v1/users/{userID}/bank-caps
+func (h *Handler) ListBankCaps(w http.ResponseWriter, r *http.Request) {
+	caps, err := h.service.ListBankCaps(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения лимитов банков", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, caps)
+}
+
+// SetBankCap обрабатывает PUT /api/v1/users/{userID}/bank-caps/{bank}
+func (h *Handler) SetBankCap(w http.ResponseWriter, r *http.Request) {
+	var req models.BankCapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	bankCap, err := h.service.SetBankCap(r.Context(), chi.URLParam(r, "userID"), chi.URLParam(r, "bank"), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения лимита банка", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, bankCap)
+}
+
+// DeleteBankCap обрабатывает DELETE /api/v1/users/{userID}/bank-caps/{bank}
+func (h *Handler) DeleteBankCap(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteBankCap(r.Context(), chi.URLParam(r, "userID"), chi.URLParam(r, "bank")); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Лимит банка не найден", err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка удаления лимита банка", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Лимит банка удалён"})
+}
+
+// RecordSpend обрабатывает POST /api/v1/spends
+func (h *Handler) RecordSpend(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateSpendRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	spend, err := h.service.RecordSpend(r.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotExists):
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+		case errors.Is(err, database.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Кэшбэк не найден", err.Error())
+		case errors.Is(err, service.ErrNotGroupMember):
+			respondError(w, http.StatusForbidden, "Записывать траты может только участник группы", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Ошибка записи траты", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, spend)
+}
+
+// --- Обработчики для активации категорий ---
+
+// ActivateCashback обрабатывает POST /api/v1/cashback/{id}/activate
+func (h *Handler) ActivateCashback(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID")
+		return
+	}
+
+	var req models.ActivateCashbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	rule, err := h.service.ActivateCashback(r.Context(), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Правило не найдено", err.Error())
+		case errors.Is(err, service.ErrNotRuleOwner):
+			respondError(w, http.StatusForbidden, "Отметить активацию может только владелец правила", err.Error())
+		default:
+			respondError(w, http.StatusBadRequest, "Ошибка отметки об активации", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// ListDueActivations обрабатывает GET /api/v1/cashback/activations/due
+func (h *Handler) ListDueActivations(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.ListDueActivations(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения правил для напоминания", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rules)
+}
+
+// ClaimActivationReminder обрабатывает POST /api/v1/cashback/{id}/activation/remind
+func (h *Handler) ClaimActivationReminder(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID")
+		return
+	}
+
+	rule, err := h.service.ClaimActivationReminder(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrNotFound):
+			respondError(w, http.StatusNotFound, "Правило не найдено", err.Error())
+		case errors.Is(err, service.ErrReminderAlreadySent):
+			respondError(w, http.StatusConflict, "Напоминание уже отправлено", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Ошибка отметки о напоминании", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, rule)
+}
+
+// --- Обработчики для меню категорий банков ---
+
+// ListOfferMenus обрабатывает GET /api/v1/offer-menus?month_year=...
+func (h *Handler) ListOfferMenus(w http.ResponseWriter, r *http.Request) {
+	menus, err := h.service.ListOfferMenus(r.Context(), r.URL.Query().Get("month_year"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка получения меню категорий", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, menus)
+}
+
+// SetOfferMenu обрабатывает PUT /api/v1/offer-menus/{bank}
+func (h *Handler) SetOfferMenu(w http.ResponseWriter, r *http.Request) {
+	var req models.OfferMenuRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	menu, err := h.service.SetOfferMenu(r.Context(), chi.URLParam(r, "bank"), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения меню категорий", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, menu)
+}
+
+// AdviseCategories обрабатывает POST /api/v1/groups/{name}/advice
+func (h *Handler) AdviseCategories(w http.ResponseWriter, r *http.Request) {
+	var req models.CategoryAdviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+	req.GroupName = chi.URLParam(r, "name")
+
+	advice, err := h.service.AdviseCategories(r.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrGroupNotExists) {
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+			return
+		}
+		respondError(w, http.StatusBadRequest, "Ошибка подбора категорий", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, advice)
+}
+
+// --- Обработчики для дерева категорий ---
+
+// ListCategories обрабатывает GET /api/v1/categories
+func (h *Handler) ListCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := h.service.ListCategories(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения категорий", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, categories)
+}
+
+// SetCategory обрабатывает PUT /api/v1/categories/{name}
+func (h *Handler) SetCategory(w http.ResponseWriter, r *http.Request) {
+	var req models.CategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	category, err := h.service.SetCategory(r.Context(), chi.URLParam(r, "name"), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения категории", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, category)
+}
+
+// GetCategoryPath обрабатывает GET /api/v1/categories/{name}/path
+func (h *Handler) GetCategoryPath(w http.ResponseWriter, r *http.Request) {
+	path, err := h.service.GetCategoryPath(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка получения пути категории", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, path)
+}
+
+// --- Обработчики для карт ---
+
+// CreateCard обрабатывает POST /api/v1/cards
+func (h *Handler) CreateCard(w http.ResponseWriter, r *http.Request) {
+	var req models.CreateCardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	card, err := h.service.CreateCard(r.Context(), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка добавления карты", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, card)
+}
+
+// ListUserCards обрабатывает GET /api/v1/users/{userID}/cards
+func (h *Handler) ListUserCards(w http.ResponseWriter, r *http.Request) {
+	cards, err := h.service.ListUserCards(r.Context(), chi.URLParam(r, "userID"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения карт", err.Error())
+		return
+	}
+
+	if cards == nil {
+		cards = []models.Card{}
+	}
+	respondJSON(w, http.StatusOK, cards)
+}
+
+// DeleteCard обрабатывает DELETE /api/v1/cards/{id}?user_id=...
+func (h *Handler) DeleteCard(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID")
+		return
+	}
+
+	if err := h.service.DeleteCard(r.Context(), id, r.URL.Query().Get("user_id")); err != nil {
+		switch {
+		case errors.Is(err, service.ErrUnknownCard):
+			respondError(w, http.StatusNotFound, "Карта не найдена", err.Error())
+		case errors.Is(err, service.ErrCardMismatch):
+			respondError(w, http.StatusForbidden, "Нельзя удалить чужую карту", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Ошибка удаления карты", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{"message": "Карта удалена"})
+}
+
+// --- Обработчики для программ вознаграждения ---
+
+// ListRewardPrograms обрабатывает GET /api/v1/reward-programs
+func (h *Handler) ListRewardPrograms(w http.ResponseWriter, r *http.Request) {
+	programs, err := h.service.ListRewardPrograms(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Ошибка получения программ вознаграждения", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, programs)
+}
+
+// SetRewardProgram обрабатывает PUT /api/v1/reward-programs/{code}
+func (h *Handler) SetRewardProgram(w http.ResponseWriter, r *http.Request) {
+	var req models.RewardProgramRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	program, err := h.service.SetRewardProgram(r.Context(), chi.URLParam(r, "code"), &req)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Ошибка сохранения программы вознаграждения", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, program)
+}
+
+// --- Обработчики для групповых чатов ---
+
+// parseChatID извлекает ID чата из URL.
+func parseChatID(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "chatID"), 10, 64)
+}
+
+// BindChat привязывает групповой чат к группе
+func (h *Handler) BindChat(w http.ResponseWriter, r *http.Request) {
+	chatID, err := parseChatID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID чата")
+		return
+	}
+
+	var req models.BindChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный формат запроса", err.Error())
+		return
+	}
+
+	if req.GroupName == "" || req.UserID == "" {
+		respondError(w, http.StatusBadRequest, "Укажите group_name и user_id")
+		return
+	}
+
+	binding, err := h.service.BindChat(r.Context(), chatID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrGroupNotExists):
+			respondError(w, http.StatusNotFound, "Группа не найдена", err.Error())
+		case errors.Is(err, service.ErrNotGroupMember):
+			respondError(w, http.StatusForbidden, "Привязать чат может только участник группы", err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Ошибка привязки чата", err.Error())
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, binding)
+}
+
+// GetChatBinding возвращает группу, к которой привязан чат
+func (h *Handler) GetChatBinding(w http.ResponseWriter, r *http.Request) {
+	chatID, err := parseChatID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID чата")
+		return
+	}
+
+	binding, err := h.service.GetChatBinding(r.Context(), chatID)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Чат не привязан к группе")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка получения привязки чата", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, binding)
+}
+
+// UnbindChat отвязывает групповой чат от группы
+func (h *Handler) UnbindChat(w http.ResponseWriter, r *http.Request) {
+	chatID, err := parseChatID(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Неверный ID чата")
+		return
+	}
+
+	if err := h.service.UnbindChat(r.Context(), chatID); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			respondError(w, http.StatusNotFound, "Чат не привязан к группе")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Ошибка отвязки чата", err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]string{
+		"message": "Чат отвязан от группы",
+	})
+}
@@ -0,0 +1,167 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTableCSVDetectsDelimiter(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"comma", "Банк,Категория,Процент\nТинькофф,Такси,5\n"},
+		{"semicolon with BOM", "\xef\xbb\xbfБанк;Категория;Процент\nТинькофф;Такси;5\n"},
+		{"tab", "Банк\tКатегория\tПроцент\nТинькофф\tТакси\t5\n"},
+	}
+
+	want := [][]string{{"Банк", "Категория", "Процент"}, {"Тинькофф", "Такси", "5"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := ReadTable("cashback.csv", []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ReadTable failed: %v", err)
+			}
+			if !reflect.DeepEqual(table, want) {
+				t.Errorf("Expected %v, got %v", want, table)
+			}
+		})
+	}
+}
+
+func TestReadTableXLSX(t *testing.T) {
+	files := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Кэшбэк" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships>` +
+			`<Relationship Id="rId1" Target="worksheets/data.xml"/></Relationships>`,
+		"xl/styles.xml": `<styleSheet><numFmts><numFmt numFmtId="164" formatCode="0.0%"/></numFmts>` +
+			`<cellXfs><xf numFmtId="0"/><xf numFmtId="9"/><xf numFmtId="164"/></cellXfs></styleSheet>`,
+		"xl/sharedStrings.xml": `<sst><si><t>Банк</t></si><si><t>Категория</t></si>` +
+			`<si><r><t>Про</t></r><r><t>цент</t></r></si><si><t>Альфа</t></si></sst>`,
+		"xl/worksheets/data.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2" t="inlineStr"><is><t>Кафе</t></is></c><c r="D2"><v>0.05</v></c></row>` +
+			`<row r="3"><c r="A3" s="1"><v>0.05</v></c><c r="B3" s="2"><v>0.055</v></c><c r="C3" s="0"><v>0.5</v></c></row>` +
+			`</sheetData></worksheet>`,
+	}
+
+	table, err := ReadTable("cashback.xlsx", buildXLSX(files))
+	if err != nil {
+		t.Fatalf("ReadTable failed: %v", err)
+	}
+
+	want := [][]string{{"Банк", "Категория", "Процент"}, {"Альфа", "Кафе", "", "0.05"}, {"5%", "5.5%", "0.5"}}
+	if !reflect.DeepEqual(table, want) {
+		t.Errorf("Expected %v, got %v", want, table)
+	}
+}
+
+func TestReadTableXLSXRejectsFarColumns(t *testing.T) {
+	for _, ref := range []string{"JW1", "ZZZZZZ1", strings.Repeat("Z", 100) + "1"} {
+		data := buildXLSX(map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet><sheetData><row r="1"><c r="` + ref + `"><v>1</v></c></row></sheetData></worksheet>`,
+		})
+		if _, err := ReadTable("cashback.xlsx", data); !errors.Is(err, ErrTooManyColumns) {
+			t.Errorf("ReadTable() со ссылкой длиной %d error = %v, ожидалась ErrTooManyColumns", len(ref), err)
+		}
+	}
+}
+
+// buildXLSX собирает XLSX архив из файлов.
+func buildXLSX(files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, _ := archive.Create(name)
+		w.Write([]byte(content))
+	}
+	archive.Close()
+	return buf.Bytes()
+}
+
+func TestDetectMapping(t *testing.T) {
+	header := []string{"Дата окончания", "Банк", "Категория", "Лимит кэшбэка", "Кэшбэк, %"}
+	mapping := DetectMapping(header)
+
+	want := Mapping{
+		ColumnMonthYear: 0,
+		ColumnBank:      1,
+		ColumnCategory:  2,
+		ColumnMaxAmount: 3,
+		ColumnPercent:   4,
+	}
+	if !reflect.DeepEqual(mapping, want) {
+		t.Errorf("Expected %v, got %v", want, mapping)
+	}
+	if missing := mapping.Missing(); len(missing) != 0 {
+		t.Errorf("Expected no missing columns, got %v", missing)
+	}
+}
+
+func TestMappingOverrides(t *testing.T) {
+	header := []string{"Эмитент карты", "Что", "Ставка"}
+	mapping := DetectMapping(header)
+
+	if missing := mapping.Missing(); len(missing) != 2 {
+		t.Fatalf("Expected 2 missing columns, got %v", missing)
+	}
+
+	err := mapping.ApplyOverrides(header, map[string]string{"category": "что", "cashback_percent": "Ставка"})
+	if err != nil {
+		t.Fatalf("ApplyOverrides failed: %v", err)
+	}
+	if missing := mapping.Missing(); len(missing) != 0 {
+		t.Errorf("Expected no missing columns, got %v", missing)
+	}
+
+	if err := mapping.ApplyOverrides(header, map[string]string{"category": "Нет такой"}); err == nil {
+		t.Error("Expected error for unknown header")
+	}
+	if err := mapping.ApplyOverrides(header, map[string]string{"owner": "Что"}); err == nil {
+		t.Error("Expected error for unknown field")
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	numbers := map[string]float64{"3 000 ₽": 3000, "1,5": 1.5, "5000 руб.": 5000, "3 000р": 3000}
+	for input, want := range numbers {
+		got, err := ParseNumber(input)
+		if err != nil || got != want {
+			t.Errorf("ParseNumber(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	percents := map[string]float64{"5%": 5, "0.5": 0.5, "1,5": 1.5, "0,5%": 0.5}
+	for input, want := range percents {
+		got, err := ParsePercent(input)
+		if err != nil || got != want {
+			t.Errorf("ParsePercent(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	now := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	dates := map[string]string{
+		"":           "29.02.2024",
+		"31.12.2024": "31.12.2024",
+		"2024-12-31": "31.12.2024",
+		"2024-11":    "30.11.2024",
+		"03.2024":    "31.03.2024",
+		"45657":      "31.12.2024",
+	}
+	for input, want := range dates {
+		got, err := ParseMonthYear(input, now)
+		if err != nil || got != want {
+			t.Errorf("ParseMonthYear(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+
+	if _, err := ParseMonthYear("завтра", now); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+}
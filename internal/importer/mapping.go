@@ -0,0 +1,149 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Column — поле правила кэшбэка, в которое загружается колонка таблицы.
+type Column string
+
+// Поля правила кэшбэка.
+const (
+	ColumnBank      Column = "bank_name"
+	ColumnCategory  Column = "category"
+	ColumnPercent   Column = "cashback_percent"
+	ColumnMaxAmount Column = "max_amount"
+	ColumnMonthYear Column = "month_year"
+)
+
+// Columns — все поля в порядке проверки заголовков.
+// Лимит проверяется раньше процента: "Лимит кэшбэка" — это сумма, а не процент.
+var Columns = []Column{ColumnBank, ColumnCategory, ColumnMaxAmount, ColumnMonthYear, ColumnPercent}
+
+// RequiredColumns — поля, без которых импорт невозможен.
+var RequiredColumns = []Column{ColumnBank, ColumnCategory, ColumnPercent}
+
+// headerSynonyms — варианты заголовков колонок в выгрузках из Google Sheets и Excel.
+var headerSynonyms = map[Column][]string{
+	ColumnBank:      {"банк", "bank", "bank_name", "эмитент"},
+	ColumnCategory:  {"категория", "category", "категории"},
+	ColumnMaxAmount: {"лимит", "макс", "сумма", "max", "max_amount", "limit"},
+	ColumnMonthYear: {"месяц", "дата", "срок", "окончание", "действует", "month", "month_year", "date", "до"},
+	ColumnPercent:   {"процент", "кэшбэк", "кешбэк", "кэшбек", "cashback", "cashback_percent", "percent", "%"},
+}
+
+// Mapping — соответствие поля правила индексу колонки таблицы.
+type Mapping map[Column]int
+
+// DetectMapping определяет колонки по заголовку таблицы.
+// Короткие синонимы ("%", "до") должны совпадать с заголовком целиком,
+// длинные ищутся как подстрока.
+func DetectMapping(header []string) Mapping {
+	mapping := make(Mapping)
+
+	for index, cell := range header {
+		name := strings.ToLower(strings.TrimSpace(cell))
+		if name == "" {
+			continue
+		}
+
+		for _, column := range Columns {
+			if _, taken := mapping[column]; taken {
+				continue
+			}
+			if matchesHeader(name, headerSynonyms[column]) {
+				mapping[column] = index
+				break
+			}
+		}
+	}
+
+	return mapping
+}
+
+// matchesHeader проверяет совпадение заголовка с одним из синонимов.
+func matchesHeader(name string, synonyms []string) bool {
+	for _, synonym := range synonyms {
+		if len([]rune(synonym)) <= 2 {
+			if name == synonym {
+				return true
+			}
+			continue
+		}
+		if strings.Contains(name, synonym) {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyOverrides заменяет автоматически найденные колонки на указанные пользователем.
+// Ключ — поле правила, значение — заголовок колонки без учёта регистра.
+func (m Mapping) ApplyOverrides(header []string, overrides map[string]string) error {
+	for field, headerName := range overrides {
+		column := Column(field)
+		if !isKnownColumn(column) {
+			return fmt.Errorf("неизвестное поле %q", field)
+		}
+
+		index := findHeader(header, headerName)
+		if index < 0 {
+			return fmt.Errorf("колонка %q не найдена в заголовке", headerName)
+		}
+		m[column] = index
+	}
+	return nil
+}
+
+// Missing возвращает обязательные поля, для которых не найдены колонки.
+func (m Mapping) Missing() []Column {
+	var missing []Column
+	for _, column := range RequiredColumns {
+		if _, ok := m[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	return missing
+}
+
+// Headers возвращает соответствие поля правила заголовку колонки.
+func (m Mapping) Headers(header []string) map[string]string {
+	result := make(map[string]string, len(m))
+	for column, index := range m {
+		if index < len(header) {
+			result[string(column)] = strings.TrimSpace(header[index])
+		}
+	}
+	return result
+}
+
+// Value возвращает значение поля из строки таблицы или пустую строку.
+func (m Mapping) Value(row []string, column Column) string {
+	index, ok := m[column]
+	if !ok || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+// isKnownColumn проверяет, что поле поддерживается импортом.
+func isKnownColumn(column Column) bool {
+	for _, known := range Columns {
+		if known == column {
+			return true
+		}
+	}
+	return false
+}
+
+// findHeader ищет колонку по заголовку без учёта регистра.
+func findHeader(header []string, name string) int {
+	name = strings.TrimSpace(name)
+	for index, cell := range header {
+		if strings.EqualFold(strings.TrimSpace(cell), name) {
+			return index
+		}
+	}
+	return -1
+}
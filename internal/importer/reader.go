@@ -0,0 +1,367 @@
+// Package importer читает таблицы кэшбэков из CSV и XLSX файлов.
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Format определяет формат файла импорта.
+type Format string
+
+// Поддерживаемые форматы.
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// MaxRows — максимальное количество строк в файле импорта.
+const MaxRows = 5000
+
+// MaxColumns — максимальное количество колонок в файле импорта.
+const MaxColumns = 256
+
+// Ошибки чтения файлов.
+var (
+	ErrUnsupportedFormat = errors.New("неподдерживаемый формат файла, ожидается CSV или XLSX")
+	ErrEmptyFile         = errors.New("файл не содержит данных")
+	ErrTooManyRows       = fmt.Errorf("файл содержит больше %d строк", MaxRows)
+	ErrTooManyColumns    = fmt.Errorf("файл содержит больше %d колонок", MaxColumns)
+)
+
+// zipSignature — начало любого ZIP архива (XLSX — это ZIP).
+var zipSignature = []byte("PK\x03\x04")
+
+// DetectFormat определяет формат файла по расширению, а при его отсутствии — по содержимому.
+func DetectFormat(filename string, data []byte) (Format, error) {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv", ".txt":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	}
+
+	if bytes.HasPrefix(data, zipSignature) {
+		return FormatXLSX, nil
+	}
+	if utf8.Valid(data) {
+		return FormatCSV, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// ReadTable читает файл в таблицу строк. Первая строка — заголовок.
+// Пустые строки пропускаются.
+func ReadTable(filename string, data []byte) ([][]string, error) {
+	format, err := DetectFormat(filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var table [][]string
+	switch format {
+	case FormatCSV:
+		table, err = readCSV(data)
+	case FormatXLSX:
+		table, err = readXLSX(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table = dropEmptyRows(table)
+	if len(table) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if len(table) > MaxRows+1 {
+		return nil, ErrTooManyRows
+	}
+	return table, nil
+}
+
+// --- CSV ---
+
+// readCSV читает CSV с автоопределением разделителя (",", ";" или табуляция).
+// Google Sheets выгружает через запятую, Excel с русской локалью — через точку с запятой.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+
+	table, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("чтение CSV: %w", err)
+	}
+	return table, nil
+}
+
+// detectDelimiter выбирает самый частый разделитель в первой строке.
+func detectDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	best, bestCount := ',', 0
+	for _, candidate := range []rune{',', ';', '\t'} {
+		if count := bytes.Count(firstLine, []byte(string(candidate))); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+// --- XLSX ---
+
+// xlsxWorkbook — описание листов книги (xl/workbook.xml).
+type xlsxWorkbook struct {
+	Sheets []struct {
+		RelID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// xlsxRelationships — связи книги с файлами листов (xl/_rels/workbook.xml.rels).
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// xlsxSharedStrings — таблица общих строк (xl/sharedStrings.xml).
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+// xlsxRichText — строка, состоящая из простого текста или фрагментов форматирования.
+type xlsxRichText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+// String возвращает полный текст строки.
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var sb strings.Builder
+	for _, run := range t.Runs {
+		sb.WriteString(run.Text)
+	}
+	return sb.String()
+}
+
+// xlsxStyles — форматы ячеек (xl/styles.xml).
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+// percentStyles возвращает индексы стилей ячеек с процентным форматом.
+// Встроенные форматы 9 ("0%") и 10 ("0.00%") процентные всегда.
+func (s xlsxStyles) percentStyles() map[int]bool {
+	percentFormats := map[int]bool{9: true, 10: true}
+	for _, format := range s.NumFmts {
+		if strings.Contains(format.Code, "%") {
+			percentFormats[format.ID] = true
+		}
+	}
+
+	styles := make(map[int]bool)
+	for i, xf := range s.CellXfs {
+		if percentFormats[xf.NumFmtID] {
+			styles[i] = true
+		}
+	}
+	return styles
+}
+
+// xlsxSheet — данные листа (xl/worksheets/sheetN.xml).
+type xlsxSheet struct {
+	Rows []struct {
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Style  int          `xml:"s,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX читает первый лист книги XLSX средствами стандартной библиотеки.
+func readXLSX(data []byte) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("чтение XLSX: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	var shared xlsxSharedStrings
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(file, &shared); err != nil {
+			return nil, fmt.Errorf("чтение общих строк XLSX: %w", err)
+		}
+	}
+
+	var styles xlsxStyles
+	if file, ok := files["xl/styles.xml"]; ok {
+		if err := decodeZipXML(file, &styles); err != nil {
+			return nil, fmt.Errorf("чтение стилей XLSX: %w", err)
+		}
+	}
+	percentStyles := styles.percentStyles()
+
+	sheetFile, ok := files[firstSheetPath(files)]
+	if !ok {
+		return nil, fmt.Errorf("чтение XLSX: лист не найден")
+	}
+
+	var sheet xlsxSheet
+	if err := decodeZipXML(sheetFile, &sheet); err != nil {
+		return nil, fmt.Errorf("чтение листа XLSX: %w", err)
+	}
+
+	table := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var values []string
+		for i, cell := range row.Cells {
+			col, ok := columnIndex(cell.Ref)
+			if !ok {
+				col = i
+			}
+			if col >= MaxColumns {
+				return nil, fmt.Errorf("чтение листа XLSX: ячейка %q: %w", cell.Ref, ErrTooManyColumns)
+			}
+			for len(values) <= col {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err == nil && index >= 0 && index < len(shared.Items) {
+					values[col] = shared.Items[index].String()
+				}
+			case "inlineStr":
+				values[col] = cell.Inline.String()
+			default:
+				values[col] = cell.Value
+				// Excel хранит ячейку с процентным форматом как долю: 0.05 вместо 5%
+				if share, err := strconv.ParseFloat(cell.Value, 64); err == nil && percentStyles[cell.Style] {
+					percent := math.Round(share*100*1e6) / 1e6
+					values[col] = strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+				}
+			}
+		}
+
+		// Пустые строки отбрасываются сразу, чтобы лист из пустых строк
+		// не занимал память до проверки MaxRows
+		if len(dropEmptyRows([][]string{values})) == 0 {
+			continue
+		}
+		if len(table) > MaxRows {
+			return nil, ErrTooManyRows
+		}
+		table = append(table, values)
+	}
+
+	return table, nil
+}
+
+// firstSheetPath возвращает путь к первому листу книги.
+// Если структура книги нестандартная, используется xl/worksheets/sheet1.xml.
+func firstSheetPath(files map[string]*zip.File) string {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	workbookFile, ok1 := files["xl/workbook.xml"]
+	relsFile, ok2 := files["xl/_rels/workbook.xml.rels"]
+	if !ok1 || !ok2 || decodeZipXML(workbookFile, &workbook) != nil ||
+		decodeZipXML(relsFile, &rels) != nil || len(workbook.Sheets) == 0 {
+		return fallback
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RelID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/")
+		}
+		return path.Join("xl", rel.Target)
+	}
+	return fallback
+}
+
+// decodeZipXML декодирует XML файл из архива.
+func decodeZipXML(file *zip.File, v interface{}) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(io.LimitReader(rc, 50<<20)).Decode(v)
+}
+
+// columnIndex преобразует ссылку на ячейку ("C12") в индекс колонки (2).
+// Возвращает false, если в ссылке нет букв колонки. Индекс колонок дальше
+// MaxColumns не вычисляется, чтобы длинная ссылка не переполнила int.
+func columnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if index <= MaxColumns {
+			index = index*26 + int(r-'A'+1)
+		}
+		letters++
+	}
+	if letters == 0 {
+		return 0, false
+	}
+	return index - 1, true
+}
+
+// dropEmptyRows удаляет строки без значений.
+func dropEmptyRows(table [][]string) [][]string {
+	result := table[:0]
+	for _, row := range table {
+		for _, value := range row {
+			if strings.TrimSpace(value) != "" {
+				result = append(result, row)
+				break
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package importer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// excelEpoch — нулевая дата в числовом формате дат Excel.
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// excelSerialPattern — числовая дата Excel (пятизначная для дат после 1973 года).
+var excelSerialPattern = regexp.MustCompile(`^\d{5}(\.\d+)?$`)
+
+// amountReplacer убирает из чисел пробелы-разделители разрядов и знаки валют.
+var amountReplacer = strings.NewReplacer(
+	" ", "", "\u00a0", "", "\u202f", "",
+	"₽", "", "руб.", "", "руб", "", "р.", "", "р", "",
+	"%", "",
+)
+
+// ParseNumber разбирает число из ячейки: "3 000 ₽", "1,5", "5%".
+func ParseNumber(value string) (float64, error) {
+	cleaned := amountReplacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+	cleaned = strings.Replace(cleaned, ",", ".", 1)
+	if cleaned == "" {
+		return 0, fmt.Errorf("пустое значение")
+	}
+
+	number, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q не является числом", value)
+	}
+	return number, nil
+}
+
+// ParsePercent разбирает процент кэшбэка: "5", "5%", "0,5".
+// Число без знака процента считается процентом как есть, поэтому "0.5" —
+// это 0,5%. Ячейки XLSX с процентным форматом, где Excel хранит долю,
+// readXLSX уже переводит в проценты.
+func ParsePercent(value string) (float64, error) {
+	return ParseNumber(value)
+}
+
+// ParseMonthYear приводит дату окончания к формату "02.01.2006".
+// Поддерживаются "31.12.2024", "2024-12-31", "2024-12", "12.2024"
+// и числовые даты Excel. Пустое значение — последний день текущего месяца.
+func ParseMonthYear(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return lastDayOfMonth(now).Format("02.01.2006"), nil
+	}
+
+	if excelSerialPattern.MatchString(value) {
+		serial, _ := strconv.ParseFloat(value, 64)
+		date := excelEpoch.AddDate(0, 0, int(serial))
+		return date.Format("02.01.2006"), nil
+	}
+
+	for _, layout := range []string{"02.01.2006", "2006-01-02", "2.1.2006", "02/01/2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date.Format("02.01.2006"), nil
+		}
+	}
+
+	for _, layout := range []string{"2006-01", "01.2006", "1.2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return lastDayOfMonth(date).Format("02.01.2006"), nil
+		}
+	}
+
+	return "", fmt.Errorf("неверный формат даты %q", value)
+}
+
+// lastDayOfMonth возвращает последний день месяца указанной даты.
+func lastDayOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// ActivationRemindDays — за сколько дней до срока активации напомнить владельцу правила.
+const ActivationRemindDays = 3
+
+// RuleActivation описывает активацию категории в приложении банка:
+// некоторые банки не начисляют кэшбэк по выбранной категории без неё.
+type RuleActivation struct {
+	Required    bool       `json:"required"`
+	Deadline    *time.Time `json:"deadline,omitempty"`     // последний день активации
+	ActivatedAt *time.Time `json:"activated_at,omitempty"` // когда владелец отметил активацию
+}
+
+// Pending сообщает, что категорию нужно активировать, а она ещё не активирована.
+func (a RuleActivation) Pending() bool {
+	return a.Required && a.ActivatedAt == nil
+}
+
+// ActivationRequest отмечает в запросе на создание или замену правила,
+// что категорию нужно активировать в приложении банка.
+type ActivationRequest struct {
+	Deadline string `json:"deadline,omitempty"` // дд.мм.гггг; пусто — срок не указан
+}
+
+// ActivateCashbackRequest представляет отметку владельца об активации категории
+type ActivateCashbackRequest struct {
+	UserID string `json:"user_id"`
+}
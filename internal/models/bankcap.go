@@ -0,0 +1,89 @@
+package models
+
+import "time"
+
+// Периоды общего лимита кэшбэка банка
+const (
+	BankCapPeriodMonth   = "month"
+	BankCapPeriodQuarter = "quarter"
+)
+
+// BankCapWarnShare — доля лимита, после которой лимит считается почти исчерпанным.
+const BankCapWarnShare = 0.8
+
+// BankCap представляет общий лимит кэшбэка банка на все категории за период.
+// Действует отдельно от max_amount отдельных правил.
+type BankCap struct {
+	UserID    string    `json:"user_id"`
+	BankName  string    `json:"bank_name"`
+	Period    string    `json:"period"` // month, quarter
+	Amount    float64   `json:"amount"` // рубли
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BankCapRequest представляет запрос на установку общего лимита банка
+type BankCapRequest struct {
+	Amount float64 `json:"amount"`
+	Period string  `json:"period,omitempty"` // по умолчанию month
+}
+
+// BankCapUsage представляет лимит банка и кэшбэк, заработанный за текущий период
+type BankCapUsage struct {
+	BankCap
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"` // не включая
+	Earned      float64   `json:"earned"`
+	Remaining   float64   `json:"remaining"`
+}
+
+// Exhausted сообщает, что лимит банка за период выбран полностью.
+func (u *BankCapUsage) Exhausted() bool {
+	return u != nil && u.Remaining <= 0
+}
+
+// NearlyUsed сообщает, что выбрано не меньше BankCapWarnShare лимита.
+func (u *BankCapUsage) NearlyUsed() bool {
+	return u != nil && u.Earned >= u.Amount*BankCapWarnShare
+}
+
+// Spend представляет покупку по карте участника в журнале трат
+type Spend struct {
+	ID               int64     `json:"id"`
+	GroupName        string    `json:"group_name"`
+	UserID           string    `json:"user_id"` // владелец карты
+	BankName         string    `json:"bank_name"`
+	Category         string    `json:"category"`
+	RuleID           *int64    `json:"rule_id,omitempty"`
+	PaymentRequestID *int64    `json:"payment_request_id,omitempty"` // оплата по просьбе другого участника
+	Amount           float64   `json:"amount"`
+	Cashback         float64   `json:"cashback"` // рубли, с учётом лимитов
+	SpentAt          time.Time `json:"spent_at"`
+}
+
+// EarnedCashback представляет кэшбэк участника за месяц по журналу трат.
+// Банк и категория пусты, если кэшбэк сведён по участнику.
+type EarnedCashback struct {
+	Month           time.Time `json:"month"` // первое число месяца
+	UserID          string    `json:"user_id"`
+	UserDisplayName string    `json:"user_display_name"`
+	BankName        string    `json:"bank_name,omitempty"`
+	Category        string    `json:"category,omitempty"`
+	Spends          int       `json:"spends"`   // число покупок
+	Cashback        float64   `json:"cashback"` // рубли
+}
+
+// CreateSpendRequest представляет запрос на запись покупки по своей карте.
+// Без rule_id правило выбирается как лучший кэшбэк пользователя на категорию.
+type CreateSpendRequest struct {
+	GroupName string  `json:"group_name"`
+	UserID    string  `json:"user_id"`
+	Category  string  `json:"category,omitempty"`
+	RuleID    int64   `json:"rule_id,omitempty"`
+	Amount    float64 `json:"amount"`
+}
+
+// SpendResponse представляет записанную покупку и лимит банка после неё
+type SpendResponse struct {
+	Spend
+	BankCap *BankCapUsage `json:"bank_cap,omitempty"`
+}
@@ -0,0 +1,57 @@
+package models
+
+// Операции пакетного запроса
+const (
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+)
+
+// Режимы пакетного запроса
+const (
+	BatchModeAtomic     = "atomic"      // всё или ничего
+	BatchModeBestEffort = "best_effort" // успешные операции сохраняются, ошибочные пропускаются
+)
+
+// Статусы операций пакетного запроса
+const (
+	BatchStatusOK         = "ok"
+	BatchStatusError      = "error"
+	BatchStatusRolledBack = "rolled_back" // выполнена, но отменена из-за ошибки другой операции
+	BatchStatusSkipped    = "skipped"     // не выполнялась после ошибки в режиме atomic
+)
+
+// BatchOperation представляет одну операцию пакетного запроса
+type BatchOperation struct {
+	Op     string                 `json:"op"`
+	ID     int64                  `json:"id,omitempty"`     // для update и delete
+	Create *CreateCashbackRequest `json:"create,omitempty"` // для create
+	Update *UpdateCashbackRequest `json:"update,omitempty"` // для update
+}
+
+// BatchRequest представляет пакетный запрос на изменение правил
+type BatchRequest struct {
+	Mode       string           `json:"mode"` // atomic (по умолчанию) или best_effort
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResult представляет результат одной операции
+type BatchResult struct {
+	Index     int           `json:"index"`
+	Op        string        `json:"op"`
+	Status    string        `json:"status"`
+	ID        int64         `json:"id,omitempty"`
+	Rule      *CashbackRule `json:"rule,omitempty"`
+	Error     string        `json:"error,omitempty"`
+	Existing  *CashbackRule `json:"existing,omitempty"` // конфликтующее правило при create
+	Identical bool          `json:"identical,omitempty"`
+}
+
+// BatchResponse представляет результат пакетного запроса
+type BatchResponse struct {
+	Mode      string        `json:"mode"`
+	Committed bool          `json:"committed"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Results   []BatchResult `json:"results"`
+}
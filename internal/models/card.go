@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Платёжные системы карт
+const (
+	PaymentSystemMir        = "mir"
+	PaymentSystemVisa       = "visa"
+	PaymentSystemMastercard = "mastercard"
+	PaymentSystemUnionPay   = "unionpay"
+)
+
+// Card представляет банковскую карту пользователя
+type Card struct {
+	ID            int64     `json:"id"`
+	UserID        string    `json:"user_id"`
+	BankName      string    `json:"bank_name"`
+	Nickname      string    `json:"nickname,omitempty"` // например, "Зарплатная"
+	Last4         string    `json:"last4"`
+	PaymentSystem string    `json:"payment_system"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// CreateCardRequest представляет запрос на добавление карты
+type CreateCardRequest struct {
+	UserID        string `json:"user_id"`
+	BankName      string `json:"bank_name"`
+	Nickname      string `json:"nickname,omitempty"`
+	Last4         string `json:"last4"`
+	PaymentSystem string `json:"payment_system"`
+}
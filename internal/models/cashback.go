@@ -6,40 +6,62 @@ import (
 
 // CashbackRule представляет правило кэшбэка
 type CashbackRule struct {
-	ID              int64     `json:"id"`
-	GroupName       string    `json:"group_name"`
-	Category        string    `json:"category"`
-	BankName        string    `json:"bank_name"`
-	UserID          string    `json:"user_id"`
-	UserDisplayName string    `json:"user_display_name"`
-	MonthYear       time.Time `json:"month_year"`
-	CashbackPercent float64   `json:"cashback_percent"`
-	MaxAmount       float64   `json:"max_amount"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                int64          `json:"id"`
+	GroupName         string         `json:"group_name"`
+	Category          string         `json:"category"`
+	BankName          string         `json:"bank_name"`
+	UserID            string         `json:"user_id"`
+	UserDisplayName   string         `json:"user_display_name"`
+	MonthYear         time.Time      `json:"month_year"`
+	CashbackPercent   float64        `json:"cashback_percent"`
+	MaxAmount         float64        `json:"max_amount"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	RewardProgram     string         `json:"reward_program"` // код программы вознаграждения
+	RewardProgramName string         `json:"reward_program_name"`
+	EffectivePercent  float64        `json:"effective_percent"` // процент в рублях по курсу программы
+	Conditions        RuleConditions `json:"conditions"`
+	Merchant          string         `json:"merchant,omitempty"` // магазин партнёрского предложения
+	Card              *Card          `json:"card,omitempty"`     // карта, на которую действует правило
+	Activation        RuleActivation `json:"activation"`
+	BankCap           *BankCapUsage  `json:"bank_cap,omitempty"` // общий лимит банка владельца; только в поиске лучшего
 }
 
 // CreateCashbackRequest представляет запрос на создание правила
 type CreateCashbackRequest struct {
-	GroupName       string  `json:"group_name"`
-	Category        string  `json:"category"`
-	BankName        string  `json:"bank_name"`
-	UserID          string  `json:"user_id"`
-	UserDisplayName string  `json:"user_display_name"`
-	MonthYear       string  `json:"month_year"`
-	CashbackPercent float64 `json:"cashback_percent"`
-	MaxAmount       float64 `json:"max_amount"`
-	Force           bool    `json:"force,omitempty"`
+	GroupName       string             `json:"group_name"`
+	Category        string             `json:"category"`
+	BankName        string             `json:"bank_name"`
+	UserID          string             `json:"user_id"`
+	UserDisplayName string             `json:"user_display_name"`
+	MonthYear       string             `json:"month_year"`
+	CashbackPercent float64            `json:"cashback_percent"`
+	MaxAmount       float64            `json:"max_amount"`
+	RewardProgram   string             `json:"reward_program,omitempty"` // по умолчанию rub
+	Conditions      *RuleConditions    `json:"conditions,omitempty"`
+	Merchant        string             `json:"merchant,omitempty"` // категория по умолчанию — категория магазина
+	CardID          *int64             `json:"card_id,omitempty"`
+	CardLast4       string             `json:"card_last4,omitempty"` // карта банка правила по последним цифрам
+	Activation      *ActivationRequest `json:"activation,omitempty"` // категорию нужно активировать в приложении банка
+	Force           bool               `json:"force,omitempty"`
 }
 
 // UpdateCashbackRequest представляет запрос на обновление правила
 type UpdateCashbackRequest struct {
-	GroupName       string  `json:"group_name"`
-	Category        string  `json:"category"`
-	BankName        string  `json:"bank_name"`
-	MonthYear       string  `json:"month_year"`
-	CashbackPercent float64 `json:"cashback_percent"`
-	MaxAmount       float64 `json:"max_amount"`
+	GroupName       string             `json:"group_name"`
+	Category        string             `json:"category"`
+	BankName        string             `json:"bank_name"`
+	MonthYear       string             `json:"month_year"`
+	CashbackPercent float64            `json:"cashback_percent"`
+	MaxAmount       float64            `json:"max_amount"`
+	RewardProgram   string             `json:"reward_program,omitempty"`
+	Conditions      *RuleConditions    `json:"conditions,omitempty"` // пустой объект снимает условия
+	Merchant        string             `json:"merchant,omitempty"`
+	CardID          *int64             `json:"card_id,omitempty"`
+	Activation      *ActivationRequest `json:"activation,omitempty"` // заново требует активации, сбрасывает отметку
+	// Replace заменяет параметры правила целиком: нулевой лимит снимает
+	// ограничение, пустые программа, условия и активация сбрасываются
+	Replace bool `json:"replace,omitempty"`
 }
 
 // SuggestRequest представляет запрос на анализ данных
@@ -77,9 +99,11 @@ type Suggestions struct {
 
 // BestCashbackRequest представляет запрос на получение лучшего кэшбэка
 type BestCashbackRequest struct {
-	GroupName string `json:"group_name"`
-	Category  string `json:"category"`
-	MonthYear string `json:"month_year"`
+	GroupName string          `json:"group_name"`
+	Category  string          `json:"category"`
+	MonthYear string          `json:"month_year"`
+	Purchase  PurchaseContext `json:"purchase"` // правила с неподходящими условиями не учитываются
+	UserID    string          `json:"user_id,omitempty"` // кто спрашивает: для него ищется лучший кэшбэк по его картам
 }
 
 // ListCashbackRequest представляет запрос на получение списка правил
@@ -104,6 +128,14 @@ type ErrorResponse struct {
 	Details []string `json:"details,omitempty"`
 }
 
+// ConflictResponse представляет ответ 409 при создании дубликата правила
+type ConflictResponse struct {
+	Error     string        `json:"error"`
+	Details   []string      `json:"details,omitempty"`
+	Existing  *CashbackRule `json:"existing"`
+	Identical bool          `json:"identical"` // совпадают также процент и лимит
+}
+
 // UserInfo представляет информацию о пользователе
 type UserInfo struct {
 	UserID          string `json:"user_id"`
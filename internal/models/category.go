@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// AllPurchasesCategory — корень дерева категорий: кэшбэк на любые покупки.
+const AllPurchasesCategory = "Все покупки"
+
+// Category представляет узел дерева категорий
+type Category struct {
+	Name      string    `json:"name"`
+	Parent    string    `json:"parent,omitempty"` // пусто — корень
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CategoryRequest представляет запрос на добавление категории или перенос её в дереве
+type CategoryRequest struct {
+	Parent string `json:"parent"`
+}
+
+// CategoryMatch объясняет, на каком уровне дерева найден кэшбэк
+type CategoryMatch struct {
+	RequestedCategory string   `json:"requested_category"`
+	Category          string   `json:"category"` // категория найденного правила
+	Level             int      `json:"level"`    // 0 — сама категория, 1 — её родитель и т.д.
+	Path              []string `json:"path"`     // от запрошенной категории до корня
+}
+
+// IsFallback сообщает, что кэшбэк найден не на запрошенной категории, а выше по дереву.
+func (m CategoryMatch) IsFallback() bool {
+	return m.Level > 0
+}
+
+// BestCashbackResponse представляет лучший кэшбэк с уровнем, на котором он найден.
+// Поля правила остаются на верхнем уровне JSON.
+type BestCashbackResponse struct {
+	CashbackRule
+	Match    CategoryMatch         `json:"match"`
+	Personal *PersonalBestCashback `json:"personal,omitempty"` // nil — user_id не передан или у пользователя нет кэшбэка
+}
+
+// PersonalBestCashback представляет лучший кэшбэк по картам того, кто спрашивает.
+// Лучший кэшбэк группы может быть у участника, которого нет рядом при оплате.
+type PersonalBestCashback struct {
+	CashbackRule
+	Match CategoryMatch `json:"match"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// ChatBinding представляет привязку группового Telegram чата к группе кэшбэков
+type ChatBinding struct {
+	ChatID    int64     `json:"chat_id"`
+	GroupName string    `json:"group_name"`
+	BoundBy   string    `json:"bound_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BindChatRequest представляет запрос на привязку чата к группе
+type BindChatRequest struct {
+	GroupName string `json:"group_name"`
+	UserID    string `json:"user_id"`
+}
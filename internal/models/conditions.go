@@ -0,0 +1,68 @@
+package models
+
+// Способы оплаты в условиях правила.
+const (
+	PaymentMethodCard = "card"
+	PaymentMethodSBP  = "sbp"
+	PaymentMethodQR   = "qr"
+)
+
+// RuleConditions описывает условия, при которых действует правило.
+// Нулевые значения означают отсутствие ограничения.
+type RuleConditions struct {
+	MinPurchase     float64  `json:"min_purchase,omitempty"`      // минимальная сумма покупки, ₽
+	MinMonthlySpend float64  `json:"min_monthly_spend,omitempty"` // минимальные траты по карте за месяц, ₽
+	Weekdays        []int    `json:"weekdays,omitempty"`          // дни недели: 1 — понедельник … 7 — воскресенье
+	PaymentMethods  []string `json:"payment_methods,omitempty"`   // card, sbp, qr
+}
+
+// IsEmpty сообщает, что у правила нет условий.
+func (c RuleConditions) IsEmpty() bool {
+	return c.MinPurchase == 0 && c.MinMonthlySpend == 0 &&
+		len(c.Weekdays) == 0 && len(c.PaymentMethods) == 0
+}
+
+// Allows проверяет, подходит ли покупка под условия правила.
+// Не указанные в контексте параметры покупки условие не нарушают.
+func (c RuleConditions) Allows(p PurchaseContext) bool {
+	if c.MinPurchase > 0 && p.Amount > 0 && p.Amount < c.MinPurchase {
+		return false
+	}
+	if c.MinMonthlySpend > 0 && p.MonthlySpend > 0 && p.MonthlySpend < c.MinMonthlySpend {
+		return false
+	}
+	if len(c.Weekdays) > 0 && p.Weekday != 0 && !containsInt(c.Weekdays, p.Weekday) {
+		return false
+	}
+	if len(c.PaymentMethods) > 0 && p.PaymentMethod != "" && !containsString(c.PaymentMethods, p.PaymentMethod) {
+		return false
+	}
+	return true
+}
+
+// PurchaseContext описывает планируемую покупку для подбора кэшбэка.
+// Нулевые значения означают, что параметр неизвестен.
+type PurchaseContext struct {
+	Amount        float64 `json:"amount,omitempty"`         // сумма покупки, ₽
+	MonthlySpend  float64 `json:"monthly_spend,omitempty"`  // траты по карте за месяц, ₽
+	Weekday       int     `json:"weekday,omitempty"`        // 1 — понедельник … 7 — воскресенье
+	PaymentMethod string  `json:"payment_method,omitempty"` // card, sbp, qr
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Статусы пробела в покрытии категорий группы.
+const (
+	CoverageMissing = "missing" // на категорию нет ни одного действующего правила
+	CoverageWeak    = "weak"    // правила есть, но процент ниже порога
+)
+
+// Почему категория считается важной для группы.
+const (
+	CoverageSourceDictionary = "dictionary" // категория из дерева категорий
+	CoverageSourceHistory    = "history"    // у группы раньше был кэшбэк на категорию
+)
+
+// CoverageRequest представляет запрос отчёта о покрытии категорий группы
+type CoverageRequest struct {
+	GroupName  string  `json:"group_name"`
+	MonthYear  string  `json:"month_year"`
+	MinPercent float64 `json:"min_percent"` // 0 — порог по умолчанию
+}
+
+// CoverageGap представляет важную категорию без выгодного кэшбэка
+type CoverageGap struct {
+	Category      string        `json:"category"`
+	Status        string        `json:"status"` // missing или weak
+	Source        string        `json:"source"` // dictionary или history
+	HistoryMonths int           `json:"history_months,omitempty"`
+	BestRule      *CashbackRule `json:"best_rule"`               // лучший доступный кэшбэк, в том числе на родительской категории; nil — нет никакого
+	BestCategory  string        `json:"best_category,omitempty"` // категория лучшего правила
+}
+
+// CoverageReport представляет отчёт о пробелах в покрытии категорий группы
+type CoverageReport struct {
+	GroupName  string        `json:"group_name"`
+	MonthYear  time.Time     `json:"month_year"`
+	MinPercent float64       `json:"min_percent"`
+	Checked    int           `json:"checked"` // сколько важных категорий проверено
+	Gaps       []CoverageGap `json:"gaps"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Расписание сводки по умолчанию: последний день месяца, 19:00.
+const (
+	DigestDefaultDay    = 0 // 0 — последний день месяца
+	DigestDefaultHour   = 19
+	DigestDefaultMinute = 0
+)
+
+// DigestSettings представляет расписание ежемесячной сводки группы
+type DigestSettings struct {
+	GroupName     string     `json:"group_name"`
+	Day           int        `json:"day"` // 1-28; 0 — последний день месяца
+	Hour          int        `json:"hour"`
+	Minute        int        `json:"minute"`
+	Enabled       bool       `json:"enabled"`
+	LastSentMonth *time.Time `json:"last_sent_month,omitempty"` // первое число месяца последней сводки
+}
+
+// DigestSettingsRequest представляет запрос на изменение расписания сводки
+type DigestSettingsRequest struct {
+	Day     int    `json:"day"`
+	Time    string `json:"time"` // ЧЧ:ММ
+	Enabled bool   `json:"enabled"`
+}
+
+// DigestClaimRequest представляет запрос на отправку сводки за месяц
+type DigestClaimRequest struct {
+	MonthYear string `json:"month_year"`
+}
+
+// DigestDelivery представляет сводку группы, которую пора отправить
+type DigestDelivery struct {
+	GroupName string    `json:"group_name"`
+	Month     time.Time `json:"month"`    // первое число месяца сводки
+	ChatIDs   []int64   `json:"chat_ids"` // привязанные чаты группы; пусто — отправлять участникам лично
+	Members   []string  `json:"members"`
+}
+
+// GroupDigest представляет итоги месяца для группы
+type GroupDigest struct {
+	GroupName string           `json:"group_name"`
+	Month     time.Time        `json:"month"`            // первое число месяца сводки
+	NextMonth time.Time        `json:"next_month"`       // первое число следующего месяца
+	Expired   []CashbackRule   `json:"expired"`          // правила, которые заканчиваются в месяце сводки
+	Best      []CashbackRule   `json:"best"`             // лучшее правило на каждую категорию следующего месяца
+	Gaps      []CoverageGap    `json:"gaps"`             // категории без выгодного кэшбэка в следующем месяце
+	Earned    []EarnedCashback `json:"earned,omitempty"` // кэшбэк по покупкам участников в месяце сводки
+}
@@ -0,0 +1,42 @@
+package models
+
+// Статусы строк импорта
+const (
+	ImportRowValid     = "valid"
+	ImportRowDuplicate = "duplicate"
+	ImportRowError     = "error"
+	ImportRowCreated   = "created"
+)
+
+// ImportRequest представляет запрос на импорт правил из CSV/XLSX файла
+type ImportRequest struct {
+	GroupName       string            `json:"group_name"`
+	UserID          string            `json:"user_id"`
+	UserDisplayName string            `json:"user_display_name"`
+	FileName        string            `json:"file_name"`
+	Data            []byte            `json:"-"`
+	DryRun          bool              `json:"dry_run"`
+	Mapping         map[string]string `json:"mapping,omitempty"` // поле правила → заголовок колонки
+}
+
+// ImportRow представляет результат обработки одной строки файла
+type ImportRow struct {
+	Row    int                    `json:"row"` // номер строки в файле, начиная с 1
+	Status string                 `json:"status"`
+	Errors []string               `json:"errors,omitempty"`
+	Rule   *CreateCashbackRequest `json:"rule,omitempty"`
+	ID     int64                  `json:"id,omitempty"`
+}
+
+// ImportReport представляет результат импорта или его предпросмотра
+type ImportReport struct {
+	DryRun        bool              `json:"dry_run"`
+	Headers       []string          `json:"headers"`
+	Mapping       map[string]string `json:"mapping"` // поле правила → заголовок колонки
+	TotalRows     int               `json:"total_rows"`
+	ValidRows     int               `json:"valid_rows"`
+	DuplicateRows int               `json:"duplicate_rows"`
+	ErrorRows     int               `json:"error_rows"`
+	Created       int               `json:"created"`
+	Rows          []ImportRow       `json:"rows"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Уровни, на которых найден лучший кэшбэк для магазина.
+const (
+	MatchMerchant     = "merchant"      // предложение самого магазина
+	MatchCategory     = "category"      // кэшбэк на категорию магазина
+	MatchAllPurchases = "all_purchases" // кэшбэк на "Все покупки"
+)
+
+// Merchant представляет магазин из справочника
+type Merchant struct {
+	Name      string    `json:"name"`
+	Category  string    `json:"category"`
+	Aliases   []string  `json:"aliases"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MerchantRequest представляет запрос на добавление или изменение магазина
+type MerchantRequest struct {
+	Category string   `json:"category"`
+	Aliases  []string `json:"aliases,omitempty"`
+}
+
+// MerchantBestRequest представляет запрос на лучший кэшбэк в магазине
+type MerchantBestRequest struct {
+	GroupName string          `json:"group_name"`
+	Merchant  string          `json:"merchant"`
+	MonthYear string          `json:"month_year"`
+	Purchase  PurchaseContext `json:"purchase"`
+}
+
+// MerchantBestResponse представляет лучший кэшбэк в магазине
+type MerchantBestResponse struct {
+	Merchant  Merchant      `json:"merchant"`
+	Rule      *CashbackRule `json:"rule"`       // nil, если кэшбэка нет ни на одном уровне
+	MatchedBy string        `json:"matched_by"` // merchant, category или all_purchases
+}
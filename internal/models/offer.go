@@ -0,0 +1,72 @@
+package models
+
+import "time"
+
+// Источники весов категорий для советника.
+const (
+	WeightSourceSpending = "spending" // траты группы из запроса, ₽ в месяц
+	WeightSourceHistory  = "history"  // как часто группа выбирала категорию раньше
+)
+
+// OfferMenuOption представляет категорию из меню банка на месяц
+type OfferMenuOption struct {
+	Category        string  `json:"category"`
+	CashbackPercent float64 `json:"cashback_percent"`
+	MaxAmount       float64 `json:"max_amount,omitempty"` // 0 — без лимита
+}
+
+// OfferMenu представляет меню категорий банка на месяц:
+// каждый клиент выбирает PickCount категорий из Options.
+type OfferMenu struct {
+	BankName  string            `json:"bank_name"`
+	MonthYear time.Time         `json:"month_year"` // первое число месяца
+	PickCount int               `json:"pick_count"`
+	Options   []OfferMenuOption `json:"options"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// OfferMenuRequest представляет запрос на сохранение меню категорий банка
+type OfferMenuRequest struct {
+	MonthYear string            `json:"month_year"`
+	PickCount int               `json:"pick_count"`
+	Options   []OfferMenuOption `json:"options"`
+}
+
+// CategoryAdviceRequest представляет запрос совета по выбору категорий
+type CategoryAdviceRequest struct {
+	GroupName string             `json:"-"` // из пути запроса
+	MonthYear string             `json:"month_year"`
+	Spending  map[string]float64 `json:"spending,omitempty"` // категория → траты в месяц, ₽
+}
+
+// CategoryPick представляет рекомендованную категорию из меню банка
+type CategoryPick struct {
+	Category        string  `json:"category"`
+	CashbackPercent float64 `json:"cashback_percent"`
+	MaxAmount       float64 `json:"max_amount,omitempty"`
+	CurrentPercent  float64 `json:"current_percent"` // лучший процент группы на категорию без этого выбора
+	ExpectedGain    float64 `json:"expected_gain"`   // прирост выгоды группы
+	NewCoverage     bool    `json:"new_coverage"`    // категорию в группе ещё никто не покрывает
+}
+
+// MemberAdvice представляет совет участнику по меню одного банка
+type MemberAdvice struct {
+	UserID          string         `json:"user_id"`
+	UserDisplayName string         `json:"user_display_name,omitempty"`
+	BankName        string         `json:"bank_name"`
+	PickCount       int            `json:"pick_count"`
+	AlreadyChosen   []string       `json:"already_chosen,omitempty"` // категории меню, уже добавленные участником
+	Picks           []CategoryPick `json:"picks"`
+}
+
+// CategoryAdviceResponse представляет совет группе по выбору категорий на месяц.
+// ExpectedGain измеряется в рублях при весах из трат и в условных единицах
+// при весах из истории.
+type CategoryAdviceResponse struct {
+	GroupName    string         `json:"group_name"`
+	MonthYear    time.Time      `json:"month_year"`
+	WeightSource string         `json:"weight_source"`
+	Advice       []MemberAdvice `json:"advice"`
+	TotalGain    float64        `json:"total_gain"`
+}
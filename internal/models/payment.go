@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Статусы просьбы оплатить покупку
+const (
+	PaymentStatusPending  = "pending"  // ждёт ответа владельца карты
+	PaymentStatusAccepted = "accepted" // оплачено, долг учтён в балансе
+	PaymentStatusDeclined = "declined"
+	PaymentStatusSettled  = "settled" // деньги возвращены
+)
+
+// PaymentRequest представляет просьбу оплатить покупку картой другого участника.
+// После оплаты Requester должен вернуть Amount участнику Payer.
+type PaymentRequest struct {
+	ID            int64      `json:"id"`
+	GroupName     string     `json:"group_name"`
+	RuleID        *int64     `json:"rule_id,omitempty"`
+	RequesterID   string     `json:"requester_id"`
+	RequesterName string     `json:"requester_name"`
+	PayerID       string     `json:"payer_id"`
+	PayerName     string     `json:"payer_name"`
+	BankName      string     `json:"bank_name"`
+	Category      string     `json:"category"`
+	Amount        float64    `json:"amount"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	SettledAt     *time.Time `json:"settled_at,omitempty"`
+}
+
+// CreatePaymentRequest представляет просьбу оплатить покупку по правилу кэшбэка.
+// Владелец карты и категория берутся из правила.
+type CreatePaymentRequest struct {
+	RuleID        int64   `json:"rule_id"`
+	RequesterID   string  `json:"requester_id"`
+	RequesterName string  `json:"requester_name"`
+	Amount        float64 `json:"amount"`
+}
+
+// PaymentAnswerRequest представляет ответ владельца карты на просьбу оплатить
+type PaymentAnswerRequest struct {
+	UserID string `json:"user_id"`
+	Accept bool   `json:"accept"`
+}
+
+// SettleDebtRequest представляет подтверждение возврата долга.
+// Подтверждает тот, кому должны.
+type SettleDebtRequest struct {
+	UserID        string  `json:"user_id"`
+	DebtorID      string  `json:"debtor_id"`
+	LastPaymentID int64   `json:"last_payment_id"` // последняя оплата в показанном долге
+	Amount        float64 `json:"amount"`          // показанная сумма долга
+}
+
+// Debt представляет итоговый долг одного участника другому после взаимозачёта
+type Debt struct {
+	DebtorID      string  `json:"debtor_id"`
+	DebtorName    string  `json:"debtor_name"`
+	CreditorID    string  `json:"creditor_id"`
+	CreditorName  string  `json:"creditor_name"`
+	Amount        float64 `json:"amount"`
+	Payments      int     `json:"payments"`        // сколько оплат вошло в долг
+	LastPaymentID int64   `json:"last_payment_id"` // самая поздняя оплата, вошедшая в долг
+}
+
+// GroupBalances представляет текущие долги внутри группы
+type GroupBalances struct {
+	GroupName string `json:"group_name"`
+	Debts     []Debt `json:"debts"`
+}
+
+// SettleDebtResponse представляет результат подтверждения возврата долга
+type SettleDebtResponse struct {
+	Settled int     `json:"settled"` // закрыто оплат
+	Amount  float64 `json:"amount"`  // закрытый долг
+}
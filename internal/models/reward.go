@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Виды вознаграждения.
+const (
+	RewardKindRubles = "rubles"
+	RewardKindPoints = "points"
+	RewardKindMiles  = "miles"
+)
+
+// DefaultRewardProgram — программа правил, для которых программа не указана.
+const DefaultRewardProgram = "rub"
+
+// RewardProgram представляет программу вознаграждения и её курс к рублю
+type RewardProgram struct {
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"`
+	RubleRate float64   `json:"ruble_rate"` // стоимость одной единицы в рублях
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RewardProgramRequest представляет запрос на создание программы или изменение курса
+type RewardProgramRequest struct {
+	Name      string  `json:"name"`
+	Kind      string  `json:"kind"`
+	RubleRate float64 `json:"ruble_rate"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// StatsRequest представляет запрос статистики группы за период.
+// Пустые From и To — последние полгода по текущий месяц.
+type StatsRequest struct {
+	GroupName string `json:"group_name"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+// StatsBucket представляет сводку по правилам одного участника, банка или категории.
+// EstimatedCashback — потолок кэшбэка в рублях: сумма лимитов правил с учётом
+// курса программы вознаграждения, а не заработанный кэшбэк. Заработанный
+// кэшбэк по журналу трат — EarnedCashback; он считается для итога, участников,
+// банков и категорий.
+type StatsBucket struct {
+	Key               string  `json:"key"`
+	Label             string  `json:"label,omitempty"` // имя участника
+	Rules             int     `json:"rules"`
+	AvgPercent        float64 `json:"avg_percent"` // средний эффективный процент
+	MaxPercent        float64 `json:"max_percent"`
+	EstimatedCashback float64 `json:"estimated_cashback"`
+	EarnedCashback    float64 `json:"earned_cashback,omitempty"`
+}
+
+// MonthStats представляет сводку за месяц и изменение к предыдущему месяцу
+type MonthStats struct {
+	Month             time.Time `json:"month"` // первое число месяца
+	Rules             int       `json:"rules"`
+	AvgPercent        float64   `json:"avg_percent"`
+	MaxPercent        float64   `json:"max_percent"`
+	EstimatedCashback float64   `json:"estimated_cashback"`
+	EarnedCashback    float64   `json:"earned_cashback"` // по журналу трат за месяц
+	RulesChange       int       `json:"rules_change"`
+	AvgPercentChange  float64   `json:"avg_percent_change"`
+	EstimatedChange   float64   `json:"estimated_change"`
+}
+
+// PercentRange представляет число правил с эффективным процентом в диапазоне [From, To)
+type PercentRange struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to,omitempty"` // 0 — без верхней границы
+	Rules int     `json:"rules"`
+}
+
+// GroupStats представляет аналитику кэшбэка группы за период
+type GroupStats struct {
+	GroupName    string         `json:"group_name"`
+	From         time.Time      `json:"from"`
+	To           time.Time      `json:"to"`
+	Total        StatsBucket    `json:"total"`
+	Months       []MonthStats   `json:"months"`
+	ByMember     []StatsBucket  `json:"by_member"`
+	ByBank       []StatsBucket  `json:"by_bank"`
+	ByCategory   []StatsBucket  `json:"by_category"`
+	Distribution []PercentRange `json:"distribution"`
+}
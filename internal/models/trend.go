@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// HistoryRequest представляет запрос истории правил группы, включая закончившиеся
+type HistoryRequest struct {
+	GroupName string `json:"group_name"`
+	BankName  string `json:"bank_name"` // пусто — все банки
+	Category  string `json:"category"`  // пусто — все категории
+	From      string `json:"from"`      // первый месяц периода, YYYY-MM
+	To        string `json:"to"`        // последний месяц периода, YYYY-MM
+}
+
+// TrendPoint представляет предложение банка на категорию в одном месяце
+type TrendPoint struct {
+	Month            time.Time `json:"month"`             // первое число месяца
+	Rules            int       `json:"rules"`             // сколько правил у группы; 0 — предложения не было
+	CashbackPercent  float64   `json:"cashback_percent"`  // лучший процент месяца
+	EffectivePercent float64   `json:"effective_percent"` // лучший процент в рублях с учётом программы
+	MaxAmount        float64   `json:"max_amount"`        // наибольший лимит месяца
+	PercentChange    float64   `json:"percent_change"`    // к предыдущему месяцу с предложением
+	MaxAmountChange  float64   `json:"max_amount_change"` // к предыдущему месяцу с предложением
+}
+
+// BankTrend представляет изменение процента и лимита банка на категорию по месяцам
+type BankTrend struct {
+	GroupName       string       `json:"group_name"`
+	BankName        string       `json:"bank_name"`
+	Category        string       `json:"category"`
+	From            time.Time    `json:"from"` // первое число первого месяца
+	To              time.Time    `json:"to"`   // первое число последнего месяца
+	Points          []TrendPoint `json:"points"`
+	OfferedMonths   int          `json:"offered_months"`    // в скольких месяцах было предложение
+	PercentChange   float64      `json:"percent_change"`    // последний месяц с предложением к первому
+	MaxAmountChange float64      `json:"max_amount_change"` // последний месяц с предложением к первому
+}
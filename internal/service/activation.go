@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ActivateCashback отмечает, что владелец включил категорию правила в
+// приложении банка. Отметить активацию может только владелец правила.
+func (s *Service) ActivateCashback(ctx context.Context, id int64, req *models.ActivateCashbackRequest) (*models.CashbackRule, error) {
+	if err := validator.ValidateTextField("user_id", req.UserID, true); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != req.UserID {
+		return nil, fmt.Errorf("правило %d: %w", id, ErrNotRuleOwner)
+	}
+
+	if rule.Activation.ActivatedAt == nil {
+		updates := map[string]interface{}{
+			"activation_required": true,
+			"activated_at":        time.Now(),
+		}
+		if err := s.repo.Update(ctx, id, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// ListDueActivations возвращает неактивированные правила, у которых срок
+// активации наступает в ближайшие models.ActivationRemindDays дней. Правила
+// без срока попадают сюда в начале своего месяца, а созданные уже в этом
+// месяце — на следующий день после создания.
+func (s *Service) ListDueActivations(ctx context.Context) ([]models.CashbackRule, error) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	rules, err := s.repo.ListDueActivations(ctx, today, today.AddDate(0, 0, models.ActivationRemindDays))
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.CashbackRule{}
+	}
+	return rules, nil
+}
+
+// ClaimActivationReminder отмечает напоминание об активации правила
+// отправленным и возвращает правило. Если напоминание уже забрали,
+// возвращает ErrReminderAlreadySent — так оно не уходит дважды.
+func (s *Service) ClaimActivationReminder(ctx context.Context, id int64) (*models.CashbackRule, error) {
+	claimed, err := s.repo.ClaimActivationReminder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("правило %d: %w", id, ErrReminderAlreadySent)
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// ruleActivation строит состояние активации нового правила из запроса.
+func ruleActivation(req *models.ActivationRequest) (models.RuleActivation, error) {
+	activation := models.RuleActivation{Required: req != nil}
+	if req == nil || req.Deadline == "" {
+		return activation, nil
+	}
+
+	deadline, err := validator.ValidateActivationDeadline(req.Deadline)
+	if err != nil {
+		return activation, err
+	}
+	activation.Deadline = &deadline
+	return activation, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestCreateCashbackWithActivation(t *testing.T) {
+	svc := NewService(newMemoryRepo())
+
+	req := createOp("Альфа", "Такси", 5).Create
+	req.Activation = &models.ActivationRequest{Deadline: "05.12.2099"}
+
+	rule, err := svc.CreateCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateCashback failed: %v", err)
+	}
+	if !rule.Activation.Pending() || rule.Activation.Deadline == nil ||
+		!rule.Activation.Deadline.Equal(time.Date(2099, time.December, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Activation = %+v, ожидалась активация до 05.12.2099", rule.Activation)
+	}
+
+	req = createOp("Альфа", "Кафе", 5).Create
+	req.Activation = &models.ActivationRequest{Deadline: "2099-12-05"}
+	if _, err := svc.CreateCashback(context.Background(), req); err == nil || !strings.Contains(err.Error(), "activation_deadline") {
+		t.Errorf("Expected activation_deadline validation error, got %v", err)
+	}
+}
+
+func TestActivateCashbackOnlyByOwner(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := NewService(repo)
+
+	req := createOp("Альфа", "Такси", 5).Create
+	req.Activation = &models.ActivationRequest{}
+	rule, err := svc.CreateCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateCashback failed: %v", err)
+	}
+
+	if _, err := svc.ActivateCashback(context.Background(), rule.ID, &models.ActivateCashbackRequest{UserID: "2"}); !errors.Is(err, ErrNotRuleOwner) {
+		t.Errorf("Expected ErrNotRuleOwner, got %v", err)
+	}
+
+	activated, err := svc.ActivateCashback(context.Background(), rule.ID, &models.ActivateCashbackRequest{UserID: "1"})
+	if err != nil {
+		t.Fatalf("ActivateCashback failed: %v", err)
+	}
+	if activated.Activation.Pending() || activated.Activation.ActivatedAt == nil {
+		t.Errorf("Activation = %+v, ожидалась отметка об активации", activated.Activation)
+	}
+}
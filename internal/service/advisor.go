@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// SetOfferMenu сохраняет меню категорий банка на месяц.
+func (s *Service) SetOfferMenu(ctx context.Context, bankName string, req *models.OfferMenuRequest) (*models.OfferMenu, error) {
+	var validationErrors validator.ValidationErrors
+
+	bankName = strings.TrimSpace(bankName)
+	if err := validator.ValidateTextField("bank_name", bankName, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	monthYear, err := validator.ValidateMonthYear(req.MonthYear)
+	if err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if req.PickCount < 1 || req.PickCount > len(req.Options) {
+		validationErrors = append(validationErrors, validator.ValidationError{
+			Field:   "pick_count",
+			Message: fmt.Sprintf("должно быть от 1 до числа категорий меню (%d)", len(req.Options)),
+		})
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	options := make([]models.OfferMenuOption, 0, len(req.Options))
+	for _, option := range req.Options {
+		option.Category = strings.TrimSpace(option.Category)
+		if err := validator.ValidateTextField("options.category", option.Category, true); err != nil {
+			validationErrors = append(validationErrors, err.(validator.ValidationError))
+			break
+		}
+		if seen[canonicalName(option.Category)] {
+			validationErrors = append(validationErrors, validator.ValidationError{
+				Field:   "options.category",
+				Message: fmt.Sprintf("категория \"%s\" указана дважды", option.Category),
+			})
+			break
+		}
+		seen[canonicalName(option.Category)] = true
+
+		if err := validator.ValidateCashbackPercent(option.CashbackPercent); err != nil {
+			validationErrors = append(validationErrors, err.(validator.ValidationError))
+			break
+		}
+		if option.MaxAmount != 0 {
+			if err := validator.ValidateMaxAmount(option.MaxAmount); err != nil {
+				validationErrors = append(validationErrors, err.(validator.ValidationError))
+				break
+			}
+		}
+		options = append(options, option)
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	menu := &models.OfferMenu{
+		BankName:  bankName,
+		MonthYear: monthStart(monthYear),
+		PickCount: req.PickCount,
+		Options:   options,
+	}
+	if err := s.repo.UpsertOfferMenu(ctx, menu); err != nil {
+		return nil, err
+	}
+
+	return menu, nil
+}
+
+// ListOfferMenus возвращает меню категорий банков на месяц.
+func (s *Service) ListOfferMenus(ctx context.Context, monthYear string) ([]models.OfferMenu, error) {
+	month, err := validator.ValidateMonthYear(monthYear)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка валидации: %w", err)
+	}
+	return s.repo.ListOfferMenus(ctx, monthStart(month))
+}
+
+// AdviseCategories советует участникам группы, какие категории выбрать
+// в меню банков, чтобы группа покрыла больше трат с большим кэшбэком.
+//
+// Вес категории — траты из запроса, а если их нет — сколько месяцев
+// группа раньше держала кэшбэк на эту категорию. Уже действующие правила
+// группы и выбранные участниками категории меню учитываются как покрытие.
+// Категории распределяются жадно: каждый следующий выбор даёт группе
+// наибольший прирост выгоды среди оставшихся.
+func (s *Service) AdviseCategories(ctx context.Context, req *models.CategoryAdviceRequest) (*models.CategoryAdviceResponse, error) {
+	month, err := validator.ValidateMonthYear(req.MonthYear)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка валидации: %w", err)
+	}
+	month = monthStart(month)
+
+	exists, err := s.repo.GroupExists(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrGroupNotExists)
+	}
+
+	menus, err := s.repo.ListOfferMenus(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetGroupMembers(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := s.repo.ListAllByGroup(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &models.CategoryAdviceResponse{
+		GroupName: req.GroupName,
+		MonthYear: month,
+		Advice:    []models.MemberAdvice{},
+	}
+
+	weights := newCategoryWeights(req.Spending, rules, month)
+	resp.WeightSource = models.WeightSourceHistory
+	if len(req.Spending) > 0 {
+		resp.WeightSource = models.WeightSourceSpending
+	}
+	capped := resp.WeightSource == models.WeightSourceSpending
+
+	// Покрытие группы действующими правилами: лучшая выгода по категории
+	coverage := make(map[string]categoryCoverage)
+	for _, rule := range rules {
+		if rule.Merchant != "" || rule.MonthYear.Before(month) {
+			continue
+		}
+		key := canonicalName(rule.Category)
+		value := offerValue(weights.of(key), rule.EffectivePercent, rule.MaxAmount, capped)
+		if current, ok := coverage[key]; !ok || value > current.value ||
+			(value == current.value && rule.EffectivePercent > current.percent) {
+			coverage[key] = categoryCoverage{percent: rule.EffectivePercent, value: value}
+		}
+	}
+
+	slots, err := s.adviceSlots(ctx, menus, members, rules, month)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		slot, option, gain := bestAdvicePick(slots, coverage, weights, capped)
+		if slot == nil {
+			break
+		}
+
+		key := canonicalName(option.Category)
+		current, covered := coverage[key]
+		slot.advice.Picks = append(slot.advice.Picks, models.CategoryPick{
+			Category:        option.Category,
+			CashbackPercent: option.CashbackPercent,
+			MaxAmount:       option.MaxAmount,
+			CurrentPercent:  current.percent,
+			ExpectedGain:    roundCents(gain),
+			NewCoverage:     !covered,
+		})
+		slot.taken[key] = true
+		slot.remaining--
+		resp.TotalGain += gain
+
+		if value := offerValue(weights.of(key), option.CashbackPercent, option.MaxAmount, capped); !covered || value > current.value {
+			coverage[key] = categoryCoverage{percent: option.CashbackPercent, value: value}
+		}
+	}
+	resp.TotalGain = roundCents(resp.TotalGain)
+
+	for _, slot := range slots {
+		resp.Advice = append(resp.Advice, *slot.advice)
+	}
+
+	return resp, nil
+}
+
+// categoryCoverage — лучший процент группы на категорию и его выгода.
+type categoryCoverage struct {
+	percent float64
+	value   float64
+}
+
+// adviceSlot — меню банка, из которого участнику осталось выбрать категории.
+type adviceSlot struct {
+	advice    *models.MemberAdvice
+	options   []models.OfferMenuOption
+	taken     map[string]bool // категории меню, которые участник уже выбрал
+	remaining int
+}
+
+// adviceSlots определяет, кому из участников какие меню предлагать.
+// Меню банка предлагается участнику, у которого есть карта этого банка
+// или его правила в группе. Категории меню, на которые у участника уже
+// есть правило банка в этом месяце, считаются выбранными.
+func (s *Service) adviceSlots(ctx context.Context, menus []models.OfferMenu, members []string, rules []models.CashbackRule, month time.Time) ([]*adviceSlot, error) {
+	displayNames := make(map[string]string)
+	userBanks := make(map[string]map[string]bool)
+	chosen := make(map[string]map[string]bool) // участник|банк → категории этого месяца
+	for _, rule := range rules {
+		displayNames[rule.UserID] = rule.UserDisplayName
+		if userBanks[rule.UserID] == nil {
+			userBanks[rule.UserID] = make(map[string]bool)
+		}
+		userBanks[rule.UserID][canonicalName(rule.BankName)] = true
+
+		if rule.Merchant == "" && !rule.MonthYear.Before(month) {
+			key := rule.UserID + "|" + canonicalName(rule.BankName)
+			if chosen[key] == nil {
+				chosen[key] = make(map[string]bool)
+			}
+			chosen[key][canonicalName(rule.Category)] = true
+		}
+	}
+
+	sort.Strings(members)
+
+	var slots []*adviceSlot
+	for _, member := range members {
+		cards, err := s.repo.ListUserCards(ctx, member)
+		if err != nil {
+			return nil, err
+		}
+		banks := userBanks[member]
+		if banks == nil {
+			banks = make(map[string]bool)
+		}
+		for _, card := range cards {
+			banks[canonicalName(card.BankName)] = true
+		}
+
+		for _, menu := range menus {
+			bank := canonicalName(menu.BankName)
+			if !banks[bank] {
+				continue
+			}
+
+			slot := &adviceSlot{
+				advice: &models.MemberAdvice{
+					UserID:          member,
+					UserDisplayName: displayNames[member],
+					BankName:        menu.BankName,
+					PickCount:       menu.PickCount,
+					Picks:           []models.CategoryPick{},
+				},
+				options:   menu.Options,
+				taken:     make(map[string]bool),
+				remaining: menu.PickCount,
+			}
+			for _, option := range menu.Options {
+				key := canonicalName(option.Category)
+				if chosen[member+"|"+bank][key] {
+					slot.advice.AlreadyChosen = append(slot.advice.AlreadyChosen, option.Category)
+					slot.taken[key] = true
+					slot.remaining--
+				}
+			}
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
+
+// bestAdvicePick выбирает следующую категорию с наибольшим приростом выгоды.
+// При равном приросте предпочитается ещё не покрытая категория, затем
+// больший процент. Возвращает nil, если выбирать больше нечего.
+func bestAdvicePick(slots []*adviceSlot, coverage map[string]categoryCoverage, weights categoryWeights, capped bool) (*adviceSlot, models.OfferMenuOption, float64) {
+	var (
+		bestSlot   *adviceSlot
+		bestOption models.OfferMenuOption
+		bestGain   float64
+		bestNew    bool
+	)
+
+	for _, slot := range slots {
+		if slot.remaining <= 0 {
+			continue
+		}
+		for _, option := range slot.options {
+			key := canonicalName(option.Category)
+			if slot.taken[key] {
+				continue
+			}
+
+			current, covered := coverage[key]
+			gain := offerValue(weights.of(key), option.CashbackPercent, option.MaxAmount, capped) - current.value
+			if gain < 0 {
+				gain = 0
+			}
+
+			better := bestSlot == nil || gain > bestGain ||
+				(gain == bestGain && !covered && !bestNew) ||
+				(gain == bestGain && !covered == bestNew && option.CashbackPercent > bestOption.CashbackPercent)
+			if better {
+				bestSlot, bestOption, bestGain, bestNew = slot, option, gain, !covered
+			}
+		}
+	}
+
+	return bestSlot, bestOption, bestGain
+}
+
+// categoryWeights — вес категорий для советника.
+type categoryWeights struct {
+	byCategory map[string]float64
+	fallback   float64 // вес категории, о которой ничего не известно
+}
+
+// of возвращает вес категории по её каноническому имени.
+func (w categoryWeights) of(key string) float64 {
+	if weight, ok := w.byCategory[key]; ok {
+		return weight
+	}
+	return w.fallback
+}
+
+// newCategoryWeights считает веса категорий. Траты из запроса используются
+// как есть, а категории без трат не приносят выгоды. Без трат вес — 1 плюс
+// число прошлых месяцев, когда у группы был кэшбэк на категорию.
+func newCategoryWeights(spending map[string]float64, rules []models.CashbackRule, month time.Time) categoryWeights {
+	weights := categoryWeights{byCategory: make(map[string]float64)}
+
+	if len(spending) > 0 {
+		for category, amount := range spending {
+			if amount > 0 {
+				weights.byCategory[canonicalName(category)] += amount
+			}
+		}
+		return weights
+	}
+
+	months := make(map[string]map[time.Time]bool)
+	for _, rule := range rules {
+		if !rule.MonthYear.Before(month) {
+			continue
+		}
+		key := canonicalName(rule.Category)
+		if months[key] == nil {
+			months[key] = make(map[time.Time]bool)
+		}
+		months[key][monthStart(rule.MonthYear)] = true
+	}
+
+	weights.fallback = 1
+	for key, seen := range months {
+		weights.byCategory[key] = 1 + float64(len(seen))
+	}
+
+	return weights
+}
+
+// offerValue — ожидаемая выгода от кэшбэка на категорию с весом weight.
+// При весах из трат выгода в рублях и ограничена лимитом кэшбэка.
+func offerValue(weight, percent, maxAmount float64, capped bool) float64 {
+	value := weight * percent / 100
+	if capped && maxAmount > 0 && value > maxAmount {
+		value = maxAmount
+	}
+	return value
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// advisorRepo дополняет memoryRepo группой и меню категорий.
+type advisorRepo struct {
+	*memoryRepo
+	members []string
+	menus   []models.OfferMenu
+}
+
+func (r *advisorRepo) GroupExists(ctx context.Context, groupName string) (bool, error) {
+	return groupName == "Семья", nil
+}
+
+func (r *advisorRepo) GetGroupMembers(ctx context.Context, groupName string) ([]string, error) {
+	return r.members, nil
+}
+
+func (r *advisorRepo) ListAllByGroup(ctx context.Context, groupName string) ([]models.CashbackRule, error) {
+	var rules []models.CashbackRule
+	for id := int64(1); id < r.nextID; id++ {
+		rules = append(rules, r.rules[id])
+	}
+	return rules, nil
+}
+
+func (r *advisorRepo) ListOfferMenus(ctx context.Context, monthYear time.Time) ([]models.OfferMenu, error) {
+	return r.menus, nil
+}
+
+func newAdvisorRepo() *advisorRepo {
+	repo := &advisorRepo{memoryRepo: newMemoryRepo(), members: []string{"1", "2"}}
+	repo.cards = []models.Card{{ID: 1, UserID: "1", BankName: "Тинькофф", Last4: "1234"}}
+	repo.menus = []models.OfferMenu{{
+		BankName:  "Тинькофф",
+		PickCount: 2,
+		Options: []models.OfferMenuOption{
+			{Category: "Рестораны", CashbackPercent: 5},
+			{Category: "Такси", CashbackPercent: 5},
+			{Category: "Аптеки", CashbackPercent: 3, MaxAmount: 300},
+			{Category: "АЗС", CashbackPercent: 5},
+		},
+	}}
+
+	addRule := func(userID, bank, category string, percent float64, monthYear time.Time) {
+		repo.Create(context.Background(), &models.CashbackRule{
+			GroupName: "Семья", UserID: userID, UserDisplayName: "Участник " + userID,
+			BankName: bank, Category: category, CashbackPercent: percent, EffectivePercent: percent,
+			MonthYear: monthYear,
+		})
+	}
+	current := time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC)
+	addRule("2", "Альфа", "Такси", 7, current)
+	for month := time.September; month <= time.November; month++ {
+		addRule("2", "Тинькофф", "Рестораны", 5, time.Date(2099, month, 28, 0, 0, 0, 0, time.UTC))
+	}
+	addRule("1", "Сбер", "АЗС", 3, time.Date(2099, time.October, 31, 0, 0, 0, 0, time.UTC))
+
+	return repo
+}
+
+func TestAdviseCategoriesByHistory(t *testing.T) {
+	resp, err := NewService(newAdvisorRepo()).AdviseCategories(context.Background(), &models.CategoryAdviceRequest{
+		GroupName: "Семья", MonthYear: "2099-12",
+	})
+	if err != nil {
+		t.Fatalf("AdviseCategories() error = %v", err)
+	}
+
+	if resp.WeightSource != models.WeightSourceHistory || len(resp.Advice) != 2 {
+		t.Fatalf("AdviseCategories() = %+v", resp)
+	}
+
+	first := resp.Advice[0]
+	if first.UserID != "1" || len(first.Picks) != 2 ||
+		first.Picks[0].Category != "Рестораны" || first.Picks[1].Category != "АЗС" {
+		t.Errorf("Совет участнику 1 = %+v, ожидались Рестораны и АЗС", first.Picks)
+	}
+
+	second := resp.Advice[1]
+	if len(second.Picks) != 2 || second.Picks[0].Category != "Аптеки" || !second.Picks[0].NewCoverage {
+		t.Errorf("Совет участнику 2 = %+v, первой ожидались Аптеки", second.Picks)
+	}
+	for _, advice := range resp.Advice {
+		for _, pick := range advice.Picks {
+			if pick.Category == "Такси" && pick.ExpectedGain > 0 {
+				t.Errorf("Такси уже покрыто 7%%, выгоды от 5%% быть не должно: %+v", pick)
+			}
+		}
+	}
+}
+
+func TestAdviseCategoriesBySpendingRespectsLimit(t *testing.T) {
+	repo := newAdvisorRepo()
+	repo.members = []string{"1"}
+
+	resp, err := NewService(repo).AdviseCategories(context.Background(), &models.CategoryAdviceRequest{
+		GroupName: "Семья", MonthYear: "2099-12",
+		Spending: map[string]float64{"аптеки": 20000, "АЗС": 4000},
+	})
+	if err != nil {
+		t.Fatalf("AdviseCategories() error = %v", err)
+	}
+
+	picks := resp.Advice[0].Picks
+	if resp.WeightSource != models.WeightSourceSpending || len(picks) != 2 {
+		t.Fatalf("AdviseCategories() = %+v", resp)
+	}
+	// 3% от 20000₽ — 600₽, но лимит 300₽
+	if picks[0].Category != "Аптеки" || picks[0].ExpectedGain != 300 {
+		t.Errorf("Первый выбор = %+v, ожидались Аптеки с выгодой 300₽", picks[0])
+	}
+	if picks[1].Category != "АЗС" || picks[1].ExpectedGain != 200 || resp.TotalGain != 500 {
+		t.Errorf("Второй выбор = %+v, итог %.2f", picks[1], resp.TotalGain)
+	}
+}
+
+func TestAdviseCategoriesSkipsAlreadyChosen(t *testing.T) {
+	repo := newAdvisorRepo()
+	repo.members = []string{"1"}
+	repo.Create(context.Background(), &models.CashbackRule{
+		GroupName: "Семья", UserID: "1", BankName: "Тинькофф", Category: "АЗС",
+		CashbackPercent: 5, EffectivePercent: 5, MonthYear: time.Date(2099, 12, 31, 0, 0, 0, 0, time.UTC),
+	})
+
+	resp, err := NewService(repo).AdviseCategories(context.Background(), &models.CategoryAdviceRequest{
+		GroupName: "Семья", MonthYear: "2099-12",
+	})
+	if err != nil {
+		t.Fatalf("AdviseCategories() error = %v", err)
+	}
+
+	advice := resp.Advice[0]
+	if len(advice.AlreadyChosen) != 1 || advice.AlreadyChosen[0] != "АЗС" ||
+		len(advice.Picks) != 1 || advice.Picks[0].Category != "Рестораны" {
+		t.Errorf("AdviseCategories() = %+v", advice)
+	}
+}
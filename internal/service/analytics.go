@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// defaultStatsMonths — период статистики по умолчанию, месяцев.
+const defaultStatsMonths = 6
+
+// statsPercentBounds — границы диапазонов распределения эффективного процента.
+var statsPercentBounds = []float64{1, 3, 5, 10}
+
+// GetGroupStats возвращает аналитику кэшбэка группы по месяцам, участникам,
+// банкам и категориям. Правило относится к месяцу, в котором оно заканчивается.
+func (s *Service) GetGroupStats(ctx context.Context, req *models.StatsRequest) (*models.GroupStats, error) {
+	if err := validator.ValidateTextField("group_name", req.GroupName, true); err != nil {
+		return nil, err
+	}
+
+	from, to, err := statsPeriod(req.From, req.To, defaultStatsMonths, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.GroupExists(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrGroupNotExists)
+	}
+
+	// Правила выбираются по month_year в [from, end)
+	end := to.AddDate(0, 1, 0)
+	stats := &models.GroupStats{GroupName: req.GroupName, From: from, To: to}
+
+	months, err := s.repo.GetMonthlyStats(ctx, req.GroupName, from, end)
+	if err != nil {
+		return nil, err
+	}
+	stats.Months = monthTrend(months, from, to)
+	stats.Total = statsTotal(stats.Months)
+
+	if stats.ByMember, err = s.repo.GetStatsByMember(ctx, req.GroupName, from, end); err != nil {
+		return nil, err
+	}
+	if stats.ByBank, err = s.repo.GetStatsByBank(ctx, req.GroupName, from, end); err != nil {
+		return nil, err
+	}
+	if stats.ByCategory, err = s.repo.GetStatsByCategory(ctx, req.GroupName, from, end); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.repo.GetPercentDistribution(ctx, req.GroupName, from, end, statsPercentBounds)
+	if err != nil {
+		return nil, err
+	}
+	stats.Distribution = percentRanges(counts)
+
+	earned, err := s.repo.ListEarnedCashback(ctx, req.GroupName, from, end)
+	if err != nil {
+		return nil, err
+	}
+	addEarnedCashback(stats, earned)
+
+	return stats, nil
+}
+
+// addEarnedCashback добавляет в статистику кэшбэк, заработанный по журналу
+// трат: по месяцам, участникам, банкам, категориям и за весь период.
+// Участники, банки и категории, у которых есть траты, но нет правил
+// за период, добавляются в конец своих сводок.
+func addEarnedCashback(stats *models.GroupStats, earned []models.EarnedCashback) {
+	byMonth := make(map[time.Time]float64)
+	for _, e := range earned {
+		byMonth[monthStart(e.Month)] += e.Cashback
+		stats.Total.EarnedCashback += e.Cashback
+	}
+	stats.Total.EarnedCashback = roundCents(stats.Total.EarnedCashback)
+
+	for i := range stats.Months {
+		stats.Months[i].EarnedCashback = roundCents(byMonth[stats.Months[i].Month])
+	}
+
+	stats.ByMember = addEarnedToBuckets(stats.ByMember, earned, func(e models.EarnedCashback) (string, string) {
+		return e.UserID, e.UserDisplayName
+	})
+	stats.ByBank = addEarnedToBuckets(stats.ByBank, earned, func(e models.EarnedCashback) (string, string) {
+		return e.BankName, ""
+	})
+	stats.ByCategory = addEarnedToBuckets(stats.ByCategory, earned, func(e models.EarnedCashback) (string, string) {
+		return e.Category, ""
+	})
+}
+
+// addEarnedToBuckets заполняет EarnedCashback сводок по ключу key; ключи
+// без сводки добавляются в конец в порядке первого появления.
+func addEarnedToBuckets(buckets []models.StatsBucket, earned []models.EarnedCashback,
+	key func(models.EarnedCashback) (string, string)) []models.StatsBucket {
+	sums := make(map[string]float64)
+	var order []models.StatsBucket
+	for _, e := range earned {
+		k, label := key(e)
+		if _, seen := sums[k]; !seen {
+			order = append(order, models.StatsBucket{Key: k, Label: label})
+		}
+		sums[k] += e.Cashback
+	}
+
+	for i := range buckets {
+		buckets[i].EarnedCashback = roundCents(sums[buckets[i].Key])
+		delete(sums, buckets[i].Key)
+	}
+	for _, bucket := range order {
+		if cashback, ok := sums[bucket.Key]; ok {
+			bucket.EarnedCashback = roundCents(cashback)
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// earnedByMember сводит кэшбэк по журналу трат по участникам, без деления
+// на месяцы, банки и категории. Участники идут по убыванию кэшбэка.
+func earnedByMember(earned []models.EarnedCashback) []models.EarnedCashback {
+	index := make(map[string]int)
+	var members []models.EarnedCashback
+	for _, e := range earned {
+		i, ok := index[e.UserID]
+		if !ok {
+			i = len(members)
+			index[e.UserID] = i
+			members = append(members, models.EarnedCashback{
+				Month: e.Month, UserID: e.UserID, UserDisplayName: e.UserDisplayName,
+			})
+		}
+		members[i].Spends += e.Spends
+		members[i].Cashback += e.Cashback
+	}
+
+	for i := range members {
+		members[i].Cashback = roundCents(members[i].Cashback)
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		return members[i].Cashback > members[j].Cashback
+	})
+	return members
+}
+
+// statsPeriod разбирает период статистики и возвращает первые числа
+// первого и последнего месяцев. Без To период заканчивается текущим
+// месяцем, без From — длится months месяцев, включая To.
+func statsPeriod(fromValue, toValue string, months int, now time.Time) (time.Time, time.Time, error) {
+	to := monthStart(now)
+	if toValue != "" {
+		t, err := validator.ValidateMonthYear(toValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("to: %w", err)
+		}
+		to = monthStart(t)
+	}
+
+	from := to.AddDate(0, 1-months, 0)
+	if fromValue != "" {
+		t, err := validator.ValidateMonthYear(fromValue)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("from: %w", err)
+		}
+		from = monthStart(t)
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, validator.ValidationError{
+			Field:   "from",
+			Message: "начало периода позже конца",
+		}
+	}
+
+	return from, to, nil
+}
+
+// monthTrend дополняет сводку месяцами без правил и считает изменение
+// каждого месяца к предыдущему.
+func monthTrend(months []models.MonthStats, from, to time.Time) []models.MonthStats {
+	byMonth := make(map[time.Time]models.MonthStats, len(months))
+	for _, m := range months {
+		byMonth[monthStart(m.Month)] = m
+	}
+
+	var trend []models.MonthStats
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		m := byMonth[month]
+		m.Month = month
+		if len(trend) > 0 {
+			prev := trend[len(trend)-1]
+			m.RulesChange = m.Rules - prev.Rules
+			m.AvgPercentChange = roundCents(m.AvgPercent - prev.AvgPercent)
+			m.EstimatedChange = roundCents(m.EstimatedCashback - prev.EstimatedCashback)
+		}
+		trend = append(trend, m)
+	}
+
+	return trend
+}
+
+// statsTotal считает итог за период по сводке месяцев.
+func statsTotal(months []models.MonthStats) models.StatsBucket {
+	total := models.StatsBucket{Key: "total"}
+	var percentSum float64
+
+	for _, m := range months {
+		total.Rules += m.Rules
+		total.EstimatedCashback += m.EstimatedCashback
+		percentSum += m.AvgPercent * float64(m.Rules)
+		total.MaxPercent = math.Max(total.MaxPercent, m.MaxPercent)
+	}
+
+	if total.Rules > 0 {
+		total.AvgPercent = roundCents(percentSum / float64(total.Rules))
+	}
+	total.EstimatedCashback = roundCents(total.EstimatedCashback)
+
+	return total
+}
+
+// percentRanges превращает число правил по индексам диапазонов в диапазоны процентов.
+func percentRanges(counts []int) []models.PercentRange {
+	ranges := make([]models.PercentRange, 0, len(statsPercentBounds)+1)
+	for i := 0; i <= len(statsPercentBounds); i++ {
+		r := models.PercentRange{}
+		if i > 0 {
+			r.From = statsPercentBounds[i-1]
+		}
+		if i < len(statsPercentBounds) {
+			r.To = statsPercentBounds[i]
+		}
+		if i < len(counts) {
+			r.Rules = counts[i]
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// roundCents округляет значение до сотых.
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
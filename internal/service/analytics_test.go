@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// statsRepo возвращает заранее заданные сводки аналитики.
+type statsRepo struct {
+	*memoryRepo
+	months []models.MonthStats
+	from   time.Time
+	to     time.Time
+}
+
+func (r *statsRepo) GroupExists(ctx context.Context, groupName string) (bool, error) {
+	return true, nil
+}
+
+func (r *statsRepo) GetMonthlyStats(ctx context.Context, groupName string, from, to time.Time) ([]models.MonthStats, error) {
+	r.from, r.to = from, to
+	return r.months, nil
+}
+
+func (r *statsRepo) GetStatsByMember(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return []models.StatsBucket{{Key: "1", Label: "Иван", Rules: 3}}, nil
+}
+
+func (r *statsRepo) GetStatsByBank(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return []models.StatsBucket{{Key: "Т-Банк", Rules: 3}}, nil
+}
+
+func (r *statsRepo) GetStatsByCategory(ctx context.Context, groupName string, from, to time.Time) ([]models.StatsBucket, error) {
+	return []models.StatsBucket{{Key: "Такси", Rules: 2}, {Key: "Кафе", Rules: 1}}, nil
+}
+
+func (r *statsRepo) GetPercentDistribution(ctx context.Context, groupName string, from, to time.Time, bounds []float64) ([]int, error) {
+	return []int{0, 1, 2}, nil
+}
+
+func TestGetGroupStatsTrend(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2024, m, 1, 0, 0, 0, 0, time.UTC) }
+	repo := &statsRepo{memoryRepo: newMemoryRepo(), months: []models.MonthStats{
+		{Month: month(time.October), Rules: 2, AvgPercent: 4, MaxPercent: 5, EstimatedCashback: 1000},
+		{Month: month(time.December), Rules: 1, AvgPercent: 10, MaxPercent: 10, EstimatedCashback: 3000},
+	}}
+	repo.spends = []models.Spend{
+		{GroupName: "Семья", UserID: "1", BankName: "Т-Банк", Category: "Такси", Cashback: 100, SpentAt: month(time.December).AddDate(0, 0, 3)},
+		{GroupName: "Семья", UserID: "1", BankName: "Т-Банк", Category: "Аптеки", Cashback: 50, SpentAt: month(time.December).AddDate(0, 0, 4)},
+		{GroupName: "Семья", UserID: "2", BankName: "Альфа", Category: "Такси", Cashback: 50.5, SpentAt: month(time.December).AddDate(0, 0, 5)},
+		{GroupName: "Другая", UserID: "1", BankName: "Т-Банк", Category: "Такси", Cashback: 999, SpentAt: month(time.December)},
+	}
+
+	stats, err := NewService(repo).GetGroupStats(context.Background(), &models.StatsRequest{
+		GroupName: "Семья", From: "2024-10", To: "2024-12",
+	})
+	if err != nil {
+		t.Fatalf("GetGroupStats() error = %v", err)
+	}
+
+	if !repo.from.Equal(month(time.October)) || !repo.to.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Период запроса = [%v, %v)", repo.from, repo.to)
+	}
+
+	if len(stats.Months) != 3 || stats.Months[1].Rules != 0 || stats.Months[1].RulesChange != -2 ||
+		stats.Months[2].EstimatedChange != 3000 || stats.Months[2].AvgPercentChange != 10 {
+		t.Errorf("Тренд по месяцам = %+v", stats.Months)
+	}
+
+	if stats.Total.Rules != 3 || stats.Total.AvgPercent != 6 || stats.Total.MaxPercent != 10 || stats.Total.EstimatedCashback != 4000 {
+		t.Errorf("Итог = %+v", stats.Total)
+	}
+
+	if stats.Total.EarnedCashback != 200.5 || stats.Months[2].EarnedCashback != 200.5 || stats.Months[0].EarnedCashback != 0 {
+		t.Errorf("Заработано: итог %.2f, по месяцам %+v", stats.Total.EarnedCashback, stats.Months)
+	}
+	if len(stats.ByMember) != 2 || stats.ByMember[0].EarnedCashback != 150 ||
+		stats.ByMember[1].Key != "2" || stats.ByMember[1].EarnedCashback != 50.5 {
+		t.Errorf("Заработано по участникам = %+v", stats.ByMember)
+	}
+	if len(stats.ByBank) != 2 || stats.ByBank[0].EarnedCashback != 150 ||
+		stats.ByBank[1].Key != "Альфа" || stats.ByBank[1].EarnedCashback != 50.5 {
+		t.Errorf("Заработано по банкам = %+v", stats.ByBank)
+	}
+	if len(stats.ByCategory) != 3 || stats.ByCategory[0].EarnedCashback != 150.5 || stats.ByCategory[1].EarnedCashback != 0 ||
+		stats.ByCategory[2].Key != "Аптеки" || stats.ByCategory[2].EarnedCashback != 50 {
+		t.Errorf("Заработано по категориям = %+v", stats.ByCategory)
+	}
+
+	if len(stats.Distribution) != 5 || stats.Distribution[2].From != 3 || stats.Distribution[2].Rules != 2 ||
+		stats.Distribution[4].To != 0 || stats.Distribution[4].Rules != 0 {
+		t.Errorf("Распределение = %+v", stats.Distribution)
+	}
+}
+
+func TestStatsPeriodDefaults(t *testing.T) {
+	now := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	from, to, err := statsPeriod("", "", defaultStatsMonths, now)
+	if err != nil {
+		t.Fatalf("statsPeriod() error = %v", err)
+	}
+	if !from.Equal(time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("statsPeriod() = %v — %v", from, to)
+	}
+
+	if _, _, err := statsPeriod("2024-05", "2024-01", defaultStatsMonths, now); err == nil {
+		t.Error("statsPeriod() ожидалась ошибка для начала позже конца")
+	}
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ListBankCaps возвращает общие лимиты банков пользователя с кэшбэком,
+// заработанным за текущий период.
+func (s *Service) ListBankCaps(ctx context.Context, userID string) ([]models.BankCapUsage, error) {
+	if err := validator.ValidateTextField("user_id", userID, true); err != nil {
+		return nil, err
+	}
+
+	caps, err := s.repo.ListBankCaps(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usages := make([]models.BankCapUsage, 0, len(caps))
+	for _, c := range caps {
+		usage, err := capUsage(ctx, s.repo, c, now)
+		if err != nil {
+			return nil, err
+		}
+		usages = append(usages, *usage)
+	}
+
+	return usages, nil
+}
+
+// SetBankCap устанавливает общий лимит кэшбэка банка пользователя за период.
+func (s *Service) SetBankCap(ctx context.Context, userID, bankName string, req *models.BankCapRequest) (*models.BankCap, error) {
+	var validationErrors validator.ValidationErrors
+
+	bankName = strings.TrimSpace(bankName)
+	period := strings.ToLower(strings.TrimSpace(req.Period))
+	if period == "" {
+		period = models.BankCapPeriodMonth
+	}
+
+	if err := validator.ValidateTextField("user_id", userID, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("bank_name", bankName, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidatePaymentAmount(req.Amount); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateBankCapPeriod(period); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	bankCap := &models.BankCap{
+		UserID:   userID,
+		BankName: bankName,
+		Period:   period,
+		Amount:   roundCents(req.Amount),
+	}
+	if err := s.repo.UpsertBankCap(ctx, bankCap); err != nil {
+		return nil, err
+	}
+
+	return bankCap, nil
+}
+
+// DeleteBankCap удаляет лимиты банка пользователя за все периоды.
+func (s *Service) DeleteBankCap(ctx context.Context, userID, bankName string) error {
+	return s.repo.DeleteBankCaps(ctx, userID, strings.TrimSpace(bankName))
+}
+
+// RecordSpend записывает покупку по своей карте в журнал. Без rule_id правило
+// выбирается так же, как личный лучший кэшбэк пользователя на категорию.
+func (s *Service) RecordSpend(ctx context.Context, req *models.CreateSpendRequest) (*models.SpendResponse, error) {
+	if err := s.checkGroup(ctx, req.GroupName); err != nil {
+		return nil, err
+	}
+
+	var validationErrors validator.ValidationErrors
+
+	category := strings.TrimSpace(req.Category)
+
+	if err := validator.ValidateTextField("user_id", req.UserID, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("category", category, req.RuleID == 0); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidatePaymentAmount(req.Amount); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	userGroup, err := s.repo.GetUserGroup(ctx, req.UserID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, err
+	}
+	if userGroup != req.GroupName {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrNotGroupMember)
+	}
+
+	now := time.Now()
+	var rule *models.CashbackRule
+	if req.RuleID != 0 {
+		rule, err = s.repo.GetByID(ctx, req.RuleID)
+		if err != nil {
+			return nil, err
+		}
+		if rule.GroupName != req.GroupName || rule.UserID != req.UserID {
+			return nil, fmt.Errorf("правило %d пользователя %s: %w", req.RuleID, req.UserID, database.ErrNotFound)
+		}
+	} else {
+		path, err := s.categoryPath(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		rule, _, err = s.bestOnPath(ctx, req.GroupName, category, path, today,
+			models.PurchaseContext{Amount: req.Amount}, req.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return recordSpend(ctx, s.repo, rule, req.GroupName, req.Amount, nil, now)
+}
+
+// recordSpend записывает покупку по правилу. Кэшбэк в рублях ограничивается
+// остатком max_amount правила и общим лимитом банка владельца карты.
+func recordSpend(ctx context.Context, repo database.RepositoryInterface, rule *models.CashbackRule, groupName string, amount float64, paymentID *int64, at time.Time) (*models.SpendResponse, error) {
+	cashback := amount * rule.EffectivePercent / 100
+
+	// max_amount задан в единицах программы вознаграждения
+	if rule.MaxAmount > 0 && rule.CashbackPercent > 0 {
+		earned, err := repo.SumRuleCashback(ctx, rule.ID)
+		if err != nil {
+			return nil, err
+		}
+		limit := rule.MaxAmount * rule.EffectivePercent / rule.CashbackPercent
+		cashback = math.Min(cashback, math.Max(limit-earned, 0))
+	}
+
+	usage, err := bankCapUsage(ctx, repo, rule.UserID, rule.BankName, at)
+	if err != nil {
+		return nil, err
+	}
+	if usage != nil {
+		cashback = math.Min(cashback, usage.Remaining)
+	}
+
+	ruleID := rule.ID
+	spend := models.Spend{
+		GroupName:        groupName,
+		UserID:           rule.UserID,
+		BankName:         rule.BankName,
+		Category:         rule.Category,
+		RuleID:           &ruleID,
+		PaymentRequestID: paymentID,
+		Amount:           roundCents(amount),
+		Cashback:         roundCents(cashback),
+		SpentAt:          at,
+	}
+	if err := repo.CreateSpend(ctx, &spend); err != nil {
+		return nil, err
+	}
+
+	if usage != nil {
+		usage.Earned = roundCents(usage.Earned + spend.Cashback)
+		usage.Remaining = roundCents(math.Max(usage.Amount-usage.Earned, 0))
+	}
+
+	return &models.SpendResponse{Spend: spend, BankCap: usage}, nil
+}
+
+// bankCapUsage возвращает самый исчерпанный из лимитов банка пользователя
+// на момент at или nil, если лимитов нет.
+func bankCapUsage(ctx context.Context, repo database.RepositoryInterface, userID, bankName string, at time.Time) (*models.BankCapUsage, error) {
+	caps, err := repo.GetBankCaps(ctx, userID, bankName)
+	if err != nil {
+		return nil, err
+	}
+
+	var tightest *models.BankCapUsage
+	for _, c := range caps {
+		usage, err := capUsage(ctx, repo, c, at)
+		if err != nil {
+			return nil, err
+		}
+		if tightest == nil || usage.Remaining < tightest.Remaining {
+			tightest = usage
+		}
+	}
+
+	return tightest, nil
+}
+
+// capUsage считает кэшбэк, заработанный в периоде лимита, содержащем at.
+func capUsage(ctx context.Context, repo database.RepositoryInterface, c models.BankCap, at time.Time) (*models.BankCapUsage, error) {
+	start, end := capPeriod(c.Period, at)
+	earned, err := repo.SumBankCashback(ctx, c.UserID, c.BankName, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BankCapUsage{
+		BankCap:     c,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Earned:      roundCents(earned),
+		Remaining:   roundCents(math.Max(c.Amount-earned, 0)),
+	}, nil
+}
+
+// capPeriod возвращает границы календарного месяца или квартала, содержащего at.
+func capPeriod(period string, at time.Time) (time.Time, time.Time) {
+	start := monthStart(at)
+	if period == models.BankCapPeriodQuarter {
+		start = start.AddDate(0, -(int(start.Month())-1)%3, 0)
+		return start, start.AddDate(0, 3, 0)
+	}
+	return start, start.AddDate(0, 1, 0)
+}
+
+// capAwareBetter сравнивает правила для выбора лучшего: правило владельца,
+// у которого ещё остался общий лимит банка, выигрывает у исчерпанного,
+// иначе сравниваются эффективные проценты.
+func capAwareBetter(a, b *models.CashbackRule) bool {
+	if a.BankCap.Exhausted() != b.BankCap.Exhausted() {
+		return !a.BankCap.Exhausted()
+	}
+	return a.EffectivePercent > b.EffectivePercent
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestCapPeriod(t *testing.T) {
+	at := time.Date(2025, time.May, 17, 12, 0, 0, 0, time.UTC)
+
+	start, end := capPeriod(models.BankCapPeriodMonth, at)
+	if !start.Equal(time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("capPeriod(month) = %v — %v", start, end)
+	}
+
+	start, end = capPeriod(models.BankCapPeriodQuarter, at)
+	if !start.Equal(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("capPeriod(quarter) = %v — %v", start, end)
+	}
+}
+
+func TestRecordSpendRespectsLimits(t *testing.T) {
+	repo := newMemoryRepo()
+	repo.caps = []models.BankCap{{UserID: "1", BankName: "Тинькофф", Period: models.BankCapPeriodMonth, Amount: 1000}}
+	rule := &models.CashbackRule{ID: 7, UserID: "1", BankName: "тинькофф", Category: "Такси",
+		CashbackPercent: 10, EffectivePercent: 10, MaxAmount: 300}
+	ctx := context.Background()
+	now := time.Now()
+
+	// Лимит правила 300₽: вторая покупка получает только остаток
+	spend, err := recordSpend(ctx, repo, rule, "Семья", 2500, nil, now)
+	if err != nil || spend.Cashback != 250 {
+		t.Fatalf("recordSpend() = %+v, %v", spend, err)
+	}
+	spend, err = recordSpend(ctx, repo, rule, "Семья", 2500, nil, now)
+	if err != nil || spend.Cashback != 50 {
+		t.Fatalf("recordSpend() сверх лимита правила = %+v, %v", spend, err)
+	}
+
+	// Общий лимит банка: осталось 700₽
+	other := &models.CashbackRule{ID: 8, UserID: "1", BankName: "Тинькофф", Category: "Рестораны",
+		CashbackPercent: 10, EffectivePercent: 10}
+	spend, err = recordSpend(ctx, repo, other, "Семья", 10000, nil, now)
+	if err != nil || spend.Cashback != 700 {
+		t.Fatalf("recordSpend() сверх лимита банка = %+v, %v", spend, err)
+	}
+	if !spend.BankCap.Exhausted() || spend.BankCap.Earned != 1000 {
+		t.Errorf("recordSpend() лимит банка = %+v", spend.BankCap)
+	}
+}
+
+func TestGetBestCashbackSkipsExhaustedBankCap(t *testing.T) {
+	exhausted := models.CashbackRule{ID: 1, UserID: "1", BankName: "Тинькофф", Category: "Такси",
+		CashbackPercent: 10, EffectivePercent: 10}
+	fallback := models.CashbackRule{ID: 2, UserID: "2", BankName: "Альфа", Category: "Такси",
+		CashbackPercent: 5, EffectivePercent: 5}
+	repo := &rewardRepo{memoryRepo: newMemoryRepo(), best: map[string][]models.CashbackRule{
+		"Такси": {exhausted, fallback},
+	}}
+	repo.caps = []models.BankCap{{UserID: "1", BankName: "Тинькофф", Period: models.BankCapPeriodMonth, Amount: 500}}
+	repo.spends = []models.Spend{{UserID: "1", BankName: "Тинькофф", Cashback: 500,
+		SpentAt: time.Date(2099, time.December, 10, 0, 0, 0, 0, time.UTC)}}
+	svc := NewService(repo)
+	req := &models.BestCashbackRequest{GroupName: "Семья", Category: "Такси", MonthYear: "31.12.2099"}
+
+	rule, err := svc.GetBestCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetBestCashback() error = %v", err)
+	}
+	if rule.ID != fallback.ID {
+		t.Errorf("GetBestCashback() = правило %d, ожидалось %d: лимит Тинькофф исчерпан", rule.ID, fallback.ID)
+	}
+
+	// Без других вариантов остаётся правило с исчерпанным лимитом — с пометкой
+	repo.best["Такси"] = []models.CashbackRule{exhausted}
+	rule, err = svc.GetBestCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetBestCashback() error = %v", err)
+	}
+	if rule.ID != exhausted.ID || !rule.BankCap.Exhausted() {
+		t.Errorf("GetBestCashback() = %+v", rule)
+	}
+
+	// В ноябре лимит ещё не тронут: покупки декабря на него не влияют
+	req.MonthYear = "30.11.2099"
+	rule, err = svc.GetBestCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetBestCashback() error = %v", err)
+	}
+	if rule.BankCap.Exhausted() || rule.BankCap.Earned != 0 {
+		t.Errorf("GetBestCashback() за ноябрь лимит = %+v", rule.BankCap)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// maxBatchOperations — максимальное количество операций в одном пакете.
+const maxBatchOperations = 100
+
+// errBatchAborted откатывает транзакцию пакета в режиме atomic после ошибки операции.
+var errBatchAborted = errors.New("пакет отменён из-за ошибки операции")
+
+// BatchCashback выполняет пакет операций create/update/delete в одной транзакции.
+//
+// В режиме atomic первая ошибка откатывает весь пакет, остальные операции
+// не выполняются. В режиме best_effort каждая операция выполняется в своей
+// точке сохранения: ошибочные откатываются, успешные фиксируются вместе.
+func (s *Service) BatchCashback(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = models.BatchModeAtomic
+	}
+	if mode != models.BatchModeAtomic && mode != models.BatchModeBestEffort {
+		return nil, fmt.Errorf("%w: неизвестный режим %q", ErrInvalidBatch, req.Mode)
+	}
+	if len(req.Operations) == 0 {
+		return nil, fmt.Errorf("%w: нет операций", ErrInvalidBatch)
+	}
+	if len(req.Operations) > maxBatchOperations {
+		return nil, fmt.Errorf("%w: больше %d операций", ErrInvalidBatch, maxBatchOperations)
+	}
+
+	resp := &models.BatchResponse{
+		Mode:    mode,
+		Results: make([]models.BatchResult, len(req.Operations)),
+	}
+	for i, op := range req.Operations {
+		resp.Results[i] = models.BatchResult{Index: i, Op: op.Op, Status: models.BatchStatusSkipped, ID: op.ID}
+	}
+
+	err := s.repo.WithTx(ctx, func(tx database.RepositoryInterface) error {
+		for i, op := range req.Operations {
+			result := &resp.Results[i]
+
+			var err error
+			if mode == models.BatchModeAtomic {
+				err = applyBatchOperation(ctx, tx, op, result)
+			} else {
+				err = tx.WithTx(ctx, func(savepoint database.RepositoryInterface) error {
+					return applyBatchOperation(ctx, savepoint, op, result)
+				})
+			}
+
+			if err != nil {
+				result.Status = models.BatchStatusError
+				result.Error = err.Error()
+				result.Rule = nil
+
+				var duplicate *DuplicateRuleError
+				if errors.As(err, &duplicate) {
+					result.Existing = duplicate.Existing
+					result.Identical = duplicate.Identical
+				}
+
+				if mode == models.BatchModeAtomic {
+					return errBatchAborted
+				}
+				continue
+			}
+			result.Status = models.BatchStatusOK
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errBatchAborted) {
+		return nil, fmt.Errorf("пакетная операция: %w", err)
+	}
+	resp.Committed = err == nil
+
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		if !resp.Committed && result.Status == models.BatchStatusOK {
+			result.Status = models.BatchStatusRolledBack
+			if result.Op == models.BatchOpCreate {
+				result.ID = 0
+				result.Rule = nil
+			}
+		}
+
+		switch result.Status {
+		case models.BatchStatusOK:
+			resp.Succeeded++
+		case models.BatchStatusError:
+			resp.Failed++
+		}
+	}
+
+	return resp, nil
+}
+
+// applyBatchOperation выполняет одну операцию пакета через репозиторий транзакции.
+func applyBatchOperation(ctx context.Context, repo database.RepositoryInterface, op models.BatchOperation, result *models.BatchResult) error {
+	txService := NewService(repo)
+
+	switch op.Op {
+	case models.BatchOpCreate:
+		if op.Create == nil {
+			return fmt.Errorf("нет данных для создания")
+		}
+		rule, err := txService.CreateCashback(ctx, op.Create)
+		if err != nil {
+			return err
+		}
+		result.ID = rule.ID
+		result.Rule = rule
+
+	case models.BatchOpUpdate:
+		if op.ID == 0 || op.Update == nil {
+			return fmt.Errorf("нужны id и данные для обновления")
+		}
+		if err := txService.UpdateCashback(ctx, op.ID, op.Update); err != nil {
+			return err
+		}
+		rule, err := repo.GetByID(ctx, op.ID)
+		if err != nil {
+			return err
+		}
+		result.Rule = rule
+
+	case models.BatchOpDelete:
+		if op.ID == 0 {
+			return fmt.Errorf("нужен id для удаления")
+		}
+		return txService.DeleteCashback(ctx, op.ID)
+
+	default:
+		return fmt.Errorf("неизвестная операция %q", op.Op)
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// memoryRepo — репозиторий в памяти с транзакциями через копирование состояния.
+// Неиспользуемые методы интерфейса не реализованы.
+type memoryRepo struct {
+	database.RepositoryInterface
+	rules  map[int64]models.CashbackRule
+	cards  []models.Card
+	tree   map[string]string // категория → родитель
+	caps   []models.BankCap
+	spends []models.Spend
+	nextID int64
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{rules: make(map[int64]models.CashbackRule), nextID: 1}
+}
+
+func (m *memoryRepo) clone() *memoryRepo {
+	c := &memoryRepo{rules: make(map[int64]models.CashbackRule, len(m.rules)), cards: m.cards, tree: m.tree, caps: m.caps, nextID: m.nextID}
+	c.spends = append(c.spends, m.spends...)
+	for id, rule := range m.rules {
+		c.rules[id] = rule
+	}
+	return c
+}
+
+func (m *memoryRepo) WithTx(ctx context.Context, fn func(tx database.RepositoryInterface) error) error {
+	tx := m.clone()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	m.rules, m.spends, m.nextID = tx.rules, tx.spends, tx.nextID
+	return nil
+}
+
+func (m *memoryRepo) Create(ctx context.Context, rule *models.CashbackRule) error {
+	rule.ID = m.nextID
+	m.nextID++
+	m.rules[rule.ID] = *rule
+	return nil
+}
+
+func (m *memoryRepo) GetByID(ctx context.Context, id int64) (*models.CashbackRule, error) {
+	rule, ok := m.rules[id]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return &rule, nil
+}
+
+func (m *memoryRepo) Update(ctx context.Context, id int64, updates map[string]interface{}) error {
+	rule, ok := m.rules[id]
+	if !ok {
+		return database.ErrNotFound
+	}
+	if percent, ok := updates["cashback_percent"].(float64); ok {
+		rule.CashbackPercent = percent
+	}
+	if amount, ok := updates["max_amount"].(float64); ok {
+		rule.MaxAmount = amount
+	}
+	if required, ok := updates["activation_required"].(bool); ok {
+		rule.Activation.Required = required
+	}
+	if at, ok := updates["activated_at"].(time.Time); ok {
+		rule.Activation.ActivatedAt = &at
+	}
+	m.rules[id] = rule
+	return nil
+}
+
+func (m *memoryRepo) Delete(ctx context.Context, id int64) error {
+	if _, ok := m.rules[id]; !ok {
+		return database.ErrNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+func (m *memoryRepo) ListUserCashback(ctx context.Context, userID string, since time.Time) ([]models.CashbackRule, error) {
+	var rules []models.CashbackRule
+	for _, rule := range m.rules {
+		if rule.UserID == userID && !rule.MonthYear.Before(since) {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *memoryRepo) FindMerchant(ctx context.Context, name string) (*models.Merchant, error) {
+	return nil, database.ErrNotFound
+}
+
+func (m *memoryRepo) ListUserCards(ctx context.Context, userID string) ([]models.Card, error) {
+	var cards []models.Card
+	for _, card := range m.cards {
+		if card.UserID == userID {
+			cards = append(cards, card)
+		}
+	}
+	return cards, nil
+}
+
+func (m *memoryRepo) GetCard(ctx context.Context, id int64) (*models.Card, error) {
+	for _, card := range m.cards {
+		if card.ID == id {
+			return &card, nil
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (m *memoryRepo) GetCategoryPath(ctx context.Context, category string) ([]string, error) {
+	if _, ok := m.tree[category]; !ok {
+		return nil, nil
+	}
+	path := []string{category}
+	for parent := m.tree[category]; parent != ""; parent = m.tree[parent] {
+		path = append(path, parent)
+	}
+	return path, nil
+}
+
+func (m *memoryRepo) GetBankCaps(ctx context.Context, userID, bankName string) ([]models.BankCap, error) {
+	var caps []models.BankCap
+	for _, c := range m.caps {
+		if c.UserID == userID && strings.EqualFold(c.BankName, bankName) {
+			caps = append(caps, c)
+		}
+	}
+	return caps, nil
+}
+
+func (m *memoryRepo) CreateSpend(ctx context.Context, spend *models.Spend) error {
+	spend.ID = int64(len(m.spends) + 1)
+	m.spends = append(m.spends, *spend)
+	return nil
+}
+
+func (m *memoryRepo) SumBankCashback(ctx context.Context, userID, bankName string, from, to time.Time) (float64, error) {
+	var sum float64
+	for _, s := range m.spends {
+		if s.UserID == userID && strings.EqualFold(s.BankName, bankName) && !s.SpentAt.Before(from) && s.SpentAt.Before(to) {
+			sum += s.Cashback
+		}
+	}
+	return sum, nil
+}
+
+func (m *memoryRepo) SumRuleCashback(ctx context.Context, ruleID int64) (float64, error) {
+	var sum float64
+	for _, s := range m.spends {
+		if s.RuleID != nil && *s.RuleID == ruleID {
+			sum += s.Cashback
+		}
+	}
+	return sum, nil
+}
+
+func (m *memoryRepo) ListEarnedCashback(ctx context.Context, groupName string, from, to time.Time) ([]models.EarnedCashback, error) {
+	var earned []models.EarnedCashback
+	index := make(map[string]int)
+	for _, spend := range m.spends {
+		if spend.GroupName != groupName || spend.SpentAt.Before(from) || !spend.SpentAt.Before(to) {
+			continue
+		}
+		month := monthStart(spend.SpentAt)
+		key := month.Format("2006-01") + "/" + spend.UserID + "/" + spend.BankName + "/" + spend.Category
+		i, ok := index[key]
+		if !ok {
+			i = len(earned)
+			index[key] = i
+			earned = append(earned, models.EarnedCashback{
+				Month: month, UserID: spend.UserID, UserDisplayName: spend.UserID,
+				BankName: spend.BankName, Category: spend.Category,
+			})
+		}
+		earned[i].Spends++
+		earned[i].Cashback += spend.Cashback
+	}
+	return earned, nil
+}
+
+func createOp(bank, category string, percent float64) models.BatchOperation {
+	return models.BatchOperation{Op: models.BatchOpCreate, Create: &models.CreateCashbackRequest{
+		GroupName: "Семья", UserID: "1", UserDisplayName: "Иван",
+		BankName: bank, Category: category, MonthYear: "31.12.2099",
+		CashbackPercent: percent, MaxAmount: 3000,
+	}}
+}
+
+func TestBatchCashbackAtomicRollsBackOnError(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := NewService(repo)
+
+	resp, err := svc.BatchCashback(context.Background(), &models.BatchRequest{
+		Operations: []models.BatchOperation{
+			createOp("Тинькофф", "Такси", 5),
+			{Op: models.BatchOpDelete, ID: 999},
+			createOp("Альфа", "Кафе", 7),
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchCashback failed: %v", err)
+	}
+
+	if resp.Committed || len(repo.rules) != 0 {
+		t.Errorf("Expected rollback, committed=%v rules=%d", resp.Committed, len(repo.rules))
+	}
+
+	want := []string{models.BatchStatusRolledBack, models.BatchStatusError, models.BatchStatusSkipped}
+	for i, status := range want {
+		if resp.Results[i].Status != status {
+			t.Errorf("Result %d: expected %s, got %s", i, status, resp.Results[i].Status)
+		}
+	}
+}
+
+func TestBatchCashbackBestEffortKeepsSuccessful(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := NewService(repo)
+
+	resp, err := svc.BatchCashback(context.Background(), &models.BatchRequest{
+		Mode: models.BatchModeBestEffort,
+		Operations: []models.BatchOperation{
+			createOp("Тинькофф", "Такси", 5),
+			createOp("тинькофф", "такси ", 7), // конфликт с первой операцией
+			createOp("Альфа", "Кафе", 7),
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchCashback failed: %v", err)
+	}
+
+	if !resp.Committed || resp.Succeeded != 2 || resp.Failed != 1 {
+		t.Errorf("Unexpected response: %+v", resp)
+	}
+	if len(repo.rules) != 2 {
+		t.Errorf("Expected 2 saved rules, got %d", len(repo.rules))
+	}
+
+	conflict := resp.Results[1]
+	if conflict.Status != models.BatchStatusError || conflict.Existing == nil || conflict.Existing.ID != resp.Results[0].ID {
+		t.Errorf("Expected conflict with first rule, got %+v", conflict)
+	}
+}
+
+func TestBatchCashbackRejectsInvalidRequest(t *testing.T) {
+	svc := NewService(newMemoryRepo())
+
+	for _, req := range []*models.BatchRequest{
+		{},
+		{Mode: "sometimes", Operations: []models.BatchOperation{createOp("Тинькофф", "Такси", 5)}},
+	} {
+		if _, err := svc.BatchCashback(context.Background(), req); !errors.Is(err, ErrInvalidBatch) {
+			t.Errorf("Expected ErrInvalidBatch for %+v, got %v", req, err)
+		}
+	}
+}
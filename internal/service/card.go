@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// CreateCard добавляет карту пользователя.
+func (s *Service) CreateCard(ctx context.Context, req *models.CreateCardRequest) (*models.Card, error) {
+	var validationErrors validator.ValidationErrors
+
+	paymentSystem := strings.ToLower(strings.TrimSpace(req.PaymentSystem))
+	nickname := strings.TrimSpace(req.Nickname)
+
+	if err := validator.ValidateTextField("user_id", req.UserID, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("bank_name", req.BankName, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("nickname", nickname, false); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateCardLast4(req.Last4); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidatePaymentSystem(paymentSystem); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	card := &models.Card{
+		UserID:        req.UserID,
+		BankName:      strings.TrimSpace(req.BankName),
+		Nickname:      nickname,
+		Last4:         req.Last4,
+		PaymentSystem: paymentSystem,
+	}
+	if err := s.repo.CreateCard(ctx, card); err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+// ListUserCards возвращает карты пользователя.
+func (s *Service) ListUserCards(ctx context.Context, userID string) ([]models.Card, error) {
+	return s.repo.ListUserCards(ctx, userID)
+}
+
+// DeleteCard удаляет карту пользователя.
+func (s *Service) DeleteCard(ctx context.Context, id int64, userID string) error {
+	card, err := s.repo.GetCard(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return fmt.Errorf("карта %d: %w", id, ErrUnknownCard)
+		}
+		return err
+	}
+
+	if card.UserID != userID {
+		return fmt.Errorf("карта %d принадлежит другому пользователю: %w", id, ErrCardMismatch)
+	}
+
+	return s.repo.DeleteCard(ctx, id)
+}
+
+// resolveRuleCard определяет карту нового правила.
+// Карта по card_id должна принадлежать автору правила и банку правила;
+// без банка правило получает банк карты. Без card_id карта ищется среди
+// карт пользователя в этом банке: по card_last4 или, если карта одна, она
+// и выбирается. Правило без подходящей карты сохраняется без неё.
+func (s *Service) resolveRuleCard(ctx context.Context, req *models.CreateCashbackRequest) (*models.Card, error) {
+	if req.CardID != nil {
+		card, err := s.getCard(ctx, *req.CardID)
+		if err != nil {
+			return nil, err
+		}
+		if req.BankName == "" {
+			req.BankName = card.BankName
+		}
+		if err := cardMatches(card, req.UserID, req.BankName); err != nil {
+			return nil, err
+		}
+		return card, nil
+	}
+
+	if req.UserID == "" || req.BankName == "" {
+		return nil, nil
+	}
+
+	cards, err := s.repo.ListUserCards(ctx, req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("получение карт: %w", err)
+	}
+
+	var bankCards []models.Card
+	for _, card := range cards {
+		if canonicalName(card.BankName) == canonicalName(req.BankName) {
+			bankCards = append(bankCards, card)
+		}
+	}
+
+	if req.CardLast4 != "" {
+		for i := range bankCards {
+			if bankCards[i].Last4 == req.CardLast4 {
+				return &bankCards[i], nil
+			}
+		}
+		return nil, fmt.Errorf("карта %s ***%s: %w", req.BankName, req.CardLast4, ErrUnknownCard)
+	}
+
+	if len(bankCards) == 1 {
+		return &bankCards[0], nil
+	}
+	return nil, nil
+}
+
+// checkRuleCard проверяет, что карту можно привязать к сохранённому правилу.
+// bankName — новый банк правила, если он меняется в том же запросе.
+func (s *Service) checkRuleCard(ctx context.Context, ruleID, cardID int64, bankName string) error {
+	rule, err := s.repo.GetByID(ctx, ruleID)
+	if err != nil {
+		return err
+	}
+
+	card, err := s.getCard(ctx, cardID)
+	if err != nil {
+		return err
+	}
+
+	if bankName == "" {
+		bankName = rule.BankName
+	}
+	return cardMatches(card, rule.UserID, bankName)
+}
+
+// getCard получает карту, превращая отсутствие карты в ErrUnknownCard.
+func (s *Service) getCard(ctx context.Context, id int64) (*models.Card, error) {
+	card, err := s.repo.GetCard(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("карта %d: %w", id, ErrUnknownCard)
+		}
+		return nil, err
+	}
+	return card, nil
+}
+
+// cardMatches проверяет владельца и банк карты.
+func cardMatches(card *models.Card, userID, bankName string) error {
+	if card.UserID != userID {
+		return fmt.Errorf("карта %d принадлежит другому пользователю: %w", card.ID, ErrCardMismatch)
+	}
+	if canonicalName(card.BankName) != canonicalName(bankName) {
+		return fmt.Errorf("карта %d выпущена банком %s, а не %s: %w", card.ID, card.BankName, bankName, ErrCardMismatch)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func newCardRepo() *memoryRepo {
+	repo := newMemoryRepo()
+	repo.cards = []models.Card{
+		{ID: 1, UserID: "1", BankName: "Сбер", Last4: "1234", PaymentSystem: models.PaymentSystemMir},
+		{ID: 2, UserID: "1", BankName: "Сбер", Last4: "5678", PaymentSystem: models.PaymentSystemVisa},
+		{ID: 3, UserID: "1", BankName: "Альфа", Last4: "0042", PaymentSystem: models.PaymentSystemMir},
+		{ID: 4, UserID: "2", BankName: "Альфа", Last4: "9999", PaymentSystem: models.PaymentSystemMir},
+	}
+	return repo
+}
+
+func TestCreateCashbackAttachesCard(t *testing.T) {
+	svc := NewService(newCardRepo())
+
+	tests := []struct {
+		name     string
+		bank     string
+		last4    string
+		wantCard int64
+	}{
+		{"единственная карта банка", "альфа", "", 3},
+		{"по последним цифрам", "Сбер", "5678", 2},
+		{"несколько карт без цифр", "Сбер", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createOp(tt.bank, "Кафе "+tt.name, 5).Create
+			req.CardLast4 = tt.last4
+
+			rule, err := svc.CreateCashback(context.Background(), req)
+			if err != nil {
+				t.Fatalf("CreateCashback failed: %v", err)
+			}
+
+			var got int64
+			if rule.Card != nil {
+				got = rule.Card.ID
+			}
+			if got != tt.wantCard {
+				t.Errorf("Expected card %d, got %d", tt.wantCard, got)
+			}
+		})
+	}
+}
+
+func TestCreateCashbackAllowsSameCategoryOnDifferentCards(t *testing.T) {
+	svc := NewService(newCardRepo())
+
+	for _, last4 := range []string{"1234", "5678"} {
+		req := createOp("Сбер", "Такси", 5).Create
+		req.CardLast4 = last4
+		if _, err := svc.CreateCashback(context.Background(), req); err != nil {
+			t.Fatalf("CreateCashback for card %s failed: %v", last4, err)
+		}
+	}
+
+	req := createOp("Сбер", "Такси", 7).Create
+	req.CardLast4 = "1234"
+	if _, err := svc.CreateCashback(context.Background(), req); !errors.Is(err, ErrDuplicateRule) {
+		t.Errorf("Expected ErrDuplicateRule for the same card, got %v", err)
+	}
+}
+
+func TestCreateCashbackRejectsWrongCard(t *testing.T) {
+	svc := NewService(newCardRepo())
+
+	foreign, wrongBank, missing := int64(4), int64(3), int64(42)
+	tests := []struct {
+		name    string
+		cardID  *int64
+		last4   string
+		wantErr error
+	}{
+		{"чужая карта", &foreign, "", ErrCardMismatch},
+		{"карта другого банка", &wrongBank, "", ErrCardMismatch},
+		{"нет такой карты", &missing, "", ErrUnknownCard},
+		{"нет карты с такими цифрами", nil, "0000", ErrUnknownCard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := createOp("Сбер", "Такси", 5).Create
+			req.CardID, req.CardLast4 = tt.cardID, tt.last4
+
+			if _, err := svc.CreateCashback(context.Background(), req); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ListCategories возвращает дерево категорий списком узлов.
+func (s *Service) ListCategories(ctx context.Context) ([]models.Category, error) {
+	return s.repo.ListCategories(ctx)
+}
+
+// SetCategory добавляет категорию в дерево или переносит её к другому родителю.
+// Родитель должен уже быть в дереве; без родителя может быть только корень.
+func (s *Service) SetCategory(ctx context.Context, name string, req *models.CategoryRequest) (*models.Category, error) {
+	name = strings.TrimSpace(name)
+	if err := validator.ValidateTextField("name", name, true); err != nil {
+		return nil, err
+	}
+
+	parent := strings.TrimSpace(req.Parent)
+	if parent == "" {
+		if canonicalName(name) != canonicalName(models.AllPurchasesCategory) {
+			return nil, fmt.Errorf("категория \"%s\" без родителя: %w", name, ErrInvalidCategoryTree)
+		}
+	} else {
+		parentPath, err := s.repo.GetCategoryPath(ctx, parent)
+		if err != nil {
+			return nil, err
+		}
+		if len(parentPath) == 0 {
+			return nil, fmt.Errorf("родительская категория \"%s\" не найдена: %w", parent, ErrInvalidCategoryTree)
+		}
+		for _, ancestor := range parentPath {
+			if canonicalName(ancestor) == canonicalName(name) {
+				return nil, fmt.Errorf("категория \"%s\" не может входить сама в себя: %w", name, ErrInvalidCategoryTree)
+			}
+		}
+		parent = parentPath[0]
+	}
+
+	category := &models.Category{Name: name, Parent: parent}
+	if err := s.repo.UpsertCategory(ctx, category); err != nil {
+		return nil, err
+	}
+
+	return category, nil
+}
+
+// GetCategoryPath возвращает уровни поиска кэшбэка для категории:
+// саму категорию, её предков и "Все покупки".
+func (s *Service) GetCategoryPath(ctx context.Context, category string) ([]string, error) {
+	if err := validator.ValidateTextField("category", category, true); err != nil {
+		return nil, err
+	}
+	return s.categoryPath(ctx, category)
+}
+
+// categoryPath строит путь от категории к корню дерева. Категория, которой
+// нет в дереве, считается дочерней для "Все покупки".
+func (s *Service) categoryPath(ctx context.Context, category string) ([]string, error) {
+	tree, err := s.repo.GetCategoryPath(ctx, category)
+	if err != nil {
+		return nil, err
+	}
+
+	path := []string{category}
+	if len(tree) > 1 {
+		path = append(path, tree[1:]...)
+	}
+	if canonicalName(path[len(path)-1]) != canonicalName(models.AllPurchasesCategory) {
+		path = append(path, models.AllPurchasesCategory)
+	}
+
+	return path, nil
+}
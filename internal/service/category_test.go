@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// categoryRepo дополняет rewardRepo сохранением узлов дерева категорий.
+type categoryRepo struct {
+	*rewardRepo
+}
+
+func (r *categoryRepo) UpsertCategory(ctx context.Context, category *models.Category) error {
+	r.tree[category.Name] = category.Parent
+	return nil
+}
+
+func newCategoryRepo(best map[string][]models.CashbackRule) *categoryRepo {
+	repo := &categoryRepo{rewardRepo: &rewardRepo{memoryRepo: newMemoryRepo(), best: best}}
+	repo.tree = map[string]string{
+		"Все покупки":      "",
+		"Рестораны и кафе": "Все покупки",
+		"Фастфуд":          "Рестораны и кафе",
+	}
+	return repo
+}
+
+func TestGetBestCashbackWalksCategoryTree(t *testing.T) {
+	tests := []struct {
+		name         string
+		best         map[string][]models.CashbackRule
+		wantID       int64
+		wantCategory string
+		wantLevel    int
+	}{
+		{
+			name: "родитель выгоднее",
+			best: map[string][]models.CashbackRule{
+				"Фастфуд":          {{ID: 1, EffectivePercent: 3}},
+				"Рестораны и кафе": {{ID: 2, EffectivePercent: 7}},
+				"Все покупки":      {{ID: 3, EffectivePercent: 2}},
+			},
+			wantID: 2, wantCategory: "Рестораны и кафе", wantLevel: 1,
+		},
+		{
+			name: "при равенстве — более конкретная категория",
+			best: map[string][]models.CashbackRule{
+				"Фастфуд":          {{ID: 1, EffectivePercent: 7}},
+				"Рестораны и кафе": {{ID: 2, EffectivePercent: 7}},
+			},
+			wantID: 1, wantCategory: "Фастфуд", wantLevel: 0,
+		},
+		{
+			name: "только корень",
+			best: map[string][]models.CashbackRule{
+				"Все покупки": {{ID: 3, EffectivePercent: 1}},
+			},
+			wantID: 3, wantCategory: "Все покупки", wantLevel: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := NewService(newCategoryRepo(tt.best)).GetBestCashback(context.Background(), &models.BestCashbackRequest{
+				GroupName: "Семья", Category: "Фастфуд", MonthYear: "31.12.2099",
+			})
+			if err != nil {
+				t.Fatalf("GetBestCashback() error = %v", err)
+			}
+			if resp.ID != tt.wantID || resp.Match.Category != tt.wantCategory || resp.Match.Level != tt.wantLevel {
+				t.Errorf("GetBestCashback() = правило %d, уровень %d (%s); ожидалось %d, %d (%s)",
+					resp.ID, resp.Match.Level, resp.Match.Category, tt.wantID, tt.wantLevel, tt.wantCategory)
+			}
+			if len(resp.Match.Path) != 3 || resp.Match.IsFallback() != (tt.wantLevel > 0) {
+				t.Errorf("GetBestCashback() match = %+v", resp.Match)
+			}
+		})
+	}
+}
+
+func TestGetBestCashbackPersonal(t *testing.T) {
+	svc := NewService(newCategoryRepo(map[string][]models.CashbackRule{
+		"Фастфуд":          {{ID: 1, UserID: "anna", EffectivePercent: 10}, {ID: 2, UserID: "ivan", EffectivePercent: 2}},
+		"Рестораны и кафе": {{ID: 3, UserID: "ivan", EffectivePercent: 5}},
+	}))
+
+	tests := []struct {
+		userID       string
+		wantPersonal int64 // 0 — личного кэшбэка нет
+		wantLevel    int
+	}{
+		{userID: "", wantPersonal: 0},
+		{userID: "anna", wantPersonal: 1, wantLevel: 0},
+		{userID: "ivan", wantPersonal: 3, wantLevel: 1},
+		{userID: "oleg", wantPersonal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			resp, err := svc.GetBestCashback(context.Background(), &models.BestCashbackRequest{
+				GroupName: "Семья", Category: "Фастфуд", MonthYear: "31.12.2099", UserID: tt.userID,
+			})
+			if err != nil {
+				t.Fatalf("GetBestCashback() error = %v", err)
+			}
+			if resp.ID != 1 {
+				t.Errorf("GetBestCashback() лучший в группе = %d, ожидалось 1", resp.ID)
+			}
+
+			if tt.wantPersonal == 0 {
+				if resp.Personal != nil {
+					t.Errorf("GetBestCashback() личный = %+v, ожидалось nil", resp.Personal)
+				}
+				return
+			}
+			if resp.Personal == nil || resp.Personal.ID != tt.wantPersonal || resp.Personal.Match.Level != tt.wantLevel {
+				t.Errorf("GetBestCashback() личный = %+v, ожидалось правило %d на уровне %d", resp.Personal, tt.wantPersonal, tt.wantLevel)
+			}
+		})
+	}
+}
+
+func TestCategoryPathOutsideTreeFallsBackToAllPurchases(t *testing.T) {
+	path, err := NewService(newCategoryRepo(nil)).GetCategoryPath(context.Background(), "Зоотовары")
+	if err != nil {
+		t.Fatalf("GetCategoryPath() error = %v", err)
+	}
+	if len(path) != 2 || path[0] != "Зоотовары" || path[1] != models.AllPurchasesCategory {
+		t.Errorf("GetCategoryPath() = %v", path)
+	}
+}
+
+func TestSetCategoryValidatesTree(t *testing.T) {
+	repo := newCategoryRepo(nil)
+	svc := NewService(repo)
+
+	for _, tt := range []struct{ name, parent string }{
+		{"Рестораны и кафе", "Фастфуд"},  // цикл
+		{"Бургерные", "Бургеры и пицца"}, // нет родителя в дереве
+		{"Бургерные", ""},                // второй корень
+	} {
+		if _, err := svc.SetCategory(context.Background(), tt.name, &models.CategoryRequest{Parent: tt.parent}); !errors.Is(err, ErrInvalidCategoryTree) {
+			t.Errorf("SetCategory(%q, %q) error = %v, ожидалась ErrInvalidCategoryTree", tt.name, tt.parent, err)
+		}
+	}
+
+	if _, err := svc.SetCategory(context.Background(), "Бургерные", &models.CategoryRequest{Parent: "Фастфуд"}); err != nil {
+		t.Fatalf("SetCategory() error = %v", err)
+	}
+	if repo.tree["Бургерные"] != "Фастфуд" {
+		t.Errorf("Категория не сохранена: %v", repo.tree)
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// normalizeConditions валидирует условия правила и приводит их к каноническому
+// виду: дни недели по порядку без повторов, способы оплаты в нижнем регистре.
+func normalizeConditions(c *models.RuleConditions) (models.RuleConditions, error) {
+	if c == nil {
+		return models.RuleConditions{}, nil
+	}
+
+	methods := make([]string, 0, len(c.PaymentMethods))
+	seenMethods := make(map[string]bool, len(c.PaymentMethods))
+	for _, method := range c.PaymentMethods {
+		method = strings.ToLower(strings.TrimSpace(method))
+		if !seenMethods[method] {
+			seenMethods[method] = true
+			methods = append(methods, method)
+		}
+	}
+
+	validationErrors := validator.ValidateConditions(c.MinPurchase, c.MinMonthlySpend, c.Weekdays, methods)
+	if len(validationErrors) > 0 {
+		return models.RuleConditions{}, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	weekdays := make([]int, 0, len(c.Weekdays))
+	seenDays := make(map[int]bool, len(c.Weekdays))
+	for _, day := range c.Weekdays {
+		if !seenDays[day] {
+			seenDays[day] = true
+			weekdays = append(weekdays, day)
+		}
+	}
+	sort.Ints(weekdays)
+
+	normalized := models.RuleConditions{
+		MinPurchase:     validator.RoundToTwoDecimals(c.MinPurchase),
+		MinMonthlySpend: validator.RoundToTwoDecimals(c.MinMonthlySpend),
+	}
+	// Все семь дней — то же, что отсутствие ограничения
+	if len(weekdays) > 0 && len(weekdays) < 7 {
+		normalized.Weekdays = weekdays
+	}
+	if len(methods) > 0 {
+		normalized.PaymentMethods = methods
+	}
+
+	return normalized, nil
+}
+
+// sameConditions сравнивает нормализованные условия двух правил.
+func sameConditions(a, b models.RuleConditions) bool {
+	if a.MinPurchase != b.MinPurchase || a.MinMonthlySpend != b.MinMonthlySpend ||
+		len(a.Weekdays) != len(b.Weekdays) || len(a.PaymentMethods) != len(b.PaymentMethods) {
+		return false
+	}
+	for i := range a.Weekdays {
+		if a.Weekdays[i] != b.Weekdays[i] {
+			return false
+		}
+	}
+	for i := range a.PaymentMethods {
+		if a.PaymentMethods[i] != b.PaymentMethods[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// bestAllowedCashback возвращает самое выгодное правило категории,
+// условия которого допускают покупку. Непустой userID оставляет только
+// правила этого пользователя. Общие лимиты банков учитываются так же,
+// как в firstAllowedWithinCap.
+func (s *Service) bestAllowedCashback(ctx context.Context, groupName, category string, monthYear time.Time, purchase models.PurchaseContext, userID string) (*models.CashbackRule, error) {
+	rules, err := s.repo.GetAllCashbackByCategory(ctx, groupName, category, monthYear)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.firstAllowedWithinCap(ctx, rules, monthYear, purchase, userID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fmt.Errorf("кэшбэк для категории \"%s\": %w", category, database.ErrNotFound)
+	}
+	return rule, nil
+}
+
+// firstAllowedWithinCap выбирает из отсортированных по эффективному проценту
+// правил первое, условия которого допускают покупку, и заполняет у него
+// BankCap. Правило владельца, исчерпавшего общий лимит банка за период
+// месяца monthYear, выбирается, только если других подходящих правил нет.
+// Если подходящих правил нет, возвращает nil.
+func (s *Service) firstAllowedWithinCap(ctx context.Context, rules []models.CashbackRule, monthYear time.Time, purchase models.PurchaseContext, userID string) (*models.CashbackRule, error) {
+	var exhausted *models.CashbackRule
+
+	for i := range rules {
+		if userID != "" && rules[i].UserID != userID {
+			continue
+		}
+		if !rules[i].Conditions.Allows(purchase) {
+			continue
+		}
+
+		usage, err := bankCapUsage(ctx, s.repo, rules[i].UserID, rules[i].BankName, monthYear)
+		if err != nil {
+			return nil, err
+		}
+		rules[i].BankCap = usage
+
+		if !usage.Exhausted() {
+			return &rules[i], nil
+		}
+		if exhausted == nil {
+			exhausted = &rules[i]
+		}
+	}
+
+	return exhausted, nil
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestNormalizeConditions(t *testing.T) {
+	got, err := normalizeConditions(&models.RuleConditions{
+		MinPurchase:    999.999,
+		Weekdays:       []int{7, 6, 6},
+		PaymentMethods: []string{"SBP", " qr ", "sbp"},
+	})
+	if err != nil {
+		t.Fatalf("normalizeConditions() error = %v", err)
+	}
+
+	want := models.RuleConditions{MinPurchase: 1000, Weekdays: []int{6, 7}, PaymentMethods: []string{"sbp", "qr"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("normalizeConditions() = %+v, want %+v", got, want)
+	}
+
+	if _, err := normalizeConditions(&models.RuleConditions{Weekdays: []int{8}}); err == nil {
+		t.Error("normalizeConditions() должна отклонять день недели 8")
+	}
+}
+
+func TestGetBestCashbackFiltersByPurchase(t *testing.T) {
+	weekend := models.CashbackRule{ID: 1, Category: "Рестораны", CashbackPercent: 10, EffectivePercent: 10,
+		Conditions: models.RuleConditions{Weekdays: []int{6, 7}}}
+	bigPurchase := models.CashbackRule{ID: 2, Category: "Рестораны", CashbackPercent: 7, EffectivePercent: 7,
+		Conditions: models.RuleConditions{MinPurchase: 1000}}
+	plain := models.CashbackRule{ID: 3, Category: "Рестораны", CashbackPercent: 3, EffectivePercent: 3}
+	repo := &rewardRepo{memoryRepo: newMemoryRepo(), best: map[string][]models.CashbackRule{
+		"Рестораны": {weekend, bigPurchase, plain},
+	}}
+	svc := NewService(repo)
+
+	tests := []struct {
+		name     string
+		purchase models.PurchaseContext
+		wantID   int64
+	}{
+		{"без контекста", models.PurchaseContext{}, 1},
+		{"суббота", models.PurchaseContext{Weekday: 6, Amount: 500}, 1},
+		{"вторник, крупная покупка", models.PurchaseContext{Weekday: 2, Amount: 1500}, 2},
+		{"вторник, мелкая покупка", models.PurchaseContext{Weekday: 2, Amount: 500}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := svc.GetBestCashback(context.Background(), &models.BestCashbackRequest{
+				GroupName: "Семья", Category: "Рестораны", MonthYear: "31.12.2099", Purchase: tt.purchase,
+			})
+			if err != nil {
+				t.Fatalf("GetBestCashback() error = %v", err)
+			}
+			if rule.ID != tt.wantID {
+				t.Errorf("GetBestCashback() = правило %d, ожидалось %d", rule.ID, tt.wantID)
+			}
+		})
+	}
+
+	if _, err := svc.GetBestCashback(context.Background(), &models.BestCashbackRequest{
+		GroupName: "Семья", Category: "Рестораны", MonthYear: "31.12.2099",
+		Purchase: models.PurchaseContext{PaymentMethod: "cash"},
+	}); err == nil {
+		t.Error("GetBestCashback() должна отклонять неизвестный способ оплаты")
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// defaultCoverageMinPercent — порог "слабого" кэшбэка по умолчанию, %.
+const defaultCoverageMinPercent = 3
+
+// GetGroupCoverage находит важные для группы категории, на которые нет
+// действующего кэшбэка или есть только кэшбэк ниже порога.
+//
+// Важные категории — категории из дерева категорий и категории, на которые
+// у группы был кэшбэк в прошлые месяцы. Категория не считается пробелом,
+// если её выгодно покрывает кэшбэк на родительскую категорию.
+func (s *Service) GetGroupCoverage(ctx context.Context, req *models.CoverageRequest) (*models.CoverageReport, error) {
+	if err := validator.ValidateTextField("group_name", req.GroupName, true); err != nil {
+		return nil, err
+	}
+	monthYear, err := validator.ValidateMonthYear(req.MonthYear)
+	if err != nil {
+		return nil, err
+	}
+
+	minPercent := req.MinPercent
+	if minPercent == 0 {
+		minPercent = defaultCoverageMinPercent
+	}
+	if err := validator.ValidateCashbackPercent(minPercent); err != nil {
+		return nil, fmt.Errorf("min_percent: %w", err)
+	}
+
+	exists, err := s.repo.GroupExists(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrGroupNotExists)
+	}
+
+	important, err := s.importantCategories(ctx, req.GroupName, monthStart(monthYear))
+	if err != nil {
+		return nil, err
+	}
+
+	active, err := s.repo.GetActiveCategories(ctx, req.GroupName, monthYear)
+	if err != nil {
+		return nil, err
+	}
+	activeSet := make(map[string]bool, len(active))
+	for _, category := range active {
+		activeSet[canonicalName(category)] = true
+	}
+
+	report := &models.CoverageReport{
+		GroupName:  req.GroupName,
+		MonthYear:  monthYear,
+		MinPercent: minPercent,
+		Checked:    len(important),
+		Gaps:       []models.CoverageGap{},
+	}
+
+	// Правила по категориям кэшируются: родители общие у многих категорий
+	cache := make(map[string][]models.CashbackRule)
+	categoryRules := func(category string) ([]models.CashbackRule, error) {
+		key := canonicalName(category)
+		if rules, ok := cache[key]; ok {
+			return rules, nil
+		}
+		rules, err := s.repo.GetAllCashbackByCategory(ctx, req.GroupName, category, monthYear)
+		if err != nil {
+			return nil, err
+		}
+		cache[key] = rules
+		return rules, nil
+	}
+
+	for _, gap := range important {
+		var own *models.CashbackRule
+		if activeSet[canonicalName(gap.Category)] {
+			rules, err := categoryRules(gap.Category)
+			if err != nil {
+				return nil, err
+			}
+			if len(rules) > 0 {
+				own = &rules[0]
+			}
+		}
+		if own != nil && own.EffectivePercent >= minPercent {
+			continue
+		}
+
+		gap.Status = models.CoverageMissing
+		if own != nil {
+			gap.Status = models.CoverageWeak
+			gap.BestRule, gap.BestCategory = own, gap.Category
+		}
+
+		path, err := s.categoryPath(ctx, gap.Category)
+		if err != nil {
+			return nil, err
+		}
+		for _, ancestor := range path[1:] {
+			rules, err := categoryRules(ancestor)
+			if err != nil {
+				return nil, err
+			}
+			if len(rules) > 0 && (gap.BestRule == nil || rules[0].EffectivePercent > gap.BestRule.EffectivePercent) {
+				gap.BestRule, gap.BestCategory = &rules[0], ancestor
+			}
+		}
+		if gap.BestRule != nil && gap.BestRule.EffectivePercent >= minPercent {
+			continue
+		}
+
+		report.Gaps = append(report.Gaps, gap)
+	}
+
+	// Сначала категории, которыми группа пользовалась чаще, затем самые слабые
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		a, b := report.Gaps[i], report.Gaps[j]
+		if a.HistoryMonths != b.HistoryMonths {
+			return a.HistoryMonths > b.HistoryMonths
+		}
+		return gapPercent(a) < gapPercent(b)
+	})
+
+	return report, nil
+}
+
+// importantCategories собирает категории дерева (кроме "Все покупки")
+// и категории, на которые у группы был кэшбэк до указанного месяца.
+func (s *Service) importantCategories(ctx context.Context, groupName string, month time.Time) ([]models.CoverageGap, error) {
+	rules, err := s.repo.ListAllByGroup(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var gaps []models.CoverageGap
+	index := make(map[string]int)
+	months := make(map[string]map[time.Time]bool)
+
+	for _, rule := range rules {
+		if rule.Merchant != "" || !rule.MonthYear.Before(month) {
+			continue
+		}
+		key := canonicalName(rule.Category)
+		if _, ok := index[key]; !ok {
+			index[key] = len(gaps)
+			gaps = append(gaps, models.CoverageGap{Category: rule.Category, Source: models.CoverageSourceHistory})
+			months[key] = make(map[time.Time]bool)
+		}
+		months[key][monthStart(rule.MonthYear)] = true
+	}
+	for key, seen := range months {
+		gaps[index[key]].HistoryMonths = len(seen)
+	}
+
+	categories, err := s.repo.ListCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		key := canonicalName(category.Name)
+		if _, ok := index[key]; ok || key == canonicalName(models.AllPurchasesCategory) {
+			continue
+		}
+		index[key] = len(gaps)
+		gaps = append(gaps, models.CoverageGap{Category: category.Name, Source: models.CoverageSourceDictionary})
+	}
+
+	return gaps, nil
+}
+
+// gapPercent возвращает процент лучшего доступного кэшбэка для пробела.
+func gapPercent(gap models.CoverageGap) float64 {
+	if gap.BestRule == nil {
+		return 0
+	}
+	return gap.BestRule.EffectivePercent
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// coverageRepo дополняет categoryRepo историей правил и активными категориями.
+type coverageRepo struct {
+	*categoryRepo
+	history []models.CashbackRule
+}
+
+func (r *coverageRepo) GroupExists(ctx context.Context, groupName string) (bool, error) {
+	return groupName == "Семья", nil
+}
+
+func (r *coverageRepo) ListAllByGroup(ctx context.Context, groupName string) ([]models.CashbackRule, error) {
+	return r.history, nil
+}
+
+func (r *coverageRepo) GetActiveCategories(ctx context.Context, groupName string, monthYear time.Time) ([]string, error) {
+	var categories []string
+	for category := range r.best {
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+func (r *coverageRepo) ListCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	for name, parent := range r.tree {
+		categories = append(categories, models.Category{Name: name, Parent: parent})
+	}
+	return categories, nil
+}
+
+func newCoverageRepo(best map[string][]models.CashbackRule) *coverageRepo {
+	repo := &coverageRepo{categoryRepo: newCategoryRepo(best)}
+	repo.tree["Аптеки"] = "Все покупки"
+	repo.tree["Такси"] = "Все покупки"
+	for _, month := range []time.Month{time.August, time.October} {
+		repo.history = append(repo.history, models.CashbackRule{
+			Category: "Зоотовары", EffectivePercent: 5, MonthYear: time.Date(2099, month, 31, 0, 0, 0, 0, time.UTC),
+		})
+	}
+	return repo
+}
+
+func TestGetGroupCoverageFindsGaps(t *testing.T) {
+	repo := newCoverageRepo(map[string][]models.CashbackRule{
+		"Такси":       {{ID: 1, Category: "Такси", EffectivePercent: 5}},
+		"Аптеки":      {{ID: 2, Category: "Аптеки", EffectivePercent: 1}},
+		"Все покупки": {{ID: 3, Category: "Все покупки", EffectivePercent: 1.5}},
+	})
+
+	report, err := NewService(repo).GetGroupCoverage(context.Background(), &models.CoverageRequest{
+		GroupName: "Семья", MonthYear: "2099-12",
+	})
+	if err != nil {
+		t.Fatalf("GetGroupCoverage() error = %v", err)
+	}
+
+	gaps := make(map[string]models.CoverageGap)
+	for _, gap := range report.Gaps {
+		gaps[gap.Category] = gap
+	}
+	if len(report.Gaps) != 4 || report.Checked != 5 || report.MinPercent != defaultCoverageMinPercent {
+		t.Fatalf("GetGroupCoverage() = %+v", report)
+	}
+	if _, ok := gaps["Такси"]; ok {
+		t.Errorf("Такси покрыто 5%% и не должно быть пробелом")
+	}
+
+	first := report.Gaps[0]
+	if first.Category != "Зоотовары" || first.Source != models.CoverageSourceHistory || first.HistoryMonths != 2 ||
+		first.Status != models.CoverageMissing || first.BestCategory != "Все покупки" {
+		t.Errorf("Первый пробел = %+v, ожидались Зоотовары из истории", first)
+	}
+	if gap := gaps["Аптеки"]; gap.Status != models.CoverageWeak || gap.BestRule == nil || gap.BestRule.ID != 3 {
+		t.Errorf("Аптеки = %+v, ожидался слабый кэшбэк с лучшим вариантом на \"Все покупки\"", gap)
+	}
+}
+
+func TestGetGroupCoverageParentCoversChild(t *testing.T) {
+	repo := newCoverageRepo(map[string][]models.CashbackRule{
+		"Рестораны и кафе": {{ID: 1, Category: "Рестораны и кафе", EffectivePercent: 7}},
+	})
+
+	report, err := NewService(repo).GetGroupCoverage(context.Background(), &models.CoverageRequest{
+		GroupName: "Семья", MonthYear: "2099-12", MinPercent: 6,
+	})
+	if err != nil {
+		t.Fatalf("GetGroupCoverage() error = %v", err)
+	}
+
+	for _, gap := range report.Gaps {
+		if gap.Category == "Фастфуд" || gap.Category == "Рестораны и кафе" {
+			t.Errorf("Категорию %s покрывает кэшбэк 7%% на родителя: %+v", gap.Category, gap)
+		}
+	}
+}
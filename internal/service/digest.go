@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// GetDigestSettings возвращает расписание сводки группы. Если группа его
+// не меняла, возвращается расписание по умолчанию.
+func (s *Service) GetDigestSettings(ctx context.Context, groupName string) (*models.DigestSettings, error) {
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.repo.GetDigestSettings(ctx, groupName)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return defaultDigestSettings(groupName), nil
+		}
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SetDigestSettings меняет день и время отправки сводки группы или выключает её.
+func (s *Service) SetDigestSettings(ctx context.Context, groupName string, req *models.DigestSettingsRequest) (*models.DigestSettings, error) {
+	var validationErrors validator.ValidationErrors
+
+	if req.Day < 0 || req.Day > 28 {
+		validationErrors = append(validationErrors, validator.ValidationError{
+			Field:   "day",
+			Message: "день должен быть от 1 до 28 или 0 — последний день месяца",
+		})
+	}
+
+	clock, err := time.Parse("15:04", req.Time)
+	if err != nil {
+		validationErrors = append(validationErrors, validator.ValidationError{
+			Field:   "time",
+			Message: fmt.Sprintf("неверный формат времени, ожидается ЧЧ:ММ, получено: %s", req.Time),
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+
+	settings := &models.DigestSettings{
+		GroupName: groupName,
+		Day:       req.Day,
+		Hour:      clock.Hour(),
+		Minute:    clock.Minute(),
+		Enabled:   req.Enabled,
+	}
+	if err := s.repo.UpsertDigestSettings(ctx, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// ListDueDigests возвращает группы, которым пора отправить сводку за текущий
+// месяц, вместе с получателями: привязанными чатами или участниками группы.
+func (s *Service) ListDueDigests(ctx context.Context) ([]models.DigestDelivery, error) {
+	groups, err := s.repo.GetAllGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListDigestSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().In(s.digestLocation)
+	deliveries := []models.DigestDelivery{}
+
+	for _, settings := range dueDigests(groups, list, now, s.digestLocation) {
+		delivery := models.DigestDelivery{GroupName: settings.GroupName, Month: monthStart(now)}
+
+		if delivery.ChatIDs, err = s.repo.ListGroupChats(ctx, settings.GroupName); err != nil {
+			return nil, err
+		}
+		if len(delivery.ChatIDs) == 0 {
+			if delivery.Members, err = s.repo.GetGroupMembers(ctx, settings.GroupName); err != nil {
+				return nil, err
+			}
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// GetGroupDigest собирает итоги месяца для группы: закончившиеся правила,
+// лучшие правила следующего месяца и категории без выгодного кэшбэка.
+// Пустой monthYear — текущий месяц.
+func (s *Service) GetGroupDigest(ctx context.Context, groupName, monthYear string) (*models.GroupDigest, error) {
+	month := monthStart(time.Now().In(s.digestLocation))
+	if monthYear != "" {
+		t, err := validator.ValidateMonthYear(monthYear)
+		if err != nil {
+			return nil, err
+		}
+		month = monthStart(t)
+	}
+
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.repo.ListAllByGroup(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	next := month.AddDate(0, 1, 0)
+	digest := &models.GroupDigest{
+		GroupName: groupName,
+		Month:     month,
+		NextMonth: next,
+		Expired:   expiredRules(rules, month),
+		Best:      bestRulesFrom(rules, next),
+	}
+
+	coverage, err := s.GetGroupCoverage(ctx, &models.CoverageRequest{
+		GroupName: groupName,
+		MonthYear: next.Format("2006-01"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	digest.Gaps = coverage.Gaps
+
+	earned, err := s.repo.ListEarnedCashback(ctx, groupName, month, next)
+	if err != nil {
+		return nil, err
+	}
+	if len(earned) > 0 {
+		digest.Earned = earnedByMember(earned)
+	}
+
+	return digest, nil
+}
+
+// ClaimGroupDigest собирает сводку группы за месяц и отмечает её
+// отправленной. Если сводку за этот месяц уже забрали, возвращает
+// ErrDigestAlreadySent — так сводка не уходит дважды.
+func (s *Service) ClaimGroupDigest(ctx context.Context, groupName string, req *models.DigestClaimRequest) (*models.GroupDigest, error) {
+	if err := validator.ValidateTextField("month_year", req.MonthYear, true); err != nil {
+		return nil, err
+	}
+
+	digest, err := s.GetGroupDigest(ctx, groupName, req.MonthYear)
+	if err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.repo.ClaimDigest(ctx, groupName, digest.Month)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("группа \"%s\", %s: %w", groupName, digest.Month.Format("01/2006"), ErrDigestAlreadySent)
+	}
+
+	return digest, nil
+}
+
+// checkGroup проверяет, что группа существует.
+func (s *Service) checkGroup(ctx context.Context, groupName string) error {
+	if err := validator.ValidateTextField("group_name", groupName, true); err != nil {
+		return err
+	}
+
+	exists, err := s.repo.GroupExists(ctx, groupName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("группа \"%s\": %w", groupName, ErrGroupNotExists)
+	}
+	return nil
+}
+
+// defaultDigestSettings возвращает расписание сводки по умолчанию.
+func defaultDigestSettings(groupName string) *models.DigestSettings {
+	return &models.DigestSettings{
+		GroupName: groupName,
+		Day:       models.DigestDefaultDay,
+		Hour:      models.DigestDefaultHour,
+		Minute:    models.DigestDefaultMinute,
+		Enabled:   true,
+	}
+}
+
+// dueDigests отбирает группы, которым пора отправить сводку за месяц now:
+// время отправки в этом месяце наступило, а сводку ещё не отправляли.
+// Месяц и время отправки считаются в часовом поясе loc.
+// Группы без расписания получают сводку по умолчанию.
+func dueDigests(groups []string, list []models.DigestSettings, now time.Time, loc *time.Location) []models.DigestSettings {
+	now = now.In(loc)
+
+	byGroup := make(map[string]models.DigestSettings, len(list))
+	for _, settings := range list {
+		byGroup[settings.GroupName] = settings
+	}
+
+	month := monthStart(now)
+	var due []models.DigestSettings
+
+	for _, groupName := range groups {
+		settings, ok := byGroup[groupName]
+		if !ok {
+			settings = *defaultDigestSettings(groupName)
+		}
+		if !settings.Enabled || now.Before(digestTime(settings, now, loc)) {
+			continue
+		}
+		if settings.LastSentMonth != nil && !monthStart(*settings.LastSentMonth).Before(month) {
+			continue
+		}
+		due = append(due, settings)
+	}
+
+	return due
+}
+
+// digestTime возвращает момент отправки сводки в месяце now: день и время
+// расписания читаются в часовом поясе loc.
+func digestTime(settings models.DigestSettings, now time.Time, loc *time.Location) time.Time {
+	now = now.In(loc)
+	lastDay := time.Date(now.Year(), now.Month()+1, 0, 0, 0, 0, 0, loc).Day()
+	day := settings.Day
+	if day == 0 || day > lastDay {
+		day = lastDay
+	}
+	return time.Date(now.Year(), now.Month(), day, settings.Hour, settings.Minute, 0, 0, loc)
+}
+
+// expiredRules возвращает правила, которые заканчиваются в указанном месяце,
+// от самых выгодных.
+func expiredRules(rules []models.CashbackRule, month time.Time) []models.CashbackRule {
+	expired := []models.CashbackRule{}
+	for _, rule := range rules {
+		if monthStart(rule.MonthYear).Equal(month) {
+			expired = append(expired, rule)
+		}
+	}
+
+	sort.SliceStable(expired, func(i, j int) bool {
+		return expired[i].EffectivePercent > expired[j].EffectivePercent
+	})
+	return expired
+}
+
+// bestRulesFrom возвращает лучшее правило на каждую категорию среди правил,
+// которые действуют с первого числа указанного месяца, от самых выгодных.
+func bestRulesFrom(rules []models.CashbackRule, from time.Time) []models.CashbackRule {
+	best := []models.CashbackRule{}
+	index := make(map[string]int)
+
+	for _, rule := range rules {
+		if rule.Merchant != "" || rule.MonthYear.Before(from) {
+			continue
+		}
+		key := canonicalName(rule.Category)
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(best)
+			best = append(best, rule)
+			continue
+		}
+		if rule.EffectivePercent > best[i].EffectivePercent {
+			best[i] = rule
+		}
+	}
+
+	sort.SliceStable(best, func(i, j int) bool {
+		return best[i].EffectivePercent > best[j].EffectivePercent
+	})
+	return best
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// digestRepo дополняет coverageRepo отметками об отправленных сводках.
+type digestRepo struct {
+	*coverageRepo
+	sent map[string]time.Time
+}
+
+func (r *digestRepo) ClaimDigest(ctx context.Context, groupName string, month time.Time) (bool, error) {
+	if last, ok := r.sent[groupName]; ok && !last.Before(month) {
+		return false, nil
+	}
+	r.sent[groupName] = month
+	return true, nil
+}
+
+func TestDueDigests(t *testing.T) {
+	september := time.Date(2099, time.September, 1, 0, 0, 0, 0, time.UTC)
+	october := time.Date(2099, time.October, 1, 0, 0, 0, 0, time.UTC)
+	groups := []string{"По умолчанию", "Пятое число", "Выключена", "Уже отправлена"}
+	list := []models.DigestSettings{
+		{GroupName: "Пятое число", Day: 5, Hour: 10, Enabled: true, LastSentMonth: &september},
+		{GroupName: "Выключена", Day: 5, Hour: 10, Enabled: false},
+		{GroupName: "Уже отправлена", Day: 5, Hour: 10, Enabled: true, LastSentMonth: &october},
+	}
+
+	due := dueDigests(groups, list, time.Date(2099, time.October, 20, 12, 0, 0, 0, time.UTC), time.UTC)
+	if len(due) != 1 || due[0].GroupName != "Пятое число" {
+		t.Errorf("dueDigests() 20 октября = %+v", due)
+	}
+
+	due = dueDigests(groups, list, time.Date(2099, time.October, 31, 18, 59, 0, 0, time.UTC), time.UTC)
+	if len(due) != 1 {
+		t.Errorf("dueDigests() до 19:00 последнего дня = %+v", due)
+	}
+
+	due = dueDigests(groups, list, time.Date(2099, time.October, 31, 19, 0, 0, 0, time.UTC), time.UTC)
+	if len(due) != 2 || due[0].GroupName != "По умолчанию" {
+		t.Errorf("dueDigests() в 19:00 последнего дня = %+v", due)
+	}
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	due = dueDigests(groups, list, time.Date(2099, time.October, 31, 16, 0, 0, 0, time.UTC), moscow)
+	if len(due) != 2 {
+		t.Errorf("dueDigests() в 19:00 по Москве = %+v", due)
+	}
+
+	// 22:00 UTC 31 октября по Москве уже 1 ноября, и ноябрьская сводка
+	// ещё не наступила.
+	due = dueDigests([]string{"По умолчанию"}, nil, time.Date(2099, time.October, 31, 22, 0, 0, 0, time.UTC), moscow)
+	if len(due) != 0 {
+		t.Errorf("dueDigests() в начале ноября по Москве = %+v", due)
+	}
+}
+
+func TestGetGroupDigest(t *testing.T) {
+	october := time.Date(2099, time.October, 31, 0, 0, 0, 0, time.UTC)
+	november := time.Date(2099, time.November, 30, 0, 0, 0, 0, time.UTC)
+
+	repo := newCoverageRepo(nil)
+	repo.history = []models.CashbackRule{
+		{ID: 1, Category: "Такси", EffectivePercent: 3, MonthYear: october},
+		{ID: 2, Category: "Аптеки", EffectivePercent: 7, MonthYear: october},
+		{ID: 3, Category: "Такси", EffectivePercent: 5, MonthYear: november},
+		{ID: 4, Category: "такси", EffectivePercent: 10, MonthYear: november},
+		{ID: 5, Category: "Кафе", EffectivePercent: 2, MonthYear: november},
+		{ID: 6, Category: "Пятёрочка", Merchant: "Пятёрочка", EffectivePercent: 15, MonthYear: november},
+	}
+
+	digest, err := NewService(repo).GetGroupDigest(context.Background(), "Семья", "2099-10")
+	if err != nil {
+		t.Fatalf("GetGroupDigest() error = %v", err)
+	}
+	if digest.Earned != nil {
+		t.Errorf("GetGroupDigest() без покупок заработано = %+v", digest.Earned)
+	}
+
+	repo.spends = []models.Spend{
+		{GroupName: "Семья", UserID: "1", Category: "Такси", Cashback: 50, SpentAt: time.Date(2099, time.October, 3, 0, 0, 0, 0, time.UTC)},
+		{GroupName: "Семья", UserID: "1", Category: "Кафе", Cashback: 25, SpentAt: time.Date(2099, time.October, 20, 0, 0, 0, 0, time.UTC)},
+		{GroupName: "Семья", UserID: "1", Cashback: 90, SpentAt: time.Date(2099, time.November, 2, 0, 0, 0, 0, time.UTC)},
+		{GroupName: "Друзья", UserID: "2", Cashback: 40, SpentAt: time.Date(2099, time.October, 5, 0, 0, 0, 0, time.UTC)},
+	}
+	digest, err = NewService(repo).GetGroupDigest(context.Background(), "Семья", "2099-10")
+	if err != nil {
+		t.Fatalf("GetGroupDigest() error = %v", err)
+	}
+	if len(digest.Earned) != 1 || digest.Earned[0].Cashback != 75 || digest.Earned[0].Spends != 2 {
+		t.Errorf("GetGroupDigest() заработано = %+v", digest.Earned)
+	}
+
+	if !digest.Month.Equal(monthStart(october)) || !digest.NextMonth.Equal(monthStart(november)) {
+		t.Errorf("GetGroupDigest() месяцы = %v, %v", digest.Month, digest.NextMonth)
+	}
+	if len(digest.Expired) != 2 || digest.Expired[0].ID != 2 {
+		t.Errorf("GetGroupDigest() закончившиеся = %+v", digest.Expired)
+	}
+	if len(digest.Best) != 2 || digest.Best[0].ID != 4 || digest.Best[1].ID != 5 {
+		t.Errorf("GetGroupDigest() лучшие = %+v", digest.Best)
+	}
+
+	if _, err := NewService(repo).GetGroupDigest(context.Background(), "Чужие", ""); !errors.Is(err, ErrGroupNotExists) {
+		t.Errorf("GetGroupDigest() для несуществующей группы error = %v", err)
+	}
+}
+
+func TestClaimGroupDigestOnlyOnce(t *testing.T) {
+	repo := &digestRepo{coverageRepo: newCoverageRepo(nil), sent: make(map[string]time.Time)}
+	svc := NewService(repo)
+	req := &models.DigestClaimRequest{MonthYear: "2099-10"}
+
+	if _, err := svc.ClaimGroupDigest(context.Background(), "Семья", req); err != nil {
+		t.Fatalf("ClaimGroupDigest() error = %v", err)
+	}
+	if _, err := svc.ClaimGroupDigest(context.Background(), "Семья", req); !errors.Is(err, ErrDigestAlreadySent) {
+		t.Errorf("повторный ClaimGroupDigest() error = %v, ожидалась ErrDigestAlreadySent", err)
+	}
+}
+
+func TestSetDigestSettingsValidation(t *testing.T) {
+	svc := NewService(newCoverageRepo(nil))
+
+	for _, req := range []models.DigestSettingsRequest{
+		{Day: 29, Time: "19:00", Enabled: true},
+		{Day: 5, Time: "25:00", Enabled: true},
+	} {
+		if _, err := svc.SetDigestSettings(context.Background(), "Семья", &req); err == nil {
+			t.Errorf("SetDigestSettings(%+v) ожидалась ошибка", req)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// DuplicateRuleError описывает конфликт нового правила с уже сохранённым:
+// тот же пользователь, банк, категория и магазин в пересекающемся периоде.
+type DuplicateRuleError struct {
+	Existing  *models.CashbackRule
+	Identical bool // совпадают также процент, лимит, программа и условия
+}
+
+// Error возвращает описание конфликта.
+func (e *DuplicateRuleError) Error() string {
+	if e.Identical {
+		return fmt.Sprintf("такое правило уже существует (ID: %d)", e.Existing.ID)
+	}
+	return fmt.Sprintf("правило для %s — %s на этот период уже существует (ID: %d, %.2f%%)",
+		e.Existing.BankName, e.Existing.Category, e.Existing.ID, e.Existing.CashbackPercent)
+}
+
+// Unwrap позволяет проверять конфликт через errors.Is(err, ErrDuplicateRule).
+func (e *DuplicateRuleError) Unwrap() error {
+	return ErrDuplicateRule
+}
+
+// findDuplicate ищет сохранённое правило пользователя, конфликтующее с новым.
+// Правило действует в течение месяца, поэтому периоды пересекаются,
+// если совпадает месяц окончания.
+func (s *Service) findDuplicate(ctx context.Context, rule *models.CashbackRule) (*DuplicateRuleError, error) {
+	existing, err := s.repo.ListUserCashback(ctx, rule.UserID, monthStart(rule.MonthYear))
+	if err != nil {
+		return nil, fmt.Errorf("поиск дубликатов: %w", err)
+	}
+
+	key := ruleKey(rule.BankName, rule.Category, rule.MonthYear)
+	var conflict *DuplicateRuleError
+
+	for i := range existing {
+		candidate := &existing[i]
+		if ruleKey(candidate.BankName, candidate.Category, candidate.MonthYear) != key ||
+			canonicalName(candidate.Merchant) != canonicalName(rule.Merchant) ||
+			differentCards(candidate.Card, rule.Card) {
+			continue
+		}
+
+		identical := candidate.CashbackPercent == rule.CashbackPercent && candidate.MaxAmount == rule.MaxAmount &&
+			candidate.RewardProgram == rule.RewardProgram && sameConditions(candidate.Conditions, rule.Conditions)
+		// Полное совпадение важнее частичного: о нём сообщаем в первую очередь
+		if conflict == nil || (identical && !conflict.Identical) {
+			conflict = &DuplicateRuleError{Existing: candidate, Identical: identical}
+		}
+	}
+
+	return conflict, nil
+}
+
+// differentCards сообщает, что правила относятся к разным картам одного банка.
+// Правило без карты конфликтует с правилом любой карты.
+func differentCards(a, b *models.Card) bool {
+	return a != nil && b != nil && a.ID != b.ID
+}
+
+// ruleKey — ключ для поиска дубликатов правил одного пользователя:
+// банк, каноническая категория и месяц действия.
+func ruleKey(bankName, category string, monthYear time.Time) string {
+	return canonicalName(bankName) + "|" + canonicalName(category) + "|" + monthYear.Format("2006-01")
+}
+
+// canonicalName приводит название к каноническому виду для сравнения:
+// нижний регистр, "ё" → "е", одиночные пробелы, без точек и запятых по краям.
+func canonicalName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "ё", "е")
+	name = strings.Join(strings.Fields(name), " ")
+	return strings.Trim(name, " .,;")
+}
+
+// monthStart возвращает первый день месяца указанной даты.
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/importer"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ImportCashback импортирует правила кэшбэка из CSV/XLSX файла.
+//
+// Каждая строка проверяется так же, как при создании правила через API.
+// Строки, совпадающие с уже сохранёнными правилами пользователя или с
+// предыдущими строками файла (банк, категория, месяц), считаются дубликатами
+// и пропускаются. В режиме DryRun возвращается только отчёт, иначе все
+// корректные строки сохраняются в одной транзакции.
+func (s *Service) ImportCashback(ctx context.Context, req *models.ImportRequest) (*models.ImportReport, error) {
+	table, err := importer.ReadTable(req.FileName, req.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+
+	header := table[0]
+	mapping := importer.DetectMapping(header)
+	if err := mapping.ApplyOverrides(header, req.Mapping); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImport, err)
+	}
+	if missing := mapping.Missing(); len(missing) > 0 {
+		names := make([]string, len(missing))
+		for i, column := range missing {
+			names[i] = string(column)
+		}
+		return nil, fmt.Errorf("%w: не найдены колонки %s", ErrInvalidImport, strings.Join(names, ", "))
+	}
+
+	now := time.Now()
+	existing, err := s.repo.ListUserCashback(ctx, req.UserID,
+		monthStart(now))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, rule := range existing {
+		// Предложения магазинов не конфликтуют с правилами на категорию
+		if rule.Merchant == "" {
+			seen[ruleKey(rule.BankName, rule.Category, rule.MonthYear)] = true
+		}
+	}
+
+	report := &models.ImportReport{
+		DryRun:    req.DryRun,
+		Headers:   header,
+		Mapping:   mapping.Headers(header),
+		TotalRows: len(table) - 1,
+		Rows:      make([]models.ImportRow, 0, len(table)-1),
+	}
+
+	var rules []*models.CashbackRule
+	var ruleRows []int
+
+	for i, cells := range table[1:] {
+		row := s.parseImportRow(req, mapping, cells, now)
+		row.Row = i + 2
+
+		if row.Status == models.ImportRowValid {
+			monthYear, _ := validator.ValidateMonthYear(row.Rule.MonthYear)
+			key := ruleKey(row.Rule.BankName, row.Rule.Category, monthYear)
+			if seen[key] {
+				row.Status = models.ImportRowDuplicate
+				row.Errors = []string{"такое правило уже есть"}
+			} else {
+				seen[key] = true
+				rules = append(rules, &models.CashbackRule{
+					GroupName:       row.Rule.GroupName,
+					Category:        row.Rule.Category,
+					BankName:        row.Rule.BankName,
+					UserID:          row.Rule.UserID,
+					UserDisplayName: row.Rule.UserDisplayName,
+					MonthYear:       monthYear,
+					CashbackPercent: validator.RoundToTwoDecimals(row.Rule.CashbackPercent),
+					MaxAmount:       validator.RoundToTwoDecimals(row.Rule.MaxAmount),
+					RewardProgram:   models.DefaultRewardProgram,
+				})
+				ruleRows = append(ruleRows, len(report.Rows))
+			}
+		}
+
+		switch row.Status {
+		case models.ImportRowValid:
+			report.ValidRows++
+		case models.ImportRowDuplicate:
+			report.DuplicateRows++
+		case models.ImportRowError:
+			report.ErrorRows++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	if req.DryRun || len(rules) == 0 {
+		return report, nil
+	}
+
+	if err := s.repo.CreateMany(ctx, rules); err != nil {
+		return nil, fmt.Errorf("импорт правил: %w", err)
+	}
+
+	for i, rule := range rules {
+		row := &report.Rows[ruleRows[i]]
+		row.Status = models.ImportRowCreated
+		row.ID = rule.ID
+	}
+	report.Created = len(rules)
+
+	return report, nil
+}
+
+// parseImportRow разбирает и валидирует одну строку файла.
+func (s *Service) parseImportRow(req *models.ImportRequest, mapping importer.Mapping, cells []string, now time.Time) models.ImportRow {
+	rule := &models.CreateCashbackRequest{
+		GroupName:       req.GroupName,
+		UserID:          req.UserID,
+		UserDisplayName: req.UserDisplayName,
+		BankName:        mapping.Value(cells, importer.ColumnBank),
+		Category:        mapping.Value(cells, importer.ColumnCategory),
+	}
+	row := models.ImportRow{Status: models.ImportRowValid, Rule: rule}
+
+	var errs []string
+	if value := mapping.Value(cells, importer.ColumnPercent); value != "" {
+		percent, err := importer.ParsePercent(value)
+		if err != nil {
+			errs = append(errs, "процент: "+err.Error())
+		}
+		rule.CashbackPercent = percent
+	}
+
+	if value := mapping.Value(cells, importer.ColumnMaxAmount); value != "" {
+		amount, err := importer.ParseNumber(value)
+		if err != nil {
+			errs = append(errs, "лимит: "+err.Error())
+		}
+		rule.MaxAmount = amount
+	}
+
+	monthYear, err := importer.ParseMonthYear(mapping.Value(cells, importer.ColumnMonthYear), now)
+	if err != nil {
+		errs = append(errs, "дата: "+err.Error())
+	}
+	rule.MonthYear = monthYear
+
+	if len(errs) == 0 {
+		for _, validationErr := range validator.ValidateCreateRequest(
+			rule.GroupName, rule.Category, rule.BankName, rule.UserID,
+			rule.UserDisplayName, rule.MonthYear, rule.CashbackPercent, rule.MaxAmount,
+		) {
+			errs = append(errs, validationErr.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		row.Status = models.ImportRowError
+		row.Errors = errs
+	}
+	return row
+}
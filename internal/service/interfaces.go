@@ -15,7 +15,10 @@ type ServiceInterface interface {
 	UpdateCashback(ctx context.Context, id int64, req *models.UpdateCashbackRequest) error
 	DeleteCashback(ctx context.Context, id int64) error
 	ListCashback(ctx context.Context, req *models.ListCashbackRequest) (*models.ListCashbackResponse, error)
-	GetBestCashback(ctx context.Context, req *models.BestCashbackRequest) (*models.CashbackRule, error)
+	GetBestCashback(ctx context.Context, req *models.BestCashbackRequest) (*models.BestCashbackResponse, error)
+	ImportCashback(ctx context.Context, req *models.ImportRequest) (*models.ImportReport, error)
+	ExportCashback(ctx context.Context, groupName string) ([]models.CashbackRule, error)
+	BatchCashback(ctx context.Context, req *models.BatchRequest) (*models.BatchResponse, error)
 
 	// Группы
 	CreateGroup(ctx context.Context, groupName, creatorID string) error
@@ -24,6 +27,63 @@ type ServiceInterface interface {
 	GroupExists(ctx context.Context, groupName string) (bool, error)
 	GetAllGroups(ctx context.Context) ([]string, error)
 	GetGroupMembers(ctx context.Context, groupName string) ([]string, error)
+	GetGroupCoverage(ctx context.Context, req *models.CoverageRequest) (*models.CoverageReport, error)
+	GetGroupStats(ctx context.Context, req *models.StatsRequest) (*models.GroupStats, error)
+	GetGroupHistory(ctx context.Context, req *models.HistoryRequest) ([]models.CashbackRule, error)
+	GetBankTrend(ctx context.Context, req *models.HistoryRequest) (*models.BankTrend, error)
+
+	// Ежемесячные сводки
+	GetDigestSettings(ctx context.Context, groupName string) (*models.DigestSettings, error)
+	SetDigestSettings(ctx context.Context, groupName string, req *models.DigestSettingsRequest) (*models.DigestSettings, error)
+	ListDueDigests(ctx context.Context) ([]models.DigestDelivery, error)
+	GetGroupDigest(ctx context.Context, groupName, monthYear string) (*models.GroupDigest, error)
+	ClaimGroupDigest(ctx context.Context, groupName string, req *models.DigestClaimRequest) (*models.GroupDigest, error)
+
+	// Просьбы оплатить и долги
+	CreatePaymentRequest(ctx context.Context, groupName string, req *models.CreatePaymentRequest) (*models.PaymentRequest, error)
+	AnswerPaymentRequest(ctx context.Context, id int64, req *models.PaymentAnswerRequest) (*models.PaymentRequest, error)
+	GetGroupBalances(ctx context.Context, groupName string) (*models.GroupBalances, error)
+	SettleDebt(ctx context.Context, groupName string, req *models.SettleDebtRequest) (*models.SettleDebtResponse, error)
+
+	// Общие лимиты банков и журнал трат
+	ListBankCaps(ctx context.Context, userID string) ([]models.BankCapUsage, error)
+	SetBankCap(ctx context.Context, userID, bankName string, req *models.BankCapRequest) (*models.BankCap, error)
+	DeleteBankCap(ctx context.Context, userID, bankName string) error
+	RecordSpend(ctx context.Context, req *models.CreateSpendRequest) (*models.SpendResponse, error)
+
+	// Активация категорий в приложении банка
+	ActivateCashback(ctx context.Context, id int64, req *models.ActivateCashbackRequest) (*models.CashbackRule, error)
+	ListDueActivations(ctx context.Context) ([]models.CashbackRule, error)
+	ClaimActivationReminder(ctx context.Context, id int64) (*models.CashbackRule, error)
+
+	// Программы вознаграждения
+	ListRewardPrograms(ctx context.Context) ([]models.RewardProgram, error)
+	SetRewardProgram(ctx context.Context, code string, req *models.RewardProgramRequest) (*models.RewardProgram, error)
+
+	// Справочник магазинов
+	ListMerchants(ctx context.Context) ([]models.Merchant, error)
+	SetMerchant(ctx context.Context, name string, req *models.MerchantRequest) (*models.Merchant, error)
+	GetMerchantBestCashback(ctx context.Context, req *models.MerchantBestRequest) (*models.MerchantBestResponse, error)
+
+	// Меню категорий банков и советник
+	SetOfferMenu(ctx context.Context, bankName string, req *models.OfferMenuRequest) (*models.OfferMenu, error)
+	ListOfferMenus(ctx context.Context, monthYear string) ([]models.OfferMenu, error)
+	AdviseCategories(ctx context.Context, req *models.CategoryAdviceRequest) (*models.CategoryAdviceResponse, error)
+
+	// Дерево категорий
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	SetCategory(ctx context.Context, name string, req *models.CategoryRequest) (*models.Category, error)
+	GetCategoryPath(ctx context.Context, category string) ([]string, error)
+
+	// Карты
+	CreateCard(ctx context.Context, req *models.CreateCardRequest) (*models.Card, error)
+	ListUserCards(ctx context.Context, userID string) ([]models.Card, error)
+	DeleteCard(ctx context.Context, id int64, userID string) error
+
+	// Групповые чаты
+	BindChat(ctx context.Context, chatID int64, req *models.BindChatRequest) (*models.ChatBinding, error)
+	GetChatBinding(ctx context.Context, chatID int64) (*models.ChatBinding, error)
+	UnbindChat(ctx context.Context, chatID int64) error
 }
 
 // Проверка реализации интерфейса.
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ListMerchants возвращает справочник магазинов.
+func (s *Service) ListMerchants(ctx context.Context) ([]models.Merchant, error) {
+	return s.repo.ListMerchants(ctx)
+}
+
+// SetMerchant добавляет магазин в справочник или меняет его категорию.
+func (s *Service) SetMerchant(ctx context.Context, name string, req *models.MerchantRequest) (*models.Merchant, error) {
+	var validationErrors validator.ValidationErrors
+
+	name = strings.TrimSpace(name)
+	if err := validator.ValidateTextField("name", name, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("category", req.Category, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	aliases := make([]string, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			continue
+		}
+		if err := validator.ValidateTextField("aliases", alias, true); err != nil {
+			validationErrors = append(validationErrors, err.(validator.ValidationError))
+			break
+		}
+		aliases = append(aliases, alias)
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	merchant := &models.Merchant{
+		Name:     name,
+		Category: strings.TrimSpace(req.Category),
+		Aliases:  aliases,
+	}
+	if err := s.repo.UpsertMerchant(ctx, merchant); err != nil {
+		return nil, err
+	}
+
+	return merchant, nil
+}
+
+// findMerchant ищет магазин в справочнике; отсутствие магазина не ошибка.
+func (s *Service) findMerchant(ctx context.Context, name string) (*models.Merchant, error) {
+	merchant, err := s.repo.FindMerchant(ctx, strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return merchant, nil
+}
+
+// resolveRuleMerchant определяет магазин нового правила.
+// Явно указанный магазин должен быть в справочнике; без категории правило
+// получает категорию магазина. Если магазин не указан, но категория совпадает
+// с магазином из справочника ("Сбер, Пятёрочка, 7%"), правило становится
+// предложением этого магазина.
+func (s *Service) resolveRuleMerchant(ctx context.Context, req *models.CreateCashbackRequest) error {
+	if req.Merchant == "" {
+		merchant, err := s.findMerchant(ctx, req.Category)
+		if err != nil || merchant == nil {
+			return err
+		}
+		req.Merchant = merchant.Name
+		req.Category = merchant.Category
+		return nil
+	}
+
+	merchant, err := s.findMerchant(ctx, req.Merchant)
+	if err != nil {
+		return err
+	}
+	if merchant == nil {
+		return fmt.Errorf("магазин \"%s\": %w", req.Merchant, ErrUnknownMerchant)
+	}
+
+	req.Merchant = merchant.Name
+	if strings.TrimSpace(req.Category) == "" {
+		req.Category = merchant.Category
+	}
+	return nil
+}
+
+// GetMerchantBestCashback подбирает лучший кэшбэк для покупки в магазине.
+// Рассматриваются предложения самого магазина, кэшбэк на категорию магазина,
+// её родителей в дереве категорий и на "Все покупки"; выигрывает самый
+// выгодный по эффективному проценту, при равенстве — более конкретный.
+// Правило владельца, исчерпавшего общий лимит банка, выигрывает, только
+// если других подходящих правил нет.
+func (s *Service) GetMerchantBestCashback(ctx context.Context, req *models.MerchantBestRequest) (*models.MerchantBestResponse, error) {
+	if err := validator.ValidateTextField("group_name", req.GroupName, true); err != nil {
+		return nil, err
+	}
+	if err := validator.ValidateTextField("merchant", req.Merchant, true); err != nil {
+		return nil, err
+	}
+
+	monthYear, err := validator.ValidateMonthYear(req.MonthYear)
+	if err != nil {
+		return nil, err
+	}
+
+	purchase := req.Purchase
+	if validationErrors := validator.ValidatePurchaseContext(
+		purchase.Amount, purchase.MonthlySpend, purchase.Weekday, purchase.PaymentMethod,
+	); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	merchant, err := s.findMerchant(ctx, req.Merchant)
+	if err != nil {
+		return nil, err
+	}
+	if merchant == nil {
+		return nil, fmt.Errorf("магазин \"%s\": %w", req.Merchant, ErrUnknownMerchant)
+	}
+
+	resp := &models.MerchantBestResponse{Merchant: *merchant}
+
+	path, err := s.categoryPath(ctx, merchant.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	// Уровни в порядке убывания конкретности: магазин, затем дерево его категории
+	offers, err := s.repo.GetAllCashbackByMerchant(ctx, req.GroupName, merchant.Name, monthYear)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.considerMerchantLevel(ctx, resp, offers, models.MatchMerchant, monthYear, purchase); err != nil {
+		return nil, err
+	}
+
+	for level, category := range path {
+		rules, err := s.repo.GetAllCashbackByCategory(ctx, req.GroupName, category, monthYear)
+		if err != nil {
+			return nil, err
+		}
+
+		matchedBy := models.MatchCategory
+		if level == len(path)-1 {
+			matchedBy = models.MatchAllPurchases
+		}
+		if err := s.considerMerchantLevel(ctx, resp, rules, matchedBy, monthYear, purchase); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// considerMerchantLevel сравнивает лучшее подходящее правило уровня с уже
+// найденным с учётом общих лимитов банков владельцев.
+func (s *Service) considerMerchantLevel(ctx context.Context, resp *models.MerchantBestResponse, rules []models.CashbackRule, matchedBy string, monthYear time.Time, purchase models.PurchaseContext) error {
+	rule, err := s.firstAllowedWithinCap(ctx, rules, monthYear, purchase, "")
+	if err != nil || rule == nil {
+		return err
+	}
+	if resp.Rule == nil || capAwareBetter(rule, resp.Rule) {
+		resp.Rule = rule
+		resp.MatchedBy = matchedBy
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// merchantRepo дополняет rewardRepo справочником магазинов и их предложениями.
+type merchantRepo struct {
+	*rewardRepo
+	merchants map[string]models.Merchant
+	offers    map[string][]models.CashbackRule
+}
+
+func newMerchantRepo() *merchantRepo {
+	return &merchantRepo{
+		rewardRepo: &rewardRepo{memoryRepo: newMemoryRepo(), best: map[string][]models.CashbackRule{}},
+		merchants: map[string]models.Merchant{
+			"пятёрочка": {Name: "Пятёрочка", Category: "Супермаркеты", Aliases: []string{"Пятерочка"}},
+		},
+		offers: map[string][]models.CashbackRule{},
+	}
+}
+
+func (r *merchantRepo) FindMerchant(ctx context.Context, name string) (*models.Merchant, error) {
+	for _, m := range r.merchants {
+		if strings.EqualFold(m.Name, name) {
+			return &m, nil
+		}
+		for _, alias := range m.Aliases {
+			if strings.EqualFold(alias, name) {
+				return &m, nil
+			}
+		}
+	}
+	return nil, database.ErrNotFound
+}
+
+func (r *merchantRepo) GetAllCashbackByMerchant(ctx context.Context, groupName, merchant string, monthYear time.Time) ([]models.CashbackRule, error) {
+	return r.offers[merchant], nil
+}
+
+func TestGetMerchantBestCashbackLevels(t *testing.T) {
+	offer := models.CashbackRule{ID: 1, Category: "Супермаркеты", Merchant: "Пятёрочка", EffectivePercent: 7}
+	category := models.CashbackRule{ID: 2, Category: "Супермаркеты", EffectivePercent: 5}
+	allPurchases := models.CashbackRule{ID: 3, Category: "Все покупки", EffectivePercent: 1.5}
+
+	tests := []struct {
+		name          string
+		offers        []models.CashbackRule
+		category      []models.CashbackRule
+		wantID        int64
+		wantMatchedBy string
+	}{
+		{"предложение магазина", []models.CashbackRule{offer}, []models.CashbackRule{category}, 1, models.MatchMerchant},
+		{"категория магазина", nil, []models.CashbackRule{category}, 2, models.MatchCategory},
+		{"все покупки", nil, nil, 3, models.MatchAllPurchases},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMerchantRepo()
+			repo.offers["Пятёрочка"] = tt.offers
+			repo.best["Супермаркеты"] = tt.category
+			repo.best["Все покупки"] = []models.CashbackRule{allPurchases}
+
+			resp, err := NewService(repo).GetMerchantBestCashback(context.Background(), &models.MerchantBestRequest{
+				GroupName: "Семья", Merchant: "пятерочка", MonthYear: "31.12.2099",
+			})
+			if err != nil {
+				t.Fatalf("GetMerchantBestCashback() error = %v", err)
+			}
+			if resp.Rule == nil || resp.Rule.ID != tt.wantID || resp.MatchedBy != tt.wantMatchedBy {
+				t.Errorf("GetMerchantBestCashback() = %+v, ожидалось правило %d (%s)", resp, tt.wantID, tt.wantMatchedBy)
+			}
+		})
+	}
+}
+
+func TestGetMerchantBestCashbackSkipsExhaustedBankCap(t *testing.T) {
+	repo := newMerchantRepo()
+	repo.offers["Пятёрочка"] = []models.CashbackRule{
+		{ID: 1, UserID: "1", BankName: "Тинькофф", Category: "Супермаркеты", Merchant: "Пятёрочка", EffectivePercent: 7},
+	}
+	repo.best["Супермаркеты"] = []models.CashbackRule{{ID: 2, UserID: "2", BankName: "Альфа", Category: "Супермаркеты", EffectivePercent: 5}}
+	repo.best["Все покупки"] = nil
+	repo.caps = []models.BankCap{{UserID: "1", BankName: "Тинькофф", Period: models.BankCapPeriodMonth, Amount: 500}}
+	repo.spends = []models.Spend{{UserID: "1", BankName: "Тинькофф", Cashback: 500,
+		SpentAt: time.Date(2099, time.December, 10, 0, 0, 0, 0, time.UTC)}}
+	svc := NewService(repo)
+	req := &models.MerchantBestRequest{GroupName: "Семья", Merchant: "Пятёрочка", MonthYear: "31.12.2099"}
+
+	resp, err := svc.GetMerchantBestCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetMerchantBestCashback() error = %v", err)
+	}
+	if resp.Rule == nil || resp.Rule.ID != 2 || resp.MatchedBy != models.MatchCategory {
+		t.Errorf("GetMerchantBestCashback() = %+v, ожидалось правило 2: лимит Тинькофф исчерпан", resp.Rule)
+	}
+
+	// Без других вариантов остаётся предложение с исчерпанным лимитом — с пометкой
+	repo.best["Супермаркеты"] = nil
+	resp, err = svc.GetMerchantBestCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("GetMerchantBestCashback() error = %v", err)
+	}
+	if resp.Rule == nil || resp.Rule.ID != 1 || !resp.Rule.BankCap.Exhausted() {
+		t.Errorf("GetMerchantBestCashback() = %+v", resp.Rule)
+	}
+}
+
+func TestGetMerchantBestCashbackUnknownMerchant(t *testing.T) {
+	_, err := NewService(newMerchantRepo()).GetMerchantBestCashback(context.Background(), &models.MerchantBestRequest{
+		GroupName: "Семья", Merchant: "Неизвестный", MonthYear: "31.12.2099",
+	})
+	if !errors.Is(err, ErrUnknownMerchant) {
+		t.Errorf("GetMerchantBestCashback() error = %v, ожидалась ErrUnknownMerchant", err)
+	}
+}
+
+func TestCreateCashbackDetectsMerchantByCategory(t *testing.T) {
+	svc := NewService(newMerchantRepo())
+
+	req := createOp("Сбер", "Пятерочка", 7).Create
+	rule, err := svc.CreateCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateCashback() error = %v", err)
+	}
+	if rule.Merchant != "Пятёрочка" || rule.Category != "Супермаркеты" {
+		t.Errorf("CreateCashback() = магазин %q, категория %q; ожидалось Пятёрочка, Супермаркеты",
+			rule.Merchant, rule.Category)
+	}
+
+	// Правило на категорию того же банка — не дубликат предложения магазина
+	if _, err := svc.CreateCashback(context.Background(), createOp("Сбер", "Супермаркеты", 5).Create); err != nil {
+		t.Errorf("CreateCashback() для категории error = %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// CreatePaymentRequest создаёт просьбу оплатить покупку картой из правила кэшбэка.
+// Платит владелец правила; просить может любой другой участник группы.
+func (s *Service) CreatePaymentRequest(ctx context.Context, groupName string, req *models.CreatePaymentRequest) (*models.PaymentRequest, error) {
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+
+	var validationErrors validator.ValidationErrors
+
+	requesterName := strings.TrimSpace(req.RequesterName)
+
+	if err := validator.ValidateTextField("requester_id", req.RequesterID, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("requester_name", requesterName, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidatePaymentAmount(req.Amount); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	requesterGroup, err := s.repo.GetUserGroup(ctx, req.RequesterID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, err
+	}
+	if requesterGroup != groupName {
+		return nil, fmt.Errorf("группа \"%s\": %w", groupName, ErrNotGroupMember)
+	}
+
+	rule, err := s.repo.GetByID(ctx, req.RuleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule.GroupName != groupName {
+		return nil, fmt.Errorf("правило %d в группе \"%s\": %w", req.RuleID, groupName, database.ErrNotFound)
+	}
+	if rule.UserID == req.RequesterID {
+		return nil, fmt.Errorf("ошибки валидации: rule_id: нельзя просить оплатить своей же картой")
+	}
+
+	ruleID := rule.ID
+	payment := &models.PaymentRequest{
+		GroupName:     groupName,
+		RuleID:        &ruleID,
+		RequesterID:   req.RequesterID,
+		RequesterName: requesterName,
+		PayerID:       rule.UserID,
+		PayerName:     rule.UserDisplayName,
+		BankName:      rule.BankName,
+		Category:      rule.Category,
+		Amount:        roundCents(req.Amount),
+	}
+	if err := s.repo.CreatePaymentRequest(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// AnswerPaymentRequest записывает ответ владельца карты. Принятая просьба
+// становится долгом того, кто просил, перед владельцем карты.
+func (s *Service) AnswerPaymentRequest(ctx context.Context, id int64, req *models.PaymentAnswerRequest) (*models.PaymentRequest, error) {
+	payment, err := s.repo.GetPaymentRequest(ctx, id)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return nil, fmt.Errorf("просьба %d: %w", id, ErrUnknownPayment)
+		}
+		return nil, err
+	}
+
+	if payment.PayerID != req.UserID {
+		return nil, fmt.Errorf("просьба %d: %w", id, ErrNotPayer)
+	}
+
+	status := models.PaymentStatusDeclined
+	if req.Accept {
+		status = models.PaymentStatusAccepted
+	}
+
+	// Принятая просьба — покупка по карте владельца: записываем её в журнал
+	// трат вместе с ответом, чтобы учесть кэшбэк в лимитах банка
+	err = s.repo.WithTx(ctx, func(tx database.RepositoryInterface) error {
+		resolved, err := tx.ResolvePaymentRequest(ctx, payment, status)
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			return fmt.Errorf("просьба %d: %w", id, ErrPaymentAnswered)
+		}
+
+		if status != models.PaymentStatusAccepted || payment.RuleID == nil {
+			return nil
+		}
+		rule, err := tx.GetByID(ctx, *payment.RuleID)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		_, err = recordSpend(ctx, tx, rule, payment.GroupName, payment.Amount, &payment.ID, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// GetGroupBalances возвращает долги участников группы после взаимозачёта.
+func (s *Service) GetGroupBalances(ctx context.Context, groupName string) (*models.GroupBalances, error) {
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+
+	payments, err := s.repo.ListAcceptedPayments(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GroupBalances{GroupName: groupName, Debts: groupDebts(payments)}, nil
+}
+
+// SettleDebt закрывает долг участника после возврата денег.
+// Подтвердить возврат может только тот, кому должны. Закрываются только
+// оплаты показанного долга — не позже req.LastPaymentID; если с тех пор
+// долг изменился, возвращается ErrDebtChanged.
+func (s *Service) SettleDebt(ctx context.Context, groupName string, req *models.SettleDebtRequest) (*models.SettleDebtResponse, error) {
+	if err := s.checkGroup(ctx, groupName); err != nil {
+		return nil, err
+	}
+	if req.LastPaymentID <= 0 {
+		return nil, fmt.Errorf("ошибки валидации: last_payment_id: укажите последнюю оплату долга")
+	}
+
+	var result *models.SettleDebtResponse
+	err := s.repo.WithTx(ctx, func(tx database.RepositoryInterface) error {
+		payments, err := tx.ListAcceptedPayments(ctx, groupName)
+		if err != nil {
+			return err
+		}
+
+		for _, debt := range groupDebts(payments) {
+			if debt.CreditorID != req.UserID || debt.DebtorID != req.DebtorID {
+				continue
+			}
+			if debt.LastPaymentID != req.LastPaymentID || debt.Amount != roundCents(req.Amount) {
+				return fmt.Errorf("участник %s должен участнику %s %.2f: %w", req.DebtorID, req.UserID, debt.Amount, ErrDebtChanged)
+			}
+
+			settled, err := tx.SettlePayments(ctx, groupName, debt.DebtorID, debt.CreditorID, debt.LastPaymentID)
+			if err != nil {
+				return err
+			}
+			result = &models.SettleDebtResponse{Settled: settled, Amount: debt.Amount}
+			return nil
+		}
+
+		return fmt.Errorf("участник %s не должен участнику %s: %w", req.DebtorID, req.UserID, ErrNoDebt)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// groupDebts сводит оплаты в долги: для каждой пары участников встречные
+// оплаты взаимно вычитаются, остаётся один долг в одну сторону.
+func groupDebts(payments []models.PaymentRequest) []models.Debt {
+	type pair struct{ a, b string }
+
+	names := make(map[string]string)
+	net := make(map[pair]float64) // сколько a должен b
+	counts := make(map[pair]int)
+	last := make(map[pair]int64)
+	var order []pair
+
+	for _, p := range payments {
+		names[p.RequesterID] = p.RequesterName
+		names[p.PayerID] = p.PayerName
+
+		key, amount := pair{p.RequesterID, p.PayerID}, p.Amount
+		if key.a > key.b {
+			key, amount = pair{key.b, key.a}, -amount
+		}
+		if _, seen := net[key]; !seen {
+			order = append(order, key)
+		}
+		net[key] += amount
+		counts[key]++
+		if p.ID > last[key] {
+			last[key] = p.ID
+		}
+	}
+
+	debts := make([]models.Debt, 0, len(order))
+	for _, key := range order {
+		amount := roundCents(net[key])
+		debtor, creditor := key.a, key.b
+		if amount < 0 {
+			debtor, creditor, amount = key.b, key.a, -amount
+		}
+		if amount == 0 {
+			continue
+		}
+
+		debts = append(debts, models.Debt{
+			DebtorID:      debtor,
+			DebtorName:    names[debtor],
+			CreditorID:    creditor,
+			CreditorName:  names[creditor],
+			Amount:        amount,
+			Payments:      counts[key],
+			LastPaymentID: last[key],
+		})
+	}
+
+	sort.SliceStable(debts, func(i, j int) bool {
+		return debts[i].Amount > debts[j].Amount
+	})
+
+	return debts
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// paymentRepo хранит просьбы оплатить в памяти поверх memoryRepo.
+type paymentRepo struct {
+	*memoryRepo
+	members  map[string]string // пользователь → группа
+	payments []models.PaymentRequest
+}
+
+func newPaymentRepo() *paymentRepo {
+	repo := &paymentRepo{memoryRepo: newMemoryRepo(), members: map[string]string{"1": "Семья", "2": "Семья", "3": "Семья"}}
+	repo.rules[1] = models.CashbackRule{ID: 1, GroupName: "Семья", UserID: "2", UserDisplayName: "Мария", BankName: "Альфа", Category: "Такси"}
+	repo.rules[2] = models.CashbackRule{ID: 2, GroupName: "Друзья", UserID: "9", UserDisplayName: "Пётр", BankName: "Альфа", Category: "Такси"}
+	return repo
+}
+
+// WithTx выполняет fn без копирования: просьбы живут вне memoryRepo.
+func (r *paymentRepo) WithTx(ctx context.Context, fn func(tx database.RepositoryInterface) error) error {
+	return fn(r)
+}
+
+func (r *paymentRepo) GroupExists(ctx context.Context, groupName string) (bool, error) {
+	return groupName == "Семья", nil
+}
+
+func (r *paymentRepo) GetUserGroup(ctx context.Context, userID string) (string, error) {
+	if group, ok := r.members[userID]; ok {
+		return group, nil
+	}
+	return "", database.ErrNotFound
+}
+
+func (r *paymentRepo) CreatePaymentRequest(ctx context.Context, req *models.PaymentRequest) error {
+	req.ID = int64(len(r.payments) + 1)
+	req.Status = models.PaymentStatusPending
+	r.payments = append(r.payments, *req)
+	return nil
+}
+
+func (r *paymentRepo) GetPaymentRequest(ctx context.Context, id int64) (*models.PaymentRequest, error) {
+	if id < 1 || int(id) > len(r.payments) {
+		return nil, database.ErrNotFound
+	}
+	payment := r.payments[id-1]
+	return &payment, nil
+}
+
+func (r *paymentRepo) ResolvePaymentRequest(ctx context.Context, req *models.PaymentRequest, status string) (bool, error) {
+	stored := &r.payments[req.ID-1]
+	if stored.Status != models.PaymentStatusPending {
+		return false, nil
+	}
+	stored.Status, req.Status = status, status
+	return true, nil
+}
+
+func (r *paymentRepo) ListAcceptedPayments(ctx context.Context, groupName string) ([]models.PaymentRequest, error) {
+	var list []models.PaymentRequest
+	for _, p := range r.payments {
+		if p.GroupName == groupName && p.Status == models.PaymentStatusAccepted {
+			list = append(list, p)
+		}
+	}
+	return list, nil
+}
+
+func (r *paymentRepo) SettlePayments(ctx context.Context, groupName, userA, userB string, lastPaymentID int64) (int, error) {
+	settled := 0
+	for i, p := range r.payments {
+		pair := (p.RequesterID == userA && p.PayerID == userB) || (p.RequesterID == userB && p.PayerID == userA)
+		if p.GroupName == groupName && p.Status == models.PaymentStatusAccepted && pair && p.ID <= lastPaymentID {
+			r.payments[i].Status = models.PaymentStatusSettled
+			settled++
+		}
+	}
+	return settled, nil
+}
+
+func TestCreatePaymentRequest(t *testing.T) {
+	repo := newPaymentRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	payment, err := svc.CreatePaymentRequest(ctx, "Семья", &models.CreatePaymentRequest{
+		RuleID: 1, RequesterID: "1", RequesterName: "Иван", Amount: 1500.005,
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentRequest() error = %v", err)
+	}
+	if payment.PayerID != "2" || payment.PayerName != "Мария" || payment.Category != "Такси" || payment.Amount != 1500.01 {
+		t.Errorf("CreatePaymentRequest() = %+v", payment)
+	}
+
+	if _, err := svc.CreatePaymentRequest(ctx, "Семья", &models.CreatePaymentRequest{
+		RuleID: 1, RequesterID: "2", RequesterName: "Мария", Amount: 100,
+	}); err == nil {
+		t.Error("CreatePaymentRequest() своей картой: ожидалась ошибка")
+	}
+
+	if _, err := svc.CreatePaymentRequest(ctx, "Семья", &models.CreatePaymentRequest{
+		RuleID: 2, RequesterID: "1", RequesterName: "Иван", Amount: 100,
+	}); !errors.Is(err, database.ErrNotFound) {
+		t.Errorf("CreatePaymentRequest() правило другой группы: error = %v", err)
+	}
+
+	if _, err := svc.CreatePaymentRequest(ctx, "Семья", &models.CreatePaymentRequest{
+		RuleID: 1, RequesterID: "9", RequesterName: "Пётр", Amount: 100,
+	}); !errors.Is(err, ErrNotGroupMember) {
+		t.Errorf("CreatePaymentRequest() не участник: error = %v", err)
+	}
+}
+
+func TestAnswerPaymentRequest(t *testing.T) {
+	repo := newPaymentRepo()
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	payment, err := svc.CreatePaymentRequest(ctx, "Семья", &models.CreatePaymentRequest{
+		RuleID: 1, RequesterID: "1", RequesterName: "Иван", Amount: 500,
+	})
+	if err != nil {
+		t.Fatalf("CreatePaymentRequest() error = %v", err)
+	}
+
+	if _, err := svc.AnswerPaymentRequest(ctx, payment.ID, &models.PaymentAnswerRequest{UserID: "1", Accept: true}); !errors.Is(err, ErrNotPayer) {
+		t.Errorf("AnswerPaymentRequest() от просившего: error = %v", err)
+	}
+
+	answered, err := svc.AnswerPaymentRequest(ctx, payment.ID, &models.PaymentAnswerRequest{UserID: "2", Accept: true})
+	if err != nil || answered.Status != models.PaymentStatusAccepted {
+		t.Fatalf("AnswerPaymentRequest() = %+v, %v", answered, err)
+	}
+	if len(repo.spends) != 1 || repo.spends[0].UserID != "2" || *repo.spends[0].PaymentRequestID != payment.ID {
+		t.Errorf("AnswerPaymentRequest() должна записать покупку по карте владельца: %+v", repo.spends)
+	}
+
+	if _, err := svc.AnswerPaymentRequest(ctx, payment.ID, &models.PaymentAnswerRequest{UserID: "2"}); !errors.Is(err, ErrPaymentAnswered) {
+		t.Errorf("AnswerPaymentRequest() повторно: error = %v", err)
+	}
+
+	if _, err := svc.AnswerPaymentRequest(ctx, 42, &models.PaymentAnswerRequest{UserID: "2"}); !errors.Is(err, ErrUnknownPayment) {
+		t.Errorf("AnswerPaymentRequest() неизвестная: error = %v", err)
+	}
+}
+
+func TestGroupDebts(t *testing.T) {
+	payments := []models.PaymentRequest{
+		{ID: 1, RequesterID: "1", RequesterName: "Иван", PayerID: "2", PayerName: "Мария", Amount: 1000},
+		{ID: 2, RequesterID: "2", RequesterName: "Мария", PayerID: "1", PayerName: "Иван", Amount: 300},
+		{ID: 3, RequesterID: "3", RequesterName: "Олег", PayerID: "2", PayerName: "Мария", Amount: 2500},
+		{ID: 4, RequesterID: "3", RequesterName: "Олег", PayerID: "1", PayerName: "Иван", Amount: 200},
+		{ID: 5, RequesterID: "1", RequesterName: "Иван", PayerID: "3", PayerName: "Олег", Amount: 200},
+	}
+
+	debts := groupDebts(payments)
+	if len(debts) != 2 {
+		t.Fatalf("groupDebts() = %+v", debts)
+	}
+	if debts[0].DebtorID != "3" || debts[0].CreditorID != "2" || debts[0].Amount != 2500 {
+		t.Errorf("groupDebts()[0] = %+v", debts[0])
+	}
+	if debts[1].DebtorID != "1" || debts[1].CreditorName != "Мария" || debts[1].Amount != 700 || debts[1].Payments != 2 || debts[1].LastPaymentID != 2 {
+		t.Errorf("groupDebts()[1] = %+v", debts[1])
+	}
+}
+
+func TestSettleDebt(t *testing.T) {
+	repo := newPaymentRepo()
+	repo.payments = []models.PaymentRequest{
+		{ID: 1, GroupName: "Семья", RequesterID: "1", PayerID: "2", Amount: 1000, Status: models.PaymentStatusAccepted},
+		{ID: 2, GroupName: "Семья", RequesterID: "2", PayerID: "1", Amount: 300, Status: models.PaymentStatusAccepted},
+		{ID: 3, GroupName: "Семья", RequesterID: "3", PayerID: "2", Amount: 500, Status: models.PaymentStatusAccepted},
+	}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	shown := &models.SettleDebtRequest{UserID: "2", DebtorID: "1", LastPaymentID: 2, Amount: 700}
+
+	if _, err := svc.SettleDebt(ctx, "Семья", &models.SettleDebtRequest{UserID: "1", DebtorID: "2", LastPaymentID: 2, Amount: 700}); !errors.Is(err, ErrNoDebt) {
+		t.Errorf("SettleDebt() подтверждает должник: error = %v", err)
+	}
+
+	// После показа баланса принята новая оплата: старая кнопка не закрывает её
+	repo.payments = append(repo.payments, models.PaymentRequest{
+		ID: 4, GroupName: "Семья", RequesterID: "1", PayerID: "2", Amount: 200, Status: models.PaymentStatusAccepted,
+	})
+	if _, err := svc.SettleDebt(ctx, "Семья", shown); !errors.Is(err, ErrDebtChanged) {
+		t.Errorf("SettleDebt() после новой оплаты: error = %v, ожидалась ErrDebtChanged", err)
+	}
+	if repo.payments[0].Status != models.PaymentStatusAccepted || repo.payments[3].Status != models.PaymentStatusAccepted {
+		t.Errorf("SettleDebt() с устаревшим долгом закрыл оплаты: %+v", repo.payments)
+	}
+	repo.payments = repo.payments[:3]
+
+	result, err := svc.SettleDebt(ctx, "Семья", shown)
+	if err != nil || result.Settled != 2 || result.Amount != 700 {
+		t.Fatalf("SettleDebt() = %+v, %v", result, err)
+	}
+
+	balances, err := svc.GetGroupBalances(ctx, "Семья")
+	if err != nil || len(balances.Debts) != 1 || balances.Debts[0].DebtorID != "3" {
+		t.Errorf("GetGroupBalances() после возврата = %+v, %v", balances, err)
+	}
+}
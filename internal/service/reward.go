@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// ListRewardPrograms возвращает программы вознаграждения с курсами к рублю.
+func (s *Service) ListRewardPrograms(ctx context.Context) ([]models.RewardProgram, error) {
+	return s.repo.ListRewardPrograms(ctx)
+}
+
+// SetRewardProgram создаёт программу вознаграждения или меняет её курс.
+func (s *Service) SetRewardProgram(ctx context.Context, code string, req *models.RewardProgramRequest) (*models.RewardProgram, error) {
+	var validationErrors validator.ValidationErrors
+
+	if err := validator.ValidateRewardProgramCode(code); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("name", req.Name, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateRewardKind(req.Kind); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateRubleRate(req.RubleRate); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	program := &models.RewardProgram{
+		Code:      code,
+		Name:      strings.TrimSpace(req.Name),
+		Kind:      req.Kind,
+		RubleRate: req.RubleRate,
+	}
+	if err := s.repo.UpsertRewardProgram(ctx, program); err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// resolveRewardProgram проверяет код программы правила.
+// Пустой код означает рублёвый кэшбэк.
+func (s *Service) resolveRewardProgram(ctx context.Context, code string) (string, error) {
+	if code == "" {
+		return models.DefaultRewardProgram, nil
+	}
+
+	if err := validator.ValidateRewardProgramCode(code); err != nil {
+		return "", err
+	}
+
+	if _, err := s.repo.GetRewardProgram(ctx, code); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return "", fmt.Errorf("программа \"%s\": %w", code, ErrUnknownRewardProgram)
+		}
+		return "", err
+	}
+
+	return code, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/database"
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// rewardRepo дополняет memoryRepo программами вознаграждения и правилами по категориям.
+type rewardRepo struct {
+	*memoryRepo
+	programs map[string]models.RewardProgram
+	best     map[string][]models.CashbackRule
+}
+
+func (r *rewardRepo) GetRewardProgram(ctx context.Context, code string) (*models.RewardProgram, error) {
+	program, ok := r.programs[code]
+	if !ok {
+		return nil, database.ErrNotFound
+	}
+	return &program, nil
+}
+
+func (r *rewardRepo) GetAllCashbackByCategory(ctx context.Context, groupName, category string, monthYear time.Time) ([]models.CashbackRule, error) {
+	return r.best[category], nil
+}
+
+func TestGetBestCashbackComparesEffectivePercent(t *testing.T) {
+	points := models.CashbackRule{ID: 1, Category: "Такси", CashbackPercent: 10,
+		RewardProgram: "aeroflot_miles", EffectivePercent: 4}
+	rubles := models.CashbackRule{ID: 2, Category: "Все покупки", CashbackPercent: 5,
+		RewardProgram: models.DefaultRewardProgram, EffectivePercent: 5}
+	repo := &rewardRepo{memoryRepo: newMemoryRepo(), best: map[string][]models.CashbackRule{
+		"Такси":       {points},
+		"Все покупки": {rubles},
+	}}
+
+	rule, err := NewService(repo).GetBestCashback(context.Background(), &models.BestCashbackRequest{
+		GroupName: "Семья", Category: "Такси", MonthYear: "31.12.2099",
+	})
+	if err != nil {
+		t.Fatalf("GetBestCashback() error = %v", err)
+	}
+	if rule.ID != rubles.ID {
+		t.Errorf("GetBestCashback() = правило %d, ожидалось %d (5%% ₽ выгоднее 10%% миль по 0.4)", rule.ID, rubles.ID)
+	}
+}
+
+func TestCreateCashbackRewardProgram(t *testing.T) {
+	repo := &rewardRepo{memoryRepo: newMemoryRepo(), programs: map[string]models.RewardProgram{
+		"sber_spasibo": {Code: "sber_spasibo", Name: "СберСпасибо", Kind: models.RewardKindPoints, RubleRate: 1},
+	}}
+	svc := NewService(repo)
+
+	req := createOp("Сбер", "Такси", 5).Create
+	rule, err := svc.CreateCashback(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CreateCashback() error = %v", err)
+	}
+	if rule.RewardProgram != models.DefaultRewardProgram {
+		t.Errorf("RewardProgram = %q, ожидалось %q", rule.RewardProgram, models.DefaultRewardProgram)
+	}
+
+	req = createOp("Сбер", "Аптеки", 5).Create
+	req.RewardProgram = "sber_spasibo"
+	if rule, err = svc.CreateCashback(context.Background(), req); err != nil {
+		t.Fatalf("CreateCashback() error = %v", err)
+	}
+	if rule.RewardProgram != "sber_spasibo" {
+		t.Errorf("RewardProgram = %q, ожидалось sber_spasibo", rule.RewardProgram)
+	}
+
+	req = createOp("Сбер", "Кино", 5).Create
+	req.RewardProgram = "unknown"
+	if _, err = svc.CreateCashback(context.Background(), req); !errors.Is(err, ErrUnknownRewardProgram) {
+		t.Errorf("CreateCashback() error = %v, ожидалась ErrUnknownRewardProgram", err)
+	}
+}
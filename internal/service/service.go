@@ -30,16 +30,41 @@ const (
 // Ошибки сервиса.
 var (
 	ErrGroupNotExists = errors.New("группа не существует")
+	ErrNotGroupMember = errors.New("пользователь не состоит в группе")
+	ErrInvalidImport  = errors.New("некорректный файл импорта")
+	ErrDuplicateRule  = errors.New("правило уже существует")
+	ErrInvalidBatch   = errors.New("некорректный пакетный запрос")
+
+	ErrUnknownRewardProgram = errors.New("неизвестная программа вознаграждения")
+	ErrUnknownMerchant      = errors.New("магазин не найден в справочнике")
+	ErrUnknownCard          = errors.New("карта не найдена")
+	ErrCardMismatch         = errors.New("карта не подходит к правилу")
+	ErrInvalidCategoryTree  = errors.New("некорректное дерево категорий")
+	ErrDigestAlreadySent    = errors.New("сводка за этот месяц уже отправлена")
+	ErrUnknownPayment       = errors.New("просьба оплатить не найдена")
+	ErrNotPayer             = errors.New("ответить может только владелец карты")
+	ErrPaymentAnswered      = errors.New("на просьбу оплатить уже ответили")
+	ErrNoDebt               = errors.New("долга нет")
+	ErrDebtChanged          = errors.New("долг изменился после показа баланса")
+	ErrNotRuleOwner         = errors.New("отметить активацию может только владелец правила")
+	ErrReminderAlreadySent  = errors.New("напоминание об активации уже отправлено")
 )
 
 // Service представляет бизнес-логику приложения.
 type Service struct {
-	repo database.RepositoryInterface
+	repo           database.RepositoryInterface
+	digestLocation *time.Location // часовой пояс расписания сводок
 }
 
 // NewService создаёт новый сервис.
 func NewService(repo database.RepositoryInterface) *Service {
-	return &Service{repo: repo}
+	return &Service{repo: repo, digestLocation: time.Local}
+}
+
+// SetDigestLocation задаёт часовой пояс, в котором читается расписание
+// ежемесячных сводок и определяется их месяц.
+func (s *Service) SetDigestLocation(loc *time.Location) {
+	s.digestLocation = loc
 }
 
 // --- Методы для работы с кэшбэком ---
@@ -98,18 +123,44 @@ func (s *Service) fillSuggestions(ctx context.Context, req *models.SuggestReques
 }
 
 // CreateCashback создаёт новое правило кэшбэка.
+// Если у пользователя уже есть правило того же банка и категории на этот
+// месяц, возвращается *DuplicateRuleError (кроме запросов с Force).
 func (s *Service) CreateCashback(ctx context.Context, req *models.CreateCashbackRequest) (*models.CashbackRule, error) {
+	if err := s.resolveRuleMerchant(ctx, req); err != nil {
+		return nil, err
+	}
+
+	card, err := s.resolveRuleCard(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
 	validationErrors := validator.ValidateCreateRequest(
 		req.GroupName, req.Category, req.BankName, req.UserID,
 		req.UserDisplayName, req.MonthYear, req.CashbackPercent, req.MaxAmount,
 	)
 
+	activation, err := ruleActivation(req.Activation)
+	if err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
 	if len(validationErrors) > 0 {
 		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
 	}
 
 	monthYear, _ := validator.ValidateMonthYear(req.MonthYear)
 
+	rewardProgram, err := s.resolveRewardProgram(ctx, req.RewardProgram)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions, err := normalizeConditions(req.Conditions)
+	if err != nil {
+		return nil, err
+	}
+
 	rule := &models.CashbackRule{
 		GroupName:       req.GroupName,
 		Category:        req.Category,
@@ -119,6 +170,22 @@ func (s *Service) CreateCashback(ctx context.Context, req *models.CreateCashback
 		MonthYear:       monthYear,
 		CashbackPercent: validator.RoundToTwoDecimals(req.CashbackPercent),
 		MaxAmount:       validator.RoundToTwoDecimals(req.MaxAmount),
+		RewardProgram:   rewardProgram,
+		Conditions:      conditions,
+		Merchant:        req.Merchant,
+		Card:            card,
+		Activation:      activation,
+	}
+
+	// Force — сознательное сохранение рядом с существующим правилом
+	if !req.Force {
+		duplicate, err := s.findDuplicate(ctx, rule)
+		if err != nil {
+			return nil, err
+		}
+		if duplicate != nil {
+			return nil, duplicate
+		}
 	}
 
 	if err := s.repo.Create(ctx, rule); err != nil {
@@ -140,6 +207,32 @@ func (s *Service) UpdateCashback(ctx context.Context, id int64, req *models.Upda
 		return err
 	}
 
+	if req.RewardProgram != "" || req.Replace {
+		rewardProgram, err := s.resolveRewardProgram(ctx, req.RewardProgram)
+		if err != nil {
+			return err
+		}
+		updates["reward_program"] = rewardProgram
+	}
+
+	if req.Merchant != "" {
+		merchant, err := s.findMerchant(ctx, req.Merchant)
+		if err != nil {
+			return err
+		}
+		if merchant == nil {
+			return fmt.Errorf("магазин \"%s\": %w", req.Merchant, ErrUnknownMerchant)
+		}
+		updates["merchant"] = merchant.Name
+	}
+
+	if req.CardID != nil {
+		if err := s.checkRuleCard(ctx, id, *req.CardID, req.BankName); err != nil {
+			return err
+		}
+		updates["card_id"] = *req.CardID
+	}
+
 	return s.repo.Update(ctx, id, updates)
 }
 
@@ -183,13 +276,35 @@ func (s *Service) buildUpdates(req *models.UpdateCashbackRequest) (map[string]in
 		updates["cashback_percent"] = validator.RoundToTwoDecimals(req.CashbackPercent)
 	}
 
-	if req.MaxAmount > 0 {
+	// При замене нулевой лимит записывается — правило становится без ограничения
+	if req.MaxAmount > 0 || req.Replace {
 		if err := validator.ValidateMaxAmount(req.MaxAmount); err != nil {
 			return nil, err
 		}
 		updates["max_amount"] = validator.RoundToTwoDecimals(req.MaxAmount)
 	}
 
+	if req.Conditions != nil || req.Replace {
+		conditions, err := normalizeConditions(req.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		updates["conditions"] = conditions
+	}
+
+	// Новое требование активации сбрасывает прежнюю отметку и напоминание;
+	// замена без активации снимает требование
+	if req.Activation != nil || req.Replace {
+		activation, err := ruleActivation(req.Activation)
+		if err != nil {
+			return nil, err
+		}
+		updates["activation_required"] = activation.Required
+		updates["activation_deadline"] = activation.Deadline
+		updates["activated_at"] = nil
+		updates["activation_reminded_at"] = nil
+	}
+
 	return updates, nil
 }
 
@@ -215,6 +330,19 @@ func (s *Service) ListCashback(ctx context.Context, req *models.ListCashbackRequ
 	}, nil
 }
 
+// ExportCashback возвращает все правила группы для выгрузки.
+func (s *Service) ExportCashback(ctx context.Context, groupName string) ([]models.CashbackRule, error) {
+	exists, err := s.repo.GroupExists(ctx, groupName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("группа \"%s\": %w", groupName, ErrGroupNotExists)
+	}
+
+	return s.repo.ListAllByGroup(ctx, groupName)
+}
+
 // normalizePagination нормализует параметры пагинации.
 func (s *Service) normalizePagination(limit, offset int) (int, int) {
 	if limit <= 0 {
@@ -230,8 +358,11 @@ func (s *Service) normalizePagination(limit, offset int) (int, int) {
 	return limit, offset
 }
 
-// GetBestCashback получает правило с лучшим кэшбэком с fallback на "Все покупки".
-func (s *Service) GetBestCashback(ctx context.Context, req *models.BestCashbackRequest) (*models.CashbackRule, error) {
+// GetBestCashback получает правило с лучшим кэшбэком, поднимаясь по дереву
+// категорий до "Все покупки". Уровни сравниваются по эффективному проценту
+// в рублях; при равенстве выигрывает более конкретная категория.
+// Правила, условия которых не подходят под покупку из req.Purchase, пропускаются.
+func (s *Service) GetBestCashback(ctx context.Context, req *models.BestCashbackRequest) (*models.BestCashbackResponse, error) {
 	if err := validator.ValidateTextField("group_name", req.GroupName, true); err != nil {
 		return nil, err
 	}
@@ -244,28 +375,80 @@ func (s *Service) GetBestCashback(ctx context.Context, req *models.BestCashbackR
 		return nil, err
 	}
 
-	// Сначала ищем точное совпадение категории
-	categoryRule, err := s.repo.GetBestCashback(ctx, req.GroupName, req.Category, monthYear)
-	
-	// Ищем кэшбэк на "Все покупки"
-	allPurchasesRule, errAll := s.repo.GetBestCashback(ctx, req.GroupName, "Все покупки", monthYear)
-	
-	// Если нашли точную категорию
-	if err == nil {
-		// Если нашли "Все покупки" и он выгоднее
-		if errAll == nil && allPurchasesRule.CashbackPercent > categoryRule.CashbackPercent {
-			return allPurchasesRule, nil
+	purchase := req.Purchase
+	if validationErrors := validator.ValidatePurchaseContext(
+		purchase.Amount, purchase.MonthlySpend, purchase.Weekday, purchase.PaymentMethod,
+	); len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	path, err := s.categoryPath(ctx, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, match, err := s.bestOnPath(ctx, req.GroupName, req.Category, path, monthYear, purchase, "")
+	if err != nil {
+		return nil, err
+	}
+	best := &models.BestCashbackResponse{CashbackRule: *rule, Match: match}
+
+	if req.UserID == "" {
+		return best, nil
+	}
+	if rule.UserID == req.UserID {
+		best.Personal = &models.PersonalBestCashback{CashbackRule: *rule, Match: match}
+		return best, nil
+	}
+
+	// Своего кэшбэка может не быть — тогда отвечаем только лучшим в группе
+	own, ownMatch, err := s.bestOnPath(ctx, req.GroupName, req.Category, path, monthYear, purchase, req.UserID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			return nil, err
 		}
-		return categoryRule, nil
+		return best, nil
 	}
-	
-	// Если не нашли точную категорию, возвращаем "Все покупки" (если есть)
-	if errAll == nil {
-		return allPurchasesRule, nil
+	best.Personal = &models.PersonalBestCashback{CashbackRule: *own, Match: ownMatch}
+
+	return best, nil
+}
+
+// bestOnPath ищет самое выгодное правило на категории и её родителях из path.
+// Непустой userID оставляет только правила этого пользователя.
+func (s *Service) bestOnPath(ctx context.Context, groupName, requested string, path []string, monthYear time.Time, purchase models.PurchaseContext, userID string) (*models.CashbackRule, models.CategoryMatch, error) {
+	var best *models.CashbackRule
+	var match models.CategoryMatch
+	var notFound error
+
+	for level, category := range path {
+		rule, err := s.bestAllowedCashback(ctx, groupName, category, monthYear, purchase, userID)
+		if err != nil {
+			if !errors.Is(err, database.ErrNotFound) {
+				return nil, match, err
+			}
+			if notFound == nil {
+				notFound = err
+			}
+			continue
+		}
+
+		if best == nil || capAwareBetter(rule, best) {
+			best = rule
+			match = models.CategoryMatch{
+				RequestedCategory: requested,
+				Category:          category,
+				Level:             level,
+				Path:              path,
+			}
+		}
+	}
+
+	// Если ничего не нашли, возвращаем ошибку запрошенной категории
+	if best == nil {
+		return nil, match, notFound
 	}
-	
-	// Если ничего не нашли, возвращаем ошибку от первого запроса
-	return nil, err
+	return best, match, nil
 }
 
 // --- Методы для работы с группами ---
@@ -350,3 +533,34 @@ func (s *Service) GetGroupUsers(ctx context.Context, groupName string) ([]models
 
 	return s.repo.GetGroupUsers(ctx, groupName)
 }
+
+// --- Групповые чаты ---
+
+// BindChat привязывает групповой Telegram чат к группе.
+// Привязать чат может только участник этой группы.
+func (s *Service) BindChat(ctx context.Context, chatID int64, req *models.BindChatRequest) (*models.ChatBinding, error) {
+	exists, err := s.repo.GroupExists(ctx, req.GroupName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrGroupNotExists)
+	}
+
+	userGroup, err := s.repo.GetUserGroup(ctx, req.UserID)
+	if err != nil || userGroup != req.GroupName {
+		return nil, fmt.Errorf("группа \"%s\": %w", req.GroupName, ErrNotGroupMember)
+	}
+
+	return s.repo.BindChat(ctx, chatID, req.GroupName, req.UserID)
+}
+
+// GetChatBinding возвращает привязку чата к группе.
+func (s *Service) GetChatBinding(ctx context.Context, chatID int64) (*models.ChatBinding, error) {
+	return s.repo.GetChatBinding(ctx, chatID)
+}
+
+// UnbindChat удаляет привязку чата к группе.
+func (s *Service) UnbindChat(ctx context.Context, chatID int64) error {
+	return s.repo.UnbindChat(ctx, chatID)
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+func TestUpdateCashbackReplaceClearsLimit(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := NewService(repo)
+
+	rule, err := svc.CreateCashback(context.Background(), createOp("Альфа", "Такси", 5).Create)
+	if err != nil {
+		t.Fatalf("CreateCashback failed: %v", err)
+	}
+
+	// Частичное обновление не трогает лимит
+	if err := svc.UpdateCashback(context.Background(), rule.ID, &models.UpdateCashbackRequest{CashbackPercent: 7}); err != nil {
+		t.Fatalf("UpdateCashback failed: %v", err)
+	}
+	if got := repo.rules[rule.ID]; got.CashbackPercent != 7 || got.MaxAmount != 3000 {
+		t.Errorf("После частичного обновления = %.2f%% до %.2f, ожидалось 7%% до 3000", got.CashbackPercent, got.MaxAmount)
+	}
+
+	// Замена без лимита снимает прежний лимит
+	if err := svc.UpdateCashback(context.Background(), rule.ID, &models.UpdateCashbackRequest{
+		CashbackPercent: 10, Replace: true,
+	}); err != nil {
+		t.Fatalf("UpdateCashback(replace) failed: %v", err)
+	}
+	if got := repo.rules[rule.ID]; got.CashbackPercent != 10 || got.MaxAmount != 0 {
+		t.Errorf("После замены = %.2f%% до %.2f, ожидалось 10%% без лимита", got.CashbackPercent, got.MaxAmount)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+	"github.com/rymax1e/open-cashback-advisor/internal/validator"
+)
+
+// defaultHistoryMonths — период истории и динамики по умолчанию, месяцев.
+const defaultHistoryMonths = 12
+
+// GetGroupHistory возвращает правила группы за период, включая закончившиеся:
+// от последних месяцев к первым, внутри месяца — от самых выгодных.
+func (s *Service) GetGroupHistory(ctx context.Context, req *models.HistoryRequest) ([]models.CashbackRule, error) {
+	rules, _, _, err := s.groupHistory(ctx, req)
+	return rules, err
+}
+
+// GetBankTrend показывает, как менялись процент и лимит банка на категорию
+// по месяцам. Правило относится к месяцу, в котором оно заканчивается.
+func (s *Service) GetBankTrend(ctx context.Context, req *models.HistoryRequest) (*models.BankTrend, error) {
+	var validationErrors validator.ValidationErrors
+
+	if err := validator.ValidateTextField("bank_name", req.BankName, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+	if err := validator.ValidateTextField("category", req.Category, true); err != nil {
+		validationErrors = append(validationErrors, err.(validator.ValidationError))
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, fmt.Errorf("ошибки валидации: %s", validationErrors.Error())
+	}
+
+	rules, from, to, err := s.groupHistory(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	trend := bankTrend(rules, from, to)
+	trend.GroupName = req.GroupName
+	trend.BankName, trend.Category = req.BankName, req.Category
+	// Названия из сохранённых правил, а не в регистре запроса
+	if len(rules) > 0 {
+		trend.BankName, trend.Category = rules[0].BankName, rules[0].Category
+	}
+
+	return trend, nil
+}
+
+// groupHistory проверяет запрос истории и возвращает правила и границы периода.
+func (s *Service) groupHistory(ctx context.Context, req *models.HistoryRequest) ([]models.CashbackRule, time.Time, time.Time, error) {
+	if err := validator.ValidateTextField("group_name", req.GroupName, true); err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	from, to, err := statsPeriod(req.From, req.To, defaultHistoryMonths, time.Now())
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	if err := s.checkGroup(ctx, req.GroupName); err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+
+	rules, err := s.repo.ListGroupHistory(ctx, req.GroupName, from, to.AddDate(0, 1, 0), req.BankName, req.Category)
+	if err != nil {
+		return nil, time.Time{}, time.Time{}, err
+	}
+	if rules == nil {
+		rules = []models.CashbackRule{}
+	}
+
+	return rules, from, to, nil
+}
+
+// bankTrend собирает по месяцам лучший процент и наибольший лимит правил
+// и считает их изменение к предыдущему месяцу, в котором было предложение.
+func bankTrend(rules []models.CashbackRule, from, to time.Time) *models.BankTrend {
+	byMonth := make(map[time.Time]models.TrendPoint)
+	for _, rule := range rules {
+		month := monthStart(rule.MonthYear)
+		p := byMonth[month]
+		p.Rules++
+		p.CashbackPercent = math.Max(p.CashbackPercent, rule.CashbackPercent)
+		p.EffectivePercent = math.Max(p.EffectivePercent, rule.EffectivePercent)
+		p.MaxAmount = math.Max(p.MaxAmount, rule.MaxAmount)
+		byMonth[month] = p
+	}
+
+	trend := &models.BankTrend{From: from, To: to, Points: []models.TrendPoint{}}
+	var first, prev models.TrendPoint
+
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		p := byMonth[month]
+		p.Month = month
+		if p.Rules > 0 {
+			if trend.OfferedMonths > 0 {
+				p.PercentChange = roundCents(p.CashbackPercent - prev.CashbackPercent)
+				p.MaxAmountChange = roundCents(p.MaxAmount - prev.MaxAmount)
+			} else {
+				first = p
+			}
+			prev = p
+			trend.OfferedMonths++
+		}
+		trend.Points = append(trend.Points, p)
+	}
+
+	if trend.OfferedMonths > 0 {
+		trend.PercentChange = roundCents(prev.CashbackPercent - first.CashbackPercent)
+		trend.MaxAmountChange = roundCents(prev.MaxAmount - first.MaxAmount)
+	}
+
+	return trend
+}
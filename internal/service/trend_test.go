@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rymax1e/open-cashback-advisor/internal/models"
+)
+
+// historyRepo возвращает историю правил группы, запоминая фильтры запроса.
+type historyRepo struct {
+	*memoryRepo
+	history        []models.CashbackRule
+	bank, category string
+}
+
+func (r *historyRepo) GroupExists(ctx context.Context, groupName string) (bool, error) {
+	return groupName == "Семья", nil
+}
+
+func (r *historyRepo) ListGroupHistory(ctx context.Context, groupName string, from, to time.Time, bankName, category string) ([]models.CashbackRule, error) {
+	r.bank, r.category = bankName, category
+	return r.history, nil
+}
+
+func TestBankTrend(t *testing.T) {
+	month := func(m time.Month) time.Time { return time.Date(2099, m, 28, 0, 0, 0, 0, time.UTC) }
+	rules := []models.CashbackRule{
+		{CashbackPercent: 7, MaxAmount: 2000, MonthYear: month(time.April)},
+		{CashbackPercent: 5, MaxAmount: 3000, MonthYear: month(time.April)},
+		{CashbackPercent: 5, MaxAmount: 3000, MonthYear: month(time.February)},
+	}
+
+	trend := bankTrend(rules,
+		time.Date(2099, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2099, time.May, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(trend.Points) != 5 || trend.OfferedMonths != 2 {
+		t.Fatalf("bankTrend() = %+v", trend)
+	}
+	april := trend.Points[3]
+	if april.Rules != 2 || april.CashbackPercent != 7 || april.MaxAmount != 3000 ||
+		april.PercentChange != 2 || april.MaxAmountChange != 0 {
+		t.Errorf("bankTrend() апрель = %+v", april)
+	}
+	if trend.Points[0].Rules != 0 || trend.Points[1].PercentChange != 0 {
+		t.Errorf("bankTrend() первые месяцы = %+v", trend.Points[:2])
+	}
+	if trend.PercentChange != 2 || trend.MaxAmountChange != 0 {
+		t.Errorf("bankTrend() итог = %v%%, %v₽", trend.PercentChange, trend.MaxAmountChange)
+	}
+}
+
+func TestGetBankTrend(t *testing.T) {
+	repo := &historyRepo{memoryRepo: newMemoryRepo(), history: []models.CashbackRule{
+		{BankName: "Тинькофф", Category: "Такси", CashbackPercent: 5, MonthYear: time.Now()},
+	}}
+	svc := NewService(repo)
+
+	trend, err := svc.GetBankTrend(context.Background(), &models.HistoryRequest{
+		GroupName: "Семья", BankName: "тинькофф", Category: "такси",
+	})
+	if err != nil {
+		t.Fatalf("GetBankTrend() error = %v", err)
+	}
+	if repo.bank != "тинькофф" || repo.category != "такси" {
+		t.Errorf("GetBankTrend() фильтры = %q, %q", repo.bank, repo.category)
+	}
+	if trend.BankName != "Тинькофф" || trend.Category != "Такси" || len(trend.Points) != defaultHistoryMonths || trend.OfferedMonths != 1 {
+		t.Errorf("GetBankTrend() = %+v", trend)
+	}
+
+	if _, err := svc.GetBankTrend(context.Background(), &models.HistoryRequest{GroupName: "Семья", BankName: "Тинькофф"}); err == nil {
+		t.Error("GetBankTrend() без категории ожидалась ошибка")
+	}
+}
@@ -3,10 +3,17 @@ package validator
 import (
 	"fmt"
 	"math"
+	"regexp"
 	"strings"
 	"time"
 )
 
+// rewardProgramCodePattern — код программы вознаграждения: латиница, цифры, "_"
+var rewardProgramCodePattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// cardLast4Pattern — последние 4 цифры номера карты
+var cardLast4Pattern = regexp.MustCompile(`^[0-9]{4}$`)
+
 // ValidationError представляет ошибку валидации
 type ValidationError struct {
 	Field   string
@@ -106,6 +113,51 @@ func ValidateMaxAmount(amount float64) error {
 	return nil
 }
 
+// ValidatePaymentAmount валидирует сумму покупки, которую просят оплатить
+func ValidatePaymentAmount(amount float64) error {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return ValidationError{
+			Field:   "amount",
+			Message: "недопустимое числовое значение",
+		}
+	}
+
+	if amount <= 0 || amount > 1000000 {
+		return ValidationError{
+			Field:   "amount",
+			Message: fmt.Sprintf("должна быть в диапазоне 0.01 - 1000000.00, получено: %.2f", amount),
+		}
+	}
+
+	return nil
+}
+
+// ValidateActivationDeadline валидирует срок активации категории в формате дд.мм.гггг
+func ValidateActivationDeadline(deadline string) (time.Time, error) {
+	t, err := time.Parse("02.01.2006", deadline)
+	if err != nil {
+		return time.Time{}, ValidationError{
+			Field:   "activation_deadline",
+			Message: fmt.Sprintf("неверный формат даты, ожидается дд.мм.гггг (например, 05.12.2024), получено: %s", deadline),
+		}
+	}
+
+	return t, nil
+}
+
+// ValidateBankCapPeriod валидирует период общего лимита банка
+func ValidateBankCapPeriod(period string) error {
+	switch period {
+	case "month", "quarter":
+		return nil
+	}
+
+	return ValidationError{
+		Field:   "period",
+		Message: fmt.Sprintf("допустимые значения: month, quarter, получено: %s", period),
+	}
+}
+
 // ValidateTextField валидирует текстовые поля
 func ValidateTextField(fieldName, value string, required bool) error {
 	if required && strings.TrimSpace(value) == "" {
@@ -125,6 +177,159 @@ func ValidateTextField(fieldName, value string, required bool) error {
 	return nil
 }
 
+// ValidateRewardProgramCode валидирует код программы вознаграждения
+func ValidateRewardProgramCode(code string) error {
+	if !rewardProgramCodePattern.MatchString(code) {
+		return ValidationError{
+			Field:   "reward_program",
+			Message: fmt.Sprintf("код должен состоять из a-z, 0-9 и _ (до 50 символов), получено: %s", code),
+		}
+	}
+
+	return nil
+}
+
+// ValidateRewardKind валидирует вид вознаграждения
+func ValidateRewardKind(kind string) error {
+	switch kind {
+	case "rubles", "points", "miles":
+		return nil
+	}
+
+	return ValidationError{
+		Field:   "kind",
+		Message: fmt.Sprintf("допустимые значения: rubles, points, miles, получено: %s", kind),
+	}
+}
+
+// ValidateRubleRate валидирует курс единицы вознаграждения к рублю
+func ValidateRubleRate(rate float64) error {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return ValidationError{
+			Field:   "ruble_rate",
+			Message: "недопустимое числовое значение",
+		}
+	}
+
+	if rate < 0 || rate > 1000 {
+		return ValidationError{
+			Field:   "ruble_rate",
+			Message: fmt.Sprintf("должен быть в диапазоне 0 - 1000, получено: %.4f", rate),
+		}
+	}
+
+	return nil
+}
+
+// ValidateCardLast4 валидирует последние 4 цифры номера карты
+func ValidateCardLast4(last4 string) error {
+	if !cardLast4Pattern.MatchString(last4) {
+		return ValidationError{
+			Field:   "last4",
+			Message: fmt.Sprintf("нужны последние 4 цифры номера карты, получено: %s", last4),
+		}
+	}
+
+	return nil
+}
+
+// ValidatePaymentSystem валидирует платёжную систему карты
+func ValidatePaymentSystem(system string) error {
+	switch system {
+	case "mir", "visa", "mastercard", "unionpay":
+		return nil
+	}
+
+	return ValidationError{
+		Field:   "payment_system",
+		Message: fmt.Sprintf("допустимые значения: mir, visa, mastercard, unionpay, получено: %s", system),
+	}
+}
+
+// paymentMethods — допустимые способы оплаты в условиях правила
+var paymentMethods = map[string]bool{"card": true, "sbp": true, "qr": true}
+
+// ValidateConditions валидирует условия правила: минимальную покупку,
+// минимальные траты за месяц, дни недели (1-7) и способы оплаты
+func ValidateConditions(minPurchase, minMonthlySpend float64, weekdays []int, methods []string) ValidationErrors {
+	var errors ValidationErrors
+
+	if err := validateAmountField("conditions.min_purchase", minPurchase); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := validateAmountField("conditions.min_monthly_spend", minMonthlySpend); err != nil {
+		errors = append(errors, *err)
+	}
+
+	for _, day := range weekdays {
+		if err := validateWeekday("conditions.weekdays", day); err != nil {
+			errors = append(errors, *err)
+			break
+		}
+	}
+
+	for _, method := range methods {
+		if err := validatePaymentMethod("conditions.payment_methods", method); err != nil {
+			errors = append(errors, *err)
+			break
+		}
+	}
+
+	return errors
+}
+
+// ValidatePurchaseContext валидирует параметры покупки для подбора кэшбэка.
+// Нулевые значения допустимы и означают, что параметр не указан
+func ValidatePurchaseContext(amount, monthlySpend float64, weekday int, method string) ValidationErrors {
+	var errors ValidationErrors
+
+	if err := validateAmountField("amount", amount); err != nil {
+		errors = append(errors, *err)
+	}
+	if err := validateAmountField("monthly_spend", monthlySpend); err != nil {
+		errors = append(errors, *err)
+	}
+	if weekday != 0 {
+		if err := validateWeekday("weekday", weekday); err != nil {
+			errors = append(errors, *err)
+		}
+	}
+	if method != "" {
+		if err := validatePaymentMethod("payment_method", method); err != nil {
+			errors = append(errors, *err)
+		}
+	}
+
+	return errors
+}
+
+// validateAmountField проверяет неотрицательную сумму в рублях
+func validateAmountField(field string, amount float64) *ValidationError {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return &ValidationError{Field: field, Message: "недопустимое числовое значение"}
+	}
+	if amount < 0 {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("должен быть >= 0.00, получено: %.2f", amount)}
+	}
+	return nil
+}
+
+// validateWeekday проверяет номер дня недели
+func validateWeekday(field string, day int) *ValidationError {
+	if day < 1 || day > 7 {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("день недели от 1 (пн) до 7 (вс), получено: %d", day)}
+	}
+	return nil
+}
+
+// validatePaymentMethod проверяет способ оплаты
+func validatePaymentMethod(field, method string) *ValidationError {
+	if !paymentMethods[method] {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("допустимые значения: card, sbp, qr, получено: %s", method)}
+	}
+	return nil
+}
+
 // RoundToTwoDecimals округляет число до двух знаков после запятой
 func RoundToTwoDecimals(value float64) float64 {
 	return math.Round(value*100) / 100
@@ -89,6 +89,154 @@ func TestValidateMaxAmount(t *testing.T) {
 	}
 }
 
+func TestValidatePaymentAmount(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     float64
+		wantError bool
+	}{
+		{"Valid 1500", 1500.0, false},
+		{"Valid 0.01", 0.01, false},
+		{"Invalid 0", 0.0, true},
+		{"Invalid negative", -100.0, true},
+		{"Invalid too large", 2000000.0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePaymentAmount(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidatePaymentAmount() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateActivationDeadline(t *testing.T) {
+	got, err := ValidateActivationDeadline("05.12.2024")
+	if err != nil || !got.Equal(time.Date(2024, time.December, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ValidateActivationDeadline() = %v, %v", got, err)
+	}
+
+	for _, deadline := range []string{"", "05.12", "2024-12-05", "32.12.2024"} {
+		if _, err := ValidateActivationDeadline(deadline); err == nil {
+			t.Errorf("ValidateActivationDeadline(%q) ожидалась ошибка", deadline)
+		}
+	}
+}
+
+func TestValidateBankCapPeriod(t *testing.T) {
+	for _, period := range []string{"month", "quarter"} {
+		if err := ValidateBankCapPeriod(period); err != nil {
+			t.Errorf("ValidateBankCapPeriod(%q) error = %v", period, err)
+		}
+	}
+	for _, period := range []string{"", "year", "месяц"} {
+		if err := ValidateBankCapPeriod(period); err == nil {
+			t.Errorf("ValidateBankCapPeriod(%q) ожидалась ошибка", period)
+		}
+	}
+}
+
+func TestValidateRubleRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     float64
+		wantError bool
+	}{
+		{"Valid 0", 0.0, false},
+		{"Valid 0.5", 0.5, false},
+		{"Valid 1000", 1000.0, false},
+		{"Invalid negative", -0.1, true},
+		{"Invalid over 1000", 1000.5, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRubleRate(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateRubleRate() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateRewardProgramCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{"Valid rub", "rub", false},
+		{"Valid sber_spasibo", "sber_spasibo", false},
+		{"Invalid empty", "", true},
+		{"Invalid uppercase", "Rub", true},
+		{"Invalid cyrillic", "спасибо", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRewardProgramCode(tt.input)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateRewardProgramCode() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateCard(t *testing.T) {
+	tests := []struct {
+		name      string
+		last4     string
+		system    string
+		wantError bool
+	}{
+		{"Valid mir", "1234", "mir", false},
+		{"Valid visa with zeros", "0042", "visa", false},
+		{"Invalid short last4", "123", "mir", true},
+		{"Invalid letters in last4", "12a4", "mir", true},
+		{"Invalid system", "1234", "amex", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateCardLast4(tt.last4)
+			if err == nil {
+				err = ValidatePaymentSystem(tt.system)
+			}
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateCard() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestValidateConditions(t *testing.T) {
+	tests := []struct {
+		name       string
+		minPurch   float64
+		minMonthly float64
+		weekdays   []int
+		methods    []string
+		wantErrors int
+	}{
+		{"Empty", 0, 0, nil, nil, 0},
+		{"Valid", 1000, 10000, []int{6, 7}, []string{"sbp", "qr"}, 0},
+		{"Negative amounts", -1, -1, nil, nil, 2},
+		{"Invalid weekday", 0, 0, []int{0, 8}, nil, 1},
+		{"Invalid method", 0, 0, nil, []string{"cash"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := ValidateConditions(tt.minPurch, tt.minMonthly, tt.weekdays, tt.methods)
+			if len(errs) != tt.wantErrors {
+				t.Errorf("ValidateConditions() errors = %v, want %d errors", errs, tt.wantErrors)
+			}
+		})
+	}
+}
+
 func TestValidateTextField(t *testing.T) {
 	tests := []struct {
 		name      string
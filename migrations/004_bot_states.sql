@@ -0,0 +1,16 @@
+-- Хранилище состояний диалогов Telegram бота
+-- Позволяет продолжать диалог после перезапуска и запускать несколько реплик бота
+CREATE TABLE IF NOT EXISTS bot_states (
+    user_id BIGINT PRIMARY KEY,
+    state JSONB NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для удаления истёкших состояний
+CREATE INDEX IF NOT EXISTS idx_bot_states_expires_at ON bot_states(expires_at);
+
+-- Комментарии
+COMMENT ON TABLE bot_states IS 'Состояния диалогов Telegram бота';
+COMMENT ON COLUMN bot_states.state IS 'Сериализованное состояние диалога (JSON)';
+COMMENT ON COLUMN bot_states.expires_at IS 'Момент, после которого брошенное состояние удаляется';
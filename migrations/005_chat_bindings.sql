@@ -0,0 +1,14 @@
+-- Привязка групповых Telegram чатов к группам кэшбэков
+CREATE TABLE IF NOT EXISTS chat_bindings (
+    chat_id BIGINT PRIMARY KEY,
+    group_name VARCHAR(100) NOT NULL REFERENCES groups(group_name) ON DELETE CASCADE,
+    bound_by VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индекс для поиска чатов группы
+CREATE INDEX IF NOT EXISTS idx_chat_bindings_group_name ON chat_bindings(group_name);
+
+-- Комментарии
+COMMENT ON TABLE chat_bindings IS 'Привязка групповых Telegram чатов к группам кэшбэков';
+COMMENT ON COLUMN chat_bindings.bound_by IS 'ID пользователя, привязавшего чат';
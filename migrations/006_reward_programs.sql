@@ -0,0 +1,28 @@
+-- Программы вознаграждения: рубли, бонусные баллы, мили
+CREATE TABLE IF NOT EXISTS reward_programs (
+    code VARCHAR(50) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('rubles', 'points', 'miles')),
+    ruble_rate NUMERIC(8,4) NOT NULL DEFAULT 1 CHECK (ruble_rate >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Базовые программы; курсы можно поменять через API
+INSERT INTO reward_programs (code, name, kind, ruble_rate) VALUES
+    ('rub', 'Рубли', 'rubles', 1),
+    ('sber_spasibo', 'СберСпасибо', 'points', 1),
+    ('alfa_points', 'Альфа-баллы', 'points', 1),
+    ('yandex_plus', 'Баллы Плюса', 'points', 1),
+    ('tinkoff_bravo', 'Мили Т-Банка', 'miles', 1),
+    ('aeroflot_miles', 'Мили Аэрофлот Бонус', 'miles', 0.5)
+ON CONFLICT (code) DO NOTHING;
+
+-- Программа, в которой начисляется кэшбэк правила
+ALTER TABLE cashback_rules
+    ADD COLUMN IF NOT EXISTS reward_program VARCHAR(50) NOT NULL DEFAULT 'rub'
+    REFERENCES reward_programs(code);
+
+-- Комментарии
+COMMENT ON TABLE reward_programs IS 'Программы вознаграждения и их курс к рублю';
+COMMENT ON COLUMN reward_programs.ruble_rate IS 'Стоимость одной единицы вознаграждения в рублях';
+COMMENT ON COLUMN cashback_rules.reward_program IS 'Код программы вознаграждения (reward_programs.code)';
@@ -0,0 +1,11 @@
+-- Условия действия правила: минимальная покупка, траты за месяц, дни недели, способ оплаты
+ALTER TABLE cashback_rules
+    ADD COLUMN IF NOT EXISTS conditions JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE cashback_rules
+    DROP CONSTRAINT IF EXISTS cashback_rules_conditions_object;
+ALTER TABLE cashback_rules
+    ADD CONSTRAINT cashback_rules_conditions_object CHECK (jsonb_typeof(conditions) = 'object');
+
+-- Комментарии
+COMMENT ON COLUMN cashback_rules.conditions IS 'Условия правила: min_purchase, min_monthly_spend, weekdays (1-7), payment_methods (card, sbp, qr)';
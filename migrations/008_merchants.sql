@@ -0,0 +1,39 @@
+-- Справочник магазинов и их категорий
+CREATE TABLE IF NOT EXISTS merchants (
+    name VARCHAR(100) PRIMARY KEY,
+    category VARCHAR(100) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индексы для поиска магазина без учёта регистра
+CREATE INDEX IF NOT EXISTS idx_merchants_name_lower ON merchants(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_merchants_category ON merchants(category);
+
+-- Базовые магазины; справочник можно дополнить через API
+INSERT INTO merchants (name, category, aliases) VALUES
+    ('Пятёрочка', 'Супермаркеты', ARRAY['Пятерочка', '5ка']),
+    ('Перекрёсток', 'Супермаркеты', ARRAY['Перекресток']),
+    ('Магнит', 'Супермаркеты', ARRAY[]::TEXT[]),
+    ('ВкусВилл', 'Супермаркеты', ARRAY['Вкус Вилл']),
+    ('Лента', 'Супермаркеты', ARRAY[]::TEXT[]),
+    ('Ozon', 'Маркетплейсы', ARRAY['Озон']),
+    ('Wildberries', 'Маркетплейсы', ARRAY['WB', 'Вайлдберриз']),
+    ('Яндекс Маркет', 'Маркетплейсы', ARRAY['Yandex Market']),
+    ('Яндекс Такси', 'Такси', ARRAY['Yandex Go', 'Яндекс Go']),
+    ('Лукойл', 'АЗС', ARRAY['Lukoil']),
+    ('Вкусно — и точка', 'Фастфуд', ARRAY['Вкусно и точка']),
+    ('Аптека Ригла', 'Аптеки', ARRAY['Ригла'])
+ON CONFLICT (name) DO NOTHING;
+
+-- Предложение конкретного магазина; NULL — правило на всю категорию
+ALTER TABLE cashback_rules
+    ADD COLUMN IF NOT EXISTS merchant VARCHAR(100)
+    REFERENCES merchants(name) ON UPDATE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_cashback_rules_merchant ON cashback_rules(merchant) WHERE merchant IS NOT NULL;
+
+-- Комментарии
+COMMENT ON TABLE merchants IS 'Магазины и категории, к которым относятся их покупки';
+COMMENT ON COLUMN merchants.aliases IS 'Другие написания названия магазина';
+COMMENT ON COLUMN cashback_rules.merchant IS 'Магазин партнёрского предложения (merchants.name)';
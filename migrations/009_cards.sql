@@ -0,0 +1,26 @@
+-- Карты пользователей: у одного банка может быть несколько карт
+CREATE TABLE IF NOT EXISTS cards (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(50) NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    nickname VARCHAR(100) NOT NULL DEFAULT '',
+    last4 CHAR(4) NOT NULL CHECK (last4 ~ '^[0-9]{4}$'),
+    payment_system VARCHAR(20) NOT NULL CHECK (payment_system IN ('mir', 'visa', 'mastercard', 'unionpay')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, bank_name, last4)
+);
+
+CREATE INDEX IF NOT EXISTS idx_cards_user_id ON cards(user_id);
+
+-- Карта, на которую действует правило; NULL — карта не указана
+ALTER TABLE cashback_rules
+    ADD COLUMN IF NOT EXISTS card_id BIGINT
+    REFERENCES cards(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_cashback_rules_card_id ON cashback_rules(card_id) WHERE card_id IS NOT NULL;
+
+-- Комментарии
+COMMENT ON TABLE cards IS 'Банковские карты пользователей';
+COMMENT ON COLUMN cards.last4 IS 'Последние 4 цифры номера карты';
+COMMENT ON COLUMN cards.payment_system IS 'Платёжная система: mir, visa, mastercard, unionpay';
+COMMENT ON COLUMN cashback_rules.card_id IS 'Карта правила (cards.id)';
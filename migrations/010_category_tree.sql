@@ -0,0 +1,47 @@
+-- Дерево категорий: "Фастфуд" ⊂ "Рестораны и кафе" ⊂ "Все покупки"
+CREATE TABLE IF NOT EXISTS categories (
+    name VARCHAR(100) PRIMARY KEY,
+    parent VARCHAR(100) REFERENCES categories(name) ON UPDATE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent IS NULL OR parent <> name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_categories_name_lower ON categories(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories(parent);
+
+-- Корень и основные ветви; дерево можно дополнять через API
+INSERT INTO categories (name, parent) VALUES
+    ('Все покупки', NULL)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO categories (name, parent) VALUES
+    ('Рестораны и кафе', 'Все покупки'),
+    ('Транспорт', 'Все покупки'),
+    ('Супермаркеты', 'Все покупки'),
+    ('Маркетплейсы', 'Все покупки'),
+    ('Здоровье', 'Все покупки'),
+    ('Развлечения', 'Все покупки'),
+    ('Путешествия', 'Все покупки')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO categories (name, parent) VALUES
+    ('Рестораны', 'Рестораны и кафе'),
+    ('Кафе', 'Рестораны и кафе'),
+    ('Фастфуд', 'Рестораны и кафе'),
+    ('Доставка еды', 'Рестораны и кафе'),
+    ('Такси', 'Транспорт'),
+    ('АЗС', 'Транспорт'),
+    ('Каршеринг', 'Транспорт'),
+    ('Общественный транспорт', 'Транспорт'),
+    ('Аптеки', 'Здоровье'),
+    ('Медицина', 'Здоровье'),
+    ('Кино', 'Развлечения'),
+    ('Театры', 'Развлечения'),
+    ('Авиабилеты', 'Путешествия'),
+    ('Ж/д билеты', 'Путешествия'),
+    ('Отели', 'Путешествия')
+ON CONFLICT (name) DO NOTHING;
+
+-- Комментарии
+COMMENT ON TABLE categories IS 'Иерархия категорий для поиска кэшбэка на родительских уровнях';
+COMMENT ON COLUMN categories.parent IS 'Родительская категория; NULL — корень ("Все покупки")';
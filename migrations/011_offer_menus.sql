@@ -0,0 +1,24 @@
+-- Меню категорий банков: каждый месяц клиент выбирает несколько категорий из списка
+CREATE TABLE IF NOT EXISTS offer_menus (
+    bank_name VARCHAR(100) NOT NULL,
+    month_year DATE NOT NULL,
+    pick_count INT NOT NULL CHECK (pick_count > 0),
+    options JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bank_name, month_year)
+);
+
+CREATE INDEX IF NOT EXISTS idx_offer_menus_month_year ON offer_menus(month_year);
+
+DROP TRIGGER IF EXISTS update_offer_menus_updated_at ON offer_menus;
+CREATE TRIGGER update_offer_menus_updated_at
+    BEFORE UPDATE ON offer_menus
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Комментарии
+COMMENT ON TABLE offer_menus IS 'Меню категорий банков на месяц';
+COMMENT ON COLUMN offer_menus.month_year IS 'Первое число месяца меню';
+COMMENT ON COLUMN offer_menus.pick_count IS 'Сколько категорий выбирает клиент';
+COMMENT ON COLUMN offer_menus.options IS 'Категории меню: [{category, cashback_percent, max_amount}]';
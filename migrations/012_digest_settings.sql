@@ -0,0 +1,22 @@
+-- Расписание ежемесячной сводки группы
+CREATE TABLE IF NOT EXISTS digest_settings (
+    group_name VARCHAR(100) PRIMARY KEY REFERENCES groups(group_name) ON DELETE CASCADE,
+    day SMALLINT NOT NULL DEFAULT 0 CHECK (day BETWEEN 0 AND 28),
+    hour SMALLINT NOT NULL DEFAULT 19 CHECK (hour BETWEEN 0 AND 23),
+    minute SMALLINT NOT NULL DEFAULT 0 CHECK (minute BETWEEN 0 AND 59),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    last_sent_month DATE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_digest_settings_updated_at ON digest_settings;
+CREATE TRIGGER update_digest_settings_updated_at
+    BEFORE UPDATE ON digest_settings
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Комментарии
+COMMENT ON TABLE digest_settings IS 'Расписание ежемесячной сводки группы; группы без записи получают сводку по умолчанию';
+COMMENT ON COLUMN digest_settings.day IS 'День месяца отправки (1-28); 0 — последний день месяца';
+COMMENT ON COLUMN digest_settings.last_sent_month IS 'Первое число месяца последней отправленной сводки';
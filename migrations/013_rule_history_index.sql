@@ -0,0 +1,4 @@
+-- История предложений банка на категорию: закончившиеся правила не удаляются
+-- и выбираются по банку и категории без учёта регистра за период
+CREATE INDEX IF NOT EXISTS idx_cashback_rules_history
+    ON cashback_rules (LOWER(bank_name), LOWER(category), month_year);
@@ -0,0 +1,29 @@
+-- Просьбы оплатить покупку картой другого участника и учёт долгов
+CREATE TABLE IF NOT EXISTS payment_requests (
+    id BIGSERIAL PRIMARY KEY,
+    group_name VARCHAR(100) NOT NULL REFERENCES groups(group_name) ON DELETE CASCADE,
+    rule_id BIGINT REFERENCES cashback_rules(id) ON DELETE SET NULL,
+    requester_id VARCHAR(100) NOT NULL,
+    requester_name VARCHAR(255) NOT NULL,
+    payer_id VARCHAR(100) NOT NULL,
+    payer_name VARCHAR(255) NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    category VARCHAR(200) NOT NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'settled')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    settled_at TIMESTAMP WITH TIME ZONE,
+    CHECK (requester_id <> payer_id)
+);
+
+-- Индекс для расчёта балансов группы
+CREATE INDEX IF NOT EXISTS idx_payment_requests_group_status
+    ON payment_requests(group_name, status);
+
+-- Комментарии
+COMMENT ON TABLE payment_requests IS 'Просьбы оплатить покупку картой другого участника группы';
+COMMENT ON COLUMN payment_requests.requester_id IS 'Кто просит оплатить и потом возвращает деньги';
+COMMENT ON COLUMN payment_requests.payer_id IS 'Владелец карты, который платит';
+COMMENT ON COLUMN payment_requests.status IS 'pending — ждёт ответа, accepted — оплачено и учтено в долгах, declined — отклонено, settled — деньги возвращены';
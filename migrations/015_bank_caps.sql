@@ -0,0 +1,40 @@
+-- Общие лимиты кэшбэка банка на все категории и журнал покупок
+CREATE TABLE IF NOT EXISTS bank_caps (
+    user_id VARCHAR(100) NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    period VARCHAR(10) NOT NULL DEFAULT 'month' CHECK (period IN ('month', 'quarter')),
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, bank_name, period)
+);
+
+DROP TRIGGER IF EXISTS update_bank_caps_updated_at ON bank_caps;
+CREATE TRIGGER update_bank_caps_updated_at
+    BEFORE UPDATE ON bank_caps
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS spends (
+    id BIGSERIAL PRIMARY KEY,
+    group_name VARCHAR(100) NOT NULL REFERENCES groups(group_name) ON DELETE CASCADE,
+    user_id VARCHAR(100) NOT NULL,
+    bank_name VARCHAR(100) NOT NULL,
+    category VARCHAR(200) NOT NULL,
+    rule_id BIGINT REFERENCES cashback_rules(id) ON DELETE SET NULL,
+    payment_request_id BIGINT REFERENCES payment_requests(id) ON DELETE SET NULL,
+    amount NUMERIC(12, 2) NOT NULL CHECK (amount > 0),
+    cashback NUMERIC(12, 2) NOT NULL DEFAULT 0 CHECK (cashback >= 0),
+    spent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Индексы для расчёта заработанного кэшбэка по банку за период и по правилу
+CREATE INDEX IF NOT EXISTS idx_spends_user_bank ON spends(user_id, LOWER(bank_name), spent_at);
+CREATE INDEX IF NOT EXISTS idx_spends_rule ON spends(rule_id);
+
+-- Комментарии
+COMMENT ON TABLE bank_caps IS 'Общий лимит кэшбэка банка на все категории за период, отдельно от max_amount правил';
+COMMENT ON COLUMN bank_caps.period IS 'month — календарный месяц, quarter — календарный квартал';
+COMMENT ON TABLE spends IS 'Журнал покупок по картам участников и начисленного по ним кэшбэка';
+COMMENT ON COLUMN spends.user_id IS 'Владелец карты, по которой прошла покупка';
+COMMENT ON COLUMN spends.cashback IS 'Кэшбэк в рублях с учётом max_amount правила и общего лимита банка';
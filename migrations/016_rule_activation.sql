@@ -0,0 +1,17 @@
+-- Активация категорий в приложении банка: без неё кэшбэк не начисляется
+ALTER TABLE cashback_rules
+    ADD COLUMN IF NOT EXISTS activation_required BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS activation_deadline DATE,
+    ADD COLUMN IF NOT EXISTS activated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS activation_reminded_at TIMESTAMP WITH TIME ZONE;
+
+-- Индекс для поиска правил, о которых пора напомнить
+CREATE INDEX IF NOT EXISTS idx_cashback_rules_activation_due
+    ON cashback_rules(activation_deadline)
+    WHERE activation_required AND activated_at IS NULL AND activation_reminded_at IS NULL;
+
+-- Комментарии
+COMMENT ON COLUMN cashback_rules.activation_required IS 'Категорию нужно активировать в приложении банка';
+COMMENT ON COLUMN cashback_rules.activation_deadline IS 'Последний день активации; NULL — срок не указан';
+COMMENT ON COLUMN cashback_rules.activated_at IS 'Когда владелец отметил активацию; NULL — ещё не активирована';
+COMMENT ON COLUMN cashback_rules.activation_reminded_at IS 'Когда отправлено напоминание об активации';